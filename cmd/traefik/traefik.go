@@ -250,6 +250,8 @@ func switchRouter(routerFactory *server.RouterFactory, acmeProviders []*acme.Pro
 		}
 		serverEntryPointsTCP.Switch(routers)
 		serverEntryPointsUDP.Switch(udpRouters)
+
+		routerFactory.CloseUnusedClients()
 	}
 }
 
@@ -33,15 +33,26 @@ type serviceInfoRepresentation struct {
 	ServerStatus map[string]string `json:"serverStatus,omitempty"`
 }
 
+type tcpServiceInfoRepresentation struct {
+	*runtime.TCPServiceInfo
+	ServerStatus map[string]string `json:"serverStatus,omitempty"`
+}
+
+type udpServiceInfoRepresentation struct {
+	*runtime.UDPServiceInfo
+	ServerStatus map[string]string `json:"serverStatus,omitempty"`
+}
+
 // RunTimeRepresentation is the configuration information exposed by the API handler.
 type RunTimeRepresentation struct {
-	Routers     map[string]*runtime.RouterInfo        `json:"routers,omitempty"`
-	Middlewares map[string]*runtime.MiddlewareInfo    `json:"middlewares,omitempty"`
-	Services    map[string]*serviceInfoRepresentation `json:"services,omitempty"`
-	TCPRouters  map[string]*runtime.TCPRouterInfo     `json:"tcpRouters,omitempty"`
-	TCPServices map[string]*runtime.TCPServiceInfo    `json:"tcpServices,omitempty"`
-	UDPRouters  map[string]*runtime.UDPRouterInfo     `json:"udpRouters,omitempty"`
-	UDPServices map[string]*runtime.UDPServiceInfo    `json:"udpServices,omitempty"`
+	Routers        map[string]*runtime.RouterInfo           `json:"routers,omitempty"`
+	Middlewares    map[string]*runtime.MiddlewareInfo       `json:"middlewares,omitempty"`
+	Services       map[string]*serviceInfoRepresentation    `json:"services,omitempty"`
+	TCPRouters     map[string]*runtime.TCPRouterInfo        `json:"tcpRouters,omitempty"`
+	TCPMiddlewares map[string]*runtime.TCPMiddlewareInfo    `json:"tcpMiddlewares,omitempty"`
+	TCPServices    map[string]*tcpServiceInfoRepresentation `json:"tcpServices,omitempty"`
+	UDPRouters     map[string]*runtime.UDPRouterInfo        `json:"udpRouters,omitempty"`
+	UDPServices    map[string]*udpServiceInfoRepresentation `json:"udpServices,omitempty"`
 }
 
 // Handler serves the configuration and status of Traefik on API endpoints.
@@ -106,6 +117,8 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/tcp/routers/{routerID}").HandlerFunc(h.getTCPRouter)
 	router.Methods(http.MethodGet).Path("/api/tcp/services").HandlerFunc(h.getTCPServices)
 	router.Methods(http.MethodGet).Path("/api/tcp/services/{serviceID}").HandlerFunc(h.getTCPService)
+	router.Methods(http.MethodGet).Path("/api/tcp/middlewares").HandlerFunc(h.getTCPMiddlewares)
+	router.Methods(http.MethodGet).Path("/api/tcp/middlewares/{middlewareID}").HandlerFunc(h.getTCPMiddleware)
 
 	router.Methods(http.MethodGet).Path("/api/udp/routers").HandlerFunc(h.getUDPRouters)
 	router.Methods(http.MethodGet).Path("/api/udp/routers/{routerID}").HandlerFunc(h.getUDPRouter)
@@ -130,14 +143,31 @@ func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.R
 		}
 	}
 
+	tcpSiRepr := make(map[string]*tcpServiceInfoRepresentation, len(h.runtimeConfiguration.TCPServices))
+	for k, v := range h.runtimeConfiguration.TCPServices {
+		tcpSiRepr[k] = &tcpServiceInfoRepresentation{
+			TCPServiceInfo: v,
+			ServerStatus:   v.GetAllStatus(),
+		}
+	}
+
+	udpSiRepr := make(map[string]*udpServiceInfoRepresentation, len(h.runtimeConfiguration.UDPServices))
+	for k, v := range h.runtimeConfiguration.UDPServices {
+		udpSiRepr[k] = &udpServiceInfoRepresentation{
+			UDPServiceInfo: v,
+			ServerStatus:   v.GetAllStatus(),
+		}
+	}
+
 	result := RunTimeRepresentation{
-		Routers:     h.runtimeConfiguration.Routers,
-		Middlewares: h.runtimeConfiguration.Middlewares,
-		Services:    siRepr,
-		TCPRouters:  h.runtimeConfiguration.TCPRouters,
-		TCPServices: h.runtimeConfiguration.TCPServices,
-		UDPRouters:  h.runtimeConfiguration.UDPRouters,
-		UDPServices: h.runtimeConfiguration.UDPServices,
+		Routers:        h.runtimeConfiguration.Routers,
+		Middlewares:    h.runtimeConfiguration.Middlewares,
+		Services:       siRepr,
+		TCPRouters:     h.runtimeConfiguration.TCPRouters,
+		TCPMiddlewares: h.runtimeConfiguration.TCPMiddlewares,
+		TCPServices:    tcpSiRepr,
+		UDPRouters:     h.runtimeConfiguration.UDPRouters,
+		UDPServices:    udpSiRepr,
 	}
 
 	rw.Header().Set("Content-Type", "application/json")
@@ -30,6 +30,7 @@ func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresen
 type serviceRepresentation struct {
 	*runtime.ServiceInfo
 	ServerStatus map[string]string `json:"serverStatus,omitempty"`
+	HealthStatus string            `json:"healthStatus,omitempty"`
 	Name         string            `json:"name,omitempty"`
 	Provider     string            `json:"provider,omitempty"`
 	Type         string            `json:"type,omitempty"`
@@ -41,6 +42,7 @@ func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepre
 		Name:         name,
 		Provider:     getProviderName(name),
 		ServerStatus: si.GetAllStatus(),
+		HealthStatus: si.GetHealthStatus(),
 		Type:         strings.ToLower(extractType(si.Service)),
 	}
 }
@@ -527,6 +527,7 @@ func TestHandler_HTTP(t *testing.T) {
 							UsedBy: []string{"foo@myprovider", "test@myprovider"},
 						}
 						si.UpdateServerStatus("http://127.0.0.1", "UP")
+						si.UpdateHealthStatus("UP")
 						return si
 					}(),
 				},
@@ -433,18 +433,22 @@ func TestHandler_UDP(t *testing.T) {
 			path: "/api/udp/services/bar@myprovider",
 			conf: runtime.Configuration{
 				UDPServices: map[string]*runtime.UDPServiceInfo{
-					"bar@myprovider": {
-						UDPService: &dynamic.UDPService{
-							LoadBalancer: &dynamic.UDPServersLoadBalancer{
-								Servers: []dynamic.UDPServer{
-									{
-										Address: "127.0.0.1:2345",
+					"bar@myprovider": func() *runtime.UDPServiceInfo {
+						si := &runtime.UDPServiceInfo{
+							UDPService: &dynamic.UDPService{
+								LoadBalancer: &dynamic.UDPServersLoadBalancer{
+									Servers: []dynamic.UDPServer{
+										{
+											Address: "127.0.0.1:2345",
+										},
 									},
 								},
 							},
-						},
-						UsedBy: []string{"foo@myprovider", "test@myprovider"},
-					},
+							UsedBy: []string{"foo@myprovider", "test@myprovider"},
+						}
+						si.UpdateServerStatus("127.0.0.1:2345", "UP")
+						return si
+					}(),
 				},
 			},
 			expected: expected{
@@ -2,7 +2,9 @@ package dynamic
 
 import (
 	"reflect"
+	"time"
 
+	"github.com/containous/traefik/v2/pkg/tls"
 	"github.com/containous/traefik/v2/pkg/types"
 )
 
@@ -10,9 +12,10 @@ import (
 
 // HTTPConfiguration contains all the HTTP configuration parameters.
 type HTTPConfiguration struct {
-	Routers     map[string]*Router     `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty"`
-	Middlewares map[string]*Middleware `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
-	Services    map[string]*Service    `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty"`
+	Routers           map[string]*Router           `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty"`
+	Middlewares       map[string]*Middleware       `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	Services          map[string]*Service          `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty"`
+	ServersTransports map[string]*ServersTransport `json:"serversTransports,omitempty" toml:"serversTransports,omitempty" yaml:"serversTransports,omitempty" label:"-"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -22,6 +25,7 @@ type Service struct {
 	LoadBalancer *ServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty"`
 	Weighted     *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-"`
 	Mirroring    *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-"`
+	Failover     *Failover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -49,8 +53,15 @@ type RouterTLSConfig struct {
 
 // Mirroring holds the Mirroring configuration.
 type Mirroring struct {
-	Service string          `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
-	Mirrors []MirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty"`
+	Service     string          `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
+	MaxBodySize *int64          `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty"`
+	Mirrors     []MirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty"`
+}
+
+// SetDefaults Default values for a Mirroring.
+func (m *Mirroring) SetDefaults() {
+	var defaultMaxBodySize int64 = -1
+	m.MaxBodySize = &defaultMaxBodySize
 }
 
 // +k8s:deepcopy-gen=true
@@ -63,6 +74,14 @@ type MirrorService struct {
 
 // +k8s:deepcopy-gen=true
 
+// Failover holds the Failover configuration.
+type Failover struct {
+	Service  string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
+	Fallback string `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // WeightedRoundRobin is a weighted round robin load-balancer of services.
 type WeightedRoundRobin struct {
 	Services []WRRService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty"`
@@ -99,15 +118,30 @@ type Cookie struct {
 	HTTPOnly bool   `json:"httpOnly,omitempty" toml:"httpOnly,omitempty" yaml:"httpOnly,omitempty"`
 }
 
+// Load-balancing strategies of a ServersLoadBalancer.
+const (
+	RoundRobinStrategy        = "RoundRobin"
+	LeastConnectionsStrategy  = "LeastConnections"
+	PowerOfTwoChoicesStrategy = "PowerOfTwoChoices"
+	PeakEWMAStrategy          = "PeakEWMA"
+	ConsistentHashStrategy    = "ConsistentHash"
+)
+
 // +k8s:deepcopy-gen=true
 
 // ServersLoadBalancer holds the ServersLoadBalancer configuration.
 type ServersLoadBalancer struct {
+	Strategy           string              `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty"`
+	ConsistentHash     *ConsistentHash     `json:"consistentHash,omitempty" toml:"consistentHash,omitempty" yaml:"consistentHash,omitempty"`
 	Sticky             *Sticky             `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty"`
 	Servers            []Server            `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
 	HealthCheck        *HealthCheck        `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	OutlierDetection   *OutlierDetection   `json:"outlierDetection,omitempty" toml:"outlierDetection,omitempty" yaml:"outlierDetection,omitempty" label:"allowEmpty"`
+	Hedging            *Hedging            `json:"hedging,omitempty" toml:"hedging,omitempty" yaml:"hedging,omitempty" label:"allowEmpty"`
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty"`
+	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty"`
+	ProxyProtocol      *ProxyProtocol      `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -135,6 +169,15 @@ func (l *ServersLoadBalancer) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// ConsistentHash holds the configuration of the consistent hashing strategy.
+// The requests are hashed on the value of the Header, or else of the Cookie, or else on the client IP.
+type ConsistentHash struct {
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty"`
+	Cookie string `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ResponseForwarding holds configuration for the forward of the response.
 type ResponseForwarding struct {
 	FlushInterval string `json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty"`
@@ -145,6 +188,7 @@ type ResponseForwarding struct {
 // Server holds the server configuration.
 type Server struct {
 	URL    string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty" label:"-"`
+	Weight *int   `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty"`
 	Scheme string `toml:"-" json:"-" yaml:"-"`
 	Port   string `toml:"-" json:"-" yaml:"-"`
 }
@@ -158,6 +202,7 @@ func (s *Server) SetDefaults() {
 
 // HealthCheck holds the HealthCheck configuration.
 type HealthCheck struct {
+	Mode   string `json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty"`
 	Scheme string `json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty"`
 	Path   string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty"`
 	Port   int    `json:"port,omitempty" toml:"port,omitempty,omitzero" yaml:"port,omitempty"`
@@ -168,6 +213,14 @@ type HealthCheck struct {
 	Hostname        string            `json:"hostname,omitempty" toml:"hostname,omitempty" yaml:"hostname,omitempty"`
 	FollowRedirects *bool             `json:"followRedirects" toml:"followRedirects" yaml:"followRedirects"`
 	Headers         map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	// ExpectedStatus are the status code ranges (e.g. "200-299") of a healthy response, instead of 2XX and 3XX.
+	ExpectedStatus []string `json:"expectedStatus,omitempty" toml:"expectedStatus,omitempty" yaml:"expectedStatus,omitempty"`
+	// ExpectedBody is a regular expression the body of a healthy response must match.
+	ExpectedBody string `json:"expectedBody,omitempty" toml:"expectedBody,omitempty" yaml:"expectedBody,omitempty"`
+	// HealthyThreshold is the number of consecutive successful checks before a server is returned to the load-balancer.
+	HealthyThreshold int `json:"healthyThreshold,omitempty" toml:"healthyThreshold,omitempty" yaml:"healthyThreshold,omitempty"`
+	// UnhealthyThreshold is the number of consecutive failed checks before a server is removed from the load-balancer.
+	UnhealthyThreshold int `json:"unhealthyThreshold,omitempty" toml:"unhealthyThreshold,omitempty" yaml:"unhealthyThreshold,omitempty"`
 }
 
 // SetDefaults Default values for a HealthCheck.
@@ -175,3 +228,73 @@ func (h *HealthCheck) SetDefaults() {
 	fr := true
 	h.FollowRedirects = &fr
 }
+
+// +k8s:deepcopy-gen=true
+
+// OutlierDetection holds the passive health check configuration.
+// A server is ejected from the load-balancer after ConsecutiveErrors 5XX responses (or connection errors),
+// for BaseEjectionTime, doubled each time the server is ejected again, up to MaxEjectionTime.
+type OutlierDetection struct {
+	ConsecutiveErrors int            `json:"consecutiveErrors,omitempty" toml:"consecutiveErrors,omitempty" yaml:"consecutiveErrors,omitempty"`
+	BaseEjectionTime  types.Duration `json:"baseEjectionTime,omitempty" toml:"baseEjectionTime,omitempty" yaml:"baseEjectionTime,omitempty"`
+	MaxEjectionTime   types.Duration `json:"maxEjectionTime,omitempty" toml:"maxEjectionTime,omitempty" yaml:"maxEjectionTime,omitempty"`
+	// MaxEjectionPercent is the maximum percentage of the servers that can be ejected at the same time.
+	// At least one server can always be ejected.
+	MaxEjectionPercent int `json:"maxEjectionPercent,omitempty" toml:"maxEjectionPercent,omitempty" yaml:"maxEjectionPercent,omitempty"`
+}
+
+// SetDefaults Default values for an OutlierDetection.
+func (o *OutlierDetection) SetDefaults() {
+	o.ConsecutiveErrors = 5
+	o.BaseEjectionTime = types.Duration(30 * time.Second)
+	o.MaxEjectionTime = types.Duration(300 * time.Second)
+	o.MaxEjectionPercent = 10
+}
+
+// +k8s:deepcopy-gen=true
+
+// Hedging holds the request hedging configuration.
+// When the server chosen for an idempotent request without body has not answered within Delay,
+// the request is also sent to another server, and the first response received is used.
+type Hedging struct {
+	Delay types.Duration `json:"delay,omitempty" toml:"delay,omitempty" yaml:"delay,omitempty"`
+}
+
+// SetDefaults Default values for a Hedging.
+func (h *Hedging) SetDefaults() {
+	h.Delay = types.Duration(100 * time.Millisecond)
+}
+
+// +k8s:deepcopy-gen=true
+
+// ServersTransport options to configure communication between Traefik and the servers.
+type ServersTransport struct {
+	ServerName          string              `json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
+	InsecureSkipVerify  bool                `json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+	RootCAs             []tls.FileOrContent `json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
+	Certificates        tls.Certificates    `json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty"`
+	MaxIdleConnsPerHost int                 `json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty"`
+	ForwardingTimeouts  *ForwardingTimeouts `json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty"`
+	DisableHTTP2        bool                `json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty"`
+}
+
+// SetDefaults sets the default values.
+func (t *ServersTransport) SetDefaults() {
+	t.ForwardingTimeouts = &ForwardingTimeouts{}
+	t.ForwardingTimeouts.SetDefaults()
+}
+
+// +k8s:deepcopy-gen=true
+
+// ForwardingTimeouts contains timeout configurations for forwarding requests to the backend servers.
+type ForwardingTimeouts struct {
+	DialTimeout           types.Duration `json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty"`
+	ResponseHeaderTimeout types.Duration `json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty"`
+	IdleConnTimeout       types.Duration `json:"idleConnTimeout,omitempty" toml:"idleConnTimeout,omitempty" yaml:"idleConnTimeout,omitempty"`
+}
+
+// SetDefaults sets the default values.
+func (f *ForwardingTimeouts) SetDefaults() {
+	f.DialTimeout = types.Duration(30 * time.Second)
+	f.IdleConnTimeout = types.Duration(90 * time.Second)
+}
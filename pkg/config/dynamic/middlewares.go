@@ -38,6 +38,10 @@ type Middleware struct {
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty"`
+	Cache             *Cache             `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty"`
+	JWT               *JWT               `json:"jwt,omitempty" toml:"jwt,omitempty" yaml:"jwt,omitempty"`
+	OIDC              *OIDC              `json:"oidc,omitempty" toml:"oidc,omitempty" yaml:"oidc,omitempty"`
+	RequestID         *RequestID         `json:"requestId,omitempty" toml:"requestId,omitempty" yaml:"requestId,omitempty" label:"allowEmpty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -95,6 +99,26 @@ type Buffering struct {
 
 // +k8s:deepcopy-gen=true
 
+// Cache holds the HTTP response cache configuration.
+type Cache struct {
+	// MaxSize is the maximum size, in bytes, of the responses kept in memory.
+	// The least recently used responses are evicted when it is reached.
+	MaxSize int64 `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+	// DefaultTTL is how long the responses without freshness information (Cache-Control max-age or Expires) are cached.
+	// Such responses are not cached when it is zero.
+	DefaultTTL types.Duration `json:"defaultTTL,omitempty" toml:"defaultTTL,omitempty" yaml:"defaultTTL,omitempty"`
+	// StaleWhileRevalidate is how long a stale response can still be served while it is revalidated in the background,
+	// unless the response defines it with the stale-while-revalidate Cache-Control directive.
+	StaleWhileRevalidate types.Duration `json:"staleWhileRevalidate,omitempty" toml:"staleWhileRevalidate,omitempty" yaml:"staleWhileRevalidate,omitempty"`
+}
+
+// SetDefaults Default values for a Cache.
+func (c *Cache) SetDefaults() {
+	c.MaxSize = 64 * 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
 // Chain holds a chain of middlewares
 type Chain struct {
 	Middlewares []string `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
@@ -112,6 +136,12 @@ type CircuitBreaker struct {
 // Compress holds the compress configuration.
 type Compress struct {
 	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty" toml:"excludedContentTypes,omitempty" yaml:"excludedContentTypes,omitempty" export:"true"`
+	IncludedContentTypes []string `json:"includedContentTypes,omitempty" toml:"includedContentTypes,omitempty" yaml:"includedContentTypes,omitempty" export:"true"`
+	MinResponseBodyBytes int      `json:"minResponseBodyBytes,omitempty" toml:"minResponseBodyBytes,omitempty" yaml:"minResponseBodyBytes,omitempty" export:"true"`
+	Encodings            []string `json:"encodings,omitempty" toml:"encodings,omitempty" yaml:"encodings,omitempty" export:"true"`
+	GzipLevel            int      `json:"gzipLevel,omitempty" toml:"gzipLevel,omitempty" yaml:"gzipLevel,omitempty" export:"true"`
+	BrotliLevel          int      `json:"brotliLevel,omitempty" toml:"brotliLevel,omitempty" yaml:"brotliLevel,omitempty" export:"true"`
+	ZstdLevel            int      `json:"zstdLevel,omitempty" toml:"zstdLevel,omitempty" yaml:"zstdLevel,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -142,6 +172,32 @@ type ForwardAuth struct {
 	TLS                 *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
 	TrustForwardHeader  bool       `json:"trustForwardHeader,omitempty" toml:"trustForwardHeader,omitempty" yaml:"trustForwardHeader,omitempty" export:"true"`
 	AuthResponseHeaders []string   `json:"authResponseHeaders,omitempty" toml:"authResponseHeaders,omitempty" yaml:"authResponseHeaders,omitempty"`
+	// AuthRequestHeaders are the headers of the request copied to the authentication request.
+	// All the headers are copied when it is empty.
+	AuthRequestHeaders []string `json:"authRequestHeaders,omitempty" toml:"authRequestHeaders,omitempty" yaml:"authRequestHeaders,omitempty" export:"true"`
+	// ForwardBody defines whether the body of the request is sent to the authentication server.
+	ForwardBody bool `json:"forwardBody,omitempty" toml:"forwardBody,omitempty" yaml:"forwardBody,omitempty" export:"true"`
+	// MaxBodySize is the maximum size, in bytes, of the bodies sent to the authentication server (default: 1MiB).
+	// The requests with a larger body are rejected. A negative value means no limit.
+	MaxBodySize *int64            `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+	Cache       *ForwardAuthCache `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ForwardAuthCache holds the configuration of the cache of the forward authentication decisions.
+// Only the successful authentications are cached.
+type ForwardAuthCache struct {
+	// TTL is how long a decision is cached.
+	TTL types.Duration `json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+	// KeyHeaders are the headers of the request the decisions are cached for, such as the headers holding the credentials.
+	KeyHeaders []string `json:"keyHeaders,omitempty" toml:"keyHeaders,omitempty" yaml:"keyHeaders,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a ForwardAuthCache.
+func (f *ForwardAuthCache) SetDefaults() {
+	f.TTL = types.Duration(time.Minute)
+	f.KeyHeaders = []string{"Authorization", "Cookie"}
 }
 
 // +k8s:deepcopy-gen=true
@@ -159,6 +215,10 @@ type Headers struct {
 	AccessControlAllowMethods []string `json:"accessControlAllowMethods,omitempty" toml:"accessControlAllowMethods,omitempty" yaml:"accessControlAllowMethods,omitempty"`
 	// AccessControlAllowOrigin Can be "origin-list-or-null" or "*". From (https://www.w3.org/TR/cors/#access-control-allow-origin-response-header)
 	AccessControlAllowOrigin string `json:"accessControlAllowOrigin,omitempty" toml:"accessControlAllowOrigin,omitempty" yaml:"accessControlAllowOrigin,omitempty"`
+	// AccessControlAllowOriginList is the list of allowed origins, which can contain "*" or wildcard subdomains (e.g. "https://*.example.com").
+	AccessControlAllowOriginList []string `json:"accessControlAllowOriginList,omitempty" toml:"accessControlAllowOriginList,omitempty" yaml:"accessControlAllowOriginList,omitempty"`
+	// AccessControlAllowOriginListRegex is the list of allowed origins written as regular expressions.
+	AccessControlAllowOriginListRegex []string `json:"accessControlAllowOriginListRegex,omitempty" toml:"accessControlAllowOriginListRegex,omitempty" yaml:"accessControlAllowOriginListRegex,omitempty"`
 	// AccessControlExposeHeaders sets valid headers for the response.
 	AccessControlExposeHeaders []string `json:"accessControlExposeHeaders,omitempty" toml:"accessControlExposeHeaders,omitempty" yaml:"accessControlExposeHeaders,omitempty"`
 	// AccessControlMaxAge sets the time that a preflight request may be cached.
@@ -201,6 +261,8 @@ func (h *Headers) HasCorsHeadersDefined() bool {
 		len(h.AccessControlAllowHeaders) != 0 ||
 		len(h.AccessControlAllowMethods) != 0 ||
 		h.AccessControlAllowOrigin != "" ||
+		len(h.AccessControlAllowOriginList) != 0 ||
+		len(h.AccessControlAllowOriginListRegex) != 0 ||
 		len(h.AccessControlExposeHeaders) != 0 ||
 		h.AccessControlMaxAge != 0 ||
 		h.AddVaryHeader)
@@ -293,6 +355,55 @@ func (i *InFlightReq) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// JWT holds the JSON Web Token validation configuration.
+// The tokens are read from the Authorization header of the requests, as bearer tokens.
+type JWT struct {
+	// Keys are the PEM encoded public keys (or certificates) the tokens can be signed with, or the paths to the files containing them.
+	Keys []string `json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty"`
+	// JWKSURL is the URL of a JSON Web Key Set, containing the keys the tokens can be signed with.
+	JWKSURL string `json:"jwksURL,omitempty" toml:"jwksURL,omitempty" yaml:"jwksURL,omitempty"`
+	// JWKSRefreshInterval is how long the keys fetched from the JWKSURL are cached before being fetched again.
+	JWKSRefreshInterval types.Duration `json:"jwksRefreshInterval,omitempty" toml:"jwksRefreshInterval,omitempty" yaml:"jwksRefreshInterval,omitempty" export:"true"`
+	Issuer              string         `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty" export:"true"`
+	Audience            string         `json:"audience,omitempty" toml:"audience,omitempty" yaml:"audience,omitempty" export:"true"`
+	// RequiredClaims are the claims the tokens must contain.
+	RequiredClaims []string `json:"requiredClaims,omitempty" toml:"requiredClaims,omitempty" yaml:"requiredClaims,omitempty" export:"true"`
+	// ForwardClaims maps the names of the request headers to set to the names of the claims whose value they are set to.
+	ForwardClaims map[string]string `json:"forwardClaims,omitempty" toml:"forwardClaims,omitempty" yaml:"forwardClaims,omitempty" export:"true"`
+	RemoveHeader  bool              `json:"removeHeader,omitempty" toml:"removeHeader,omitempty" yaml:"removeHeader,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDC holds the OpenID Connect authentication configuration.
+// The users are authenticated with the authorization code flow, and their session is kept in an encrypted cookie.
+type OIDC struct {
+	// Issuer is the URL of the OpenID provider, its configuration being discovered from Issuer/.well-known/openid-configuration.
+	Issuer       string   `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty" export:"true"`
+	ClientID     string   `json:"clientID,omitempty" toml:"clientID,omitempty" yaml:"clientID,omitempty"`
+	ClientSecret string   `json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty" export:"true"`
+	// RedirectPath is the path of the URL the provider redirects the users to once authenticated.
+	RedirectPath string `json:"redirectPath,omitempty" toml:"redirectPath,omitempty" yaml:"redirectPath,omitempty" export:"true"`
+	// LogoutPath is the path of the URL ending the session of the users, if any.
+	LogoutPath            string `json:"logoutPath,omitempty" toml:"logoutPath,omitempty" yaml:"logoutPath,omitempty" export:"true"`
+	PostLogoutRedirectURL string `json:"postLogoutRedirectURL,omitempty" toml:"postLogoutRedirectURL,omitempty" yaml:"postLogoutRedirectURL,omitempty" export:"true"`
+	// SessionSecret is the secret the session cookies are encrypted with.
+	SessionSecret     string `json:"sessionSecret,omitempty" toml:"sessionSecret,omitempty" yaml:"sessionSecret,omitempty"`
+	SessionCookieName string `json:"sessionCookieName,omitempty" toml:"sessionCookieName,omitempty" yaml:"sessionCookieName,omitempty" export:"true"`
+	// ForwardClaims maps the names of the request headers to set to the names of the ID token claims whose value they are set to.
+	ForwardClaims map[string]string `json:"forwardClaims,omitempty" toml:"forwardClaims,omitempty" yaml:"forwardClaims,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a OIDC.
+func (o *OIDC) SetDefaults() {
+	o.Scopes = []string{"openid", "profile", "email"}
+	o.RedirectPath = "/oauth2/callback"
+	o.SessionCookieName = "_traefik_oidc"
+}
+
+// +k8s:deepcopy-gen=true
+
 // PassTLSClientCert holds the TLS client cert headers configuration.
 type PassTLSClientCert struct {
 	PEM  bool                      `json:"pem,omitempty" toml:"pem,omitempty" yaml:"pem,omitempty"`
@@ -326,6 +437,12 @@ type RateLimit struct {
 	// It defaults to 1.
 	Burst           int64            `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty"`
 	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty"`
+	// Redis, when defined, is the store in which the token buckets are kept,
+	// so that the rate limit is shared by all the Traefik instances using the same store.
+	Redis *RateLimitRedis `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty"`
+	// Service, when defined, is the name of the service handling the rejected requests.
+	// Its response is sent to the client with the 429 status code.
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
 }
 
 // SetDefaults sets the default values on a RateLimit.
@@ -339,6 +456,27 @@ func (r *RateLimit) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// RateLimitRedis holds the configuration of the Redis store shared by the rate limiters.
+type RateLimitRedis struct {
+	// Endpoints holds the address of the Redis server.
+	// Only one endpoint is supported.
+	Endpoints []string   `json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Password  string     `json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	DB        int        `json:"db,omitempty" toml:"db,omitempty" yaml:"db,omitempty"`
+	TLS       *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+	// Timeout is the maximum duration of an exchange with the Redis server,
+	// after which the local token buckets are used instead. It defaults to 500ms.
+	Timeout types.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// SetDefaults sets the default values on a RateLimitRedis.
+func (r *RateLimitRedis) SetDefaults() {
+	r.Endpoints = []string{"127.0.0.1:6379"}
+	r.Timeout = types.Duration(500 * time.Millisecond)
+}
+
+// +k8s:deepcopy-gen=true
+
 // RedirectRegex holds the redirection configuration.
 type RedirectRegex struct {
 	Regex       string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty"`
@@ -372,9 +510,46 @@ type ReplacePathRegex struct {
 
 // +k8s:deepcopy-gen=true
 
+// RequestID holds the request ID middleware configuration.
+// This middleware identifies each request with an ID, sent to the service and back to the client in a header.
+type RequestID struct {
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	// TrustedIPs is the list of the IPs, or CIDRs, from which the request ID of the incoming requests is kept.
+	// The request ID of the requests from other IPs is replaced.
+	TrustedIPs []string `json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+}
+
+// SetDefaults Default values for a RequestID.
+func (r *RequestID) SetDefaults() {
+	r.HeaderName = "X-Request-ID"
+}
+
+// +k8s:deepcopy-gen=true
+
 // Retry holds the retry configuration.
 type Retry struct {
 	Attempts int `json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
+	// InitialInterval is the duration waited before the first retry.
+	// It doubles for each subsequent retry, with a random jitter. It defaults to 0, which means retrying right away.
+	InitialInterval types.Duration `json:"initialInterval,omitempty" toml:"initialInterval,omitempty" yaml:"initialInterval,omitempty" export:"true"`
+	// Status are the status codes (or ranges of status codes) of the responses to retry,
+	// for the requests with an idempotent method and without body.
+	Status []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	// PerTryTimeout is the maximum duration of each attempt.
+	// The requests with an idempotent method and without body are retried when it is exceeded.
+	PerTryTimeout types.Duration `json:"perTryTimeout,omitempty" toml:"perTryTimeout,omitempty" yaml:"perTryTimeout,omitempty" export:"true"`
+	// Budget, when defined, limits the retries to a percentage of the requests.
+	Budget *RetryBudget `json:"budget,omitempty" toml:"budget,omitempty" yaml:"budget,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RetryBudget limits the retries to a percentage of the requests received over the last 10 seconds.
+type RetryBudget struct {
+	// Percent is the maximum percentage of retries, relatively to the number of requests.
+	Percent int `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	// MinRetries is the number of retries allowed regardless of the percentage.
+	MinRetries int `json:"minRetries,omitempty" toml:"minRetries,omitempty" yaml:"minRetries,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
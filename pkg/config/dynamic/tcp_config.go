@@ -10,8 +10,9 @@ import (
 
 // TCPConfiguration contains all the TCP configuration parameters.
 type TCPConfiguration struct {
-	Routers  map[string]*TCPRouter  `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty"`
-	Services map[string]*TCPService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty"`
+	Routers     map[string]*TCPRouter     `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty"`
+	Middlewares map[string]*TCPMiddleware `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	Services    map[string]*TCPService    `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -48,6 +49,7 @@ func (w *TCPWRRService) SetDefaults() {
 // TCPRouter holds the router configuration.
 type TCPRouter struct {
 	EntryPoints []string            `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty"`
+	Middlewares []string            `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
 	Service     string              `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
 	Rule        string              `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
 	TLS         *RouterTCPTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty"`
@@ -72,8 +74,10 @@ type TCPServersLoadBalancer struct {
 	// connection, to close the reading capability as well, hence fully terminating the
 	// connection. It is a duration in milliseconds, defaulting to 100. A negative value
 	// means an infinite deadline (i.e. the reading capability is never closed).
-	TerminationDelay *int        `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty"`
-	Servers          []TCPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	TerminationDelay *int            `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty"`
+	Servers          []TCPServer     `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	HealthCheck      *TCPHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty"`
+	ProxyProtocol    *ProxyProtocol  `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty"`
 }
 
 // SetDefaults Default values for a TCPServersLoadBalancer
@@ -106,3 +110,34 @@ type TCPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
 	Port    string `toml:"-" json:"-" yaml:"-"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// ProxyProtocol holds the PROXY protocol configuration of the connections to the servers,
+// so that they get the address of the client.
+type ProxyProtocol struct {
+	// Version is the version of the PROXY protocol header sent to the servers, 1 or 2.
+	// It defaults to 2.
+	Version int `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// SetDefaults Default values for a ProxyProtocol.
+func (p *ProxyProtocol) SetDefaults() {
+	p.Version = 2
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPHealthCheck holds the TCP health check configuration.
+// A server is healthy if a connection can be established with it,
+// and, if Send and Expect are defined, if its answer to Send contains Expect.
+type TCPHealthCheck struct {
+	// Port, if defined, replaces the port of the servers address for the health check.
+	Port     int    `json:"port,omitempty" toml:"port,omitempty" yaml:"port,omitempty"`
+	Interval string `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty"`
+	Timeout  string `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Send is the payload sent to the server once connected.
+	Send string `json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty"`
+	// Expect is the payload that the answer of the server must contain.
+	Expect string `json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty"`
+}
@@ -0,0 +1,52 @@
+package dynamic
+
+import (
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/types"
+)
+
+// +k8s:deepcopy-gen=true
+
+// TCPMiddleware holds the TCPMiddleware configuration.
+type TCPMiddleware struct {
+	IPWhiteList  *TCPIPWhiteList  `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty"`
+	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty"`
+	RateLimit    *TCPRateLimit    `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPIPWhiteList holds the TCP ip white list configuration.
+type TCPIPWhiteList struct {
+	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPInFlightConn limits the number of connections being served concurrently, per source IP.
+type TCPInFlightConn struct {
+	Amount int64 `json:"amount,omitempty" toml:"amount,omitempty" yaml:"amount,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPRateLimit limits the rate of new connections, per source IP.
+type TCPRateLimit struct {
+	// Average is the maximum rate, by default in connections/s, allowed for a given source IP.
+	// It defaults to 0, which means no rate limiting.
+	// The rate is actually defined by dividing Average by Period.
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty"`
+	// Period, in combination with Average, defines the actual maximum rate, such as:
+	// r = Average / Period. It defaults to a second.
+	Period types.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty"`
+	// Burst is the maximum number of connections allowed to be opened in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty"`
+}
+
+// SetDefaults sets the default values on a TCPRateLimit.
+func (r *TCPRateLimit) SetDefaults() {
+	r.Burst = 1
+	r.Period = types.Duration(time.Second)
+}
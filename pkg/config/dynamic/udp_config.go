@@ -53,7 +53,8 @@ type UDPRouter struct {
 
 // UDPServersLoadBalancer defines the configuration for a load-balancer of UDP servers.
 type UDPServersLoadBalancer struct {
-	Servers []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	Servers     []UDPServer     `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	HealthCheck *UDPHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
 }
 
 // Mergeable reports whether the given load-balancer can be merged with the receiver.
@@ -80,3 +81,17 @@ type UDPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
 	Port    string `toml:"-" json:"-" yaml:"-"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// UDPHealthCheck holds the UDP health check configuration.
+// A server is healthy if it answers the Send payload within the timeout,
+// and, if Expect is defined, if its answer contains Expect.
+type UDPHealthCheck struct {
+	// Port, if defined, replaces the port of the servers address for the health check.
+	Port     int    `json:"port,omitempty" toml:"port,omitempty" yaml:"port,omitempty"`
+	Interval string `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty"`
+	Timeout  string `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Send     string `json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty"`
+	Expect   string `json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty"`
+}
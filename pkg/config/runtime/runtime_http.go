@@ -148,7 +148,8 @@ type ServiceInfo struct {
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
 
 	serverStatusMu sync.RWMutex
-	serverStatus   map[string]string // keyed by server URL
+	serverStatus   map[string]string // keyed by server URL, or by child service name for Weighted and Failover services
+	healthStatus   string            // aggregated status (UP or DOWN) of the service, if it is known
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -184,6 +185,26 @@ func (s *ServiceInfo) UpdateServerStatus(server string, status string) {
 	s.serverStatus[server] = status
 }
 
+// UpdateHealthStatus sets the aggregated status (UP or DOWN) of the service,
+// i.e. whether it has at least one healthy server, or one healthy child service.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) UpdateHealthStatus(status string) {
+	s.serverStatusMu.Lock()
+	defer s.serverStatusMu.Unlock()
+
+	s.healthStatus = status
+}
+
+// GetHealthStatus returns the aggregated status of the service,
+// or an empty string if the service does not keep track of its health.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetHealthStatus() string {
+	s.serverStatusMu.RLock()
+	defer s.serverStatusMu.RUnlock()
+
+	return s.healthStatus
+}
+
 // GetAllStatus returns all the statuses of all the servers in ServiceInfo.
 // It is the responsibility of the caller to check that s is not nil
 func (s *ServiceInfo) GetAllStatus() map[string]string {
@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
@@ -81,6 +82,36 @@ func (r *TCPRouterInfo) AddError(err error, critical bool) {
 	}
 }
 
+// TCPMiddlewareInfo holds information about a currently running TCP middleware.
+type TCPMiddlewareInfo struct {
+	*dynamic.TCPMiddleware // dynamic configuration
+	// Err contains all the errors that occurred during middleware creation.
+	Err    []string `json:"error,omitempty"`
+	Status string   `json:"status,omitempty"`
+	UsedBy []string `json:"usedBy,omitempty"` // list of TCP routers using that middleware.
+}
+
+// AddError adds err to m.Err, if it does not already exist.
+// If critical is set, m is marked as disabled.
+func (m *TCPMiddlewareInfo) AddError(err error, critical bool) {
+	for _, value := range m.Err {
+		if value == err.Error() {
+			return
+		}
+	}
+
+	m.Err = append(m.Err, err.Error())
+	if critical {
+		m.Status = StatusDisabled
+		return
+	}
+
+	// only set it to "warning" if not already in a worse state
+	if m.Status != StatusDisabled {
+		m.Status = StatusWarning
+	}
+}
+
 // TCPServiceInfo holds information about a currently running TCP service.
 type TCPServiceInfo struct {
 	*dynamic.TCPService          // dynamic configuration
@@ -90,6 +121,9 @@ type TCPServiceInfo struct {
 	// It is the caller's responsibility to set the initial status.
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
+
+	serverStatusMu sync.RWMutex
+	serverStatus   map[string]string // keyed by server address
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -112,3 +146,32 @@ func (s *TCPServiceInfo) AddError(err error, critical bool) {
 		s.Status = StatusWarning
 	}
 }
+
+// UpdateServerStatus sets the status of the server in the TCPServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *TCPServiceInfo) UpdateServerStatus(server string, status string) {
+	s.serverStatusMu.Lock()
+	defer s.serverStatusMu.Unlock()
+
+	if s.serverStatus == nil {
+		s.serverStatus = make(map[string]string)
+	}
+	s.serverStatus[server] = status
+}
+
+// GetAllStatus returns all the statuses of all the servers in TCPServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *TCPServiceInfo) GetAllStatus() map[string]string {
+	s.serverStatusMu.RLock()
+	defer s.serverStatusMu.RUnlock()
+
+	if len(s.serverStatus) == 0 {
+		return nil
+	}
+
+	allStatus := make(map[string]string, len(s.serverStatus))
+	for k, v := range s.serverStatus {
+		allStatus[k] = v
+	}
+	return allStatus
+}
@@ -661,6 +661,77 @@ func TestPopulateUsedBy(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "TCP, 2 middlewares both used by 2 Routers",
+			conf: &runtime.Configuration{
+				TCPServices: map[string]*runtime.TCPServiceInfo{
+					"foo-service@myprovider": {
+						TCPService: &dynamic.TCPService{
+							LoadBalancer: &dynamic.TCPServersLoadBalancer{
+								Servers: []dynamic.TCPServer{
+									{
+										Address: "127.0.0.1:8085",
+									},
+								},
+							},
+						},
+					},
+				},
+				TCPMiddlewares: map[string]*runtime.TCPMiddlewareInfo{
+					"allowlist@myprovider": {
+						TCPMiddleware: &dynamic.TCPMiddleware{
+							IPWhiteList: &dynamic.TCPIPWhiteList{
+								SourceRange: []string{"10.0.0.0/8"},
+							},
+						},
+					},
+					"conns@myprovider": {
+						TCPMiddleware: &dynamic.TCPMiddleware{
+							InFlightConn: &dynamic.TCPInFlightConn{
+								Amount: 10,
+							},
+						},
+					},
+				},
+				TCPRouters: map[string]*runtime.TCPRouterInfo{
+					"bar@myprovider": {
+						TCPRouter: &dynamic.TCPRouter{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "HostSNI(`*`)",
+							Middlewares: []string{"allowlist", "conns"},
+						},
+					},
+					"test@myprovider": {
+						TCPRouter: &dynamic.TCPRouter{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "HostSNI(`*`)",
+							Middlewares: []string{"allowlist", "conns@myprovider", "unknown"},
+						},
+					},
+				},
+			},
+			expected: runtime.Configuration{
+				TCPRouters: map[string]*runtime.TCPRouterInfo{
+					"bar@myprovider":  {},
+					"test@myprovider": {},
+				},
+				TCPServices: map[string]*runtime.TCPServiceInfo{
+					"foo-service@myprovider": {
+						UsedBy: []string{"bar@myprovider", "test@myprovider"},
+					},
+				},
+				TCPMiddlewares: map[string]*runtime.TCPMiddlewareInfo{
+					"allowlist@myprovider": {
+						UsedBy: []string{"bar@myprovider", "test@myprovider"},
+					},
+					"conns@myprovider": {
+						UsedBy: []string{"bar@myprovider", "test@myprovider"},
+					},
+				},
+			},
+		},
 	}
 	for _, test := range testCases {
 		test := test
@@ -685,6 +756,11 @@ func TestPopulateUsedBy(t *testing.T) {
 				require.NotNil(t, runtimeConf.TCPServices[key])
 				assert.Equal(t, expectedTCPService.UsedBy, runtimeConf.TCPServices[key].UsedBy)
 			}
+
+			for key, expectedTCPMiddleware := range test.expected.TCPMiddlewares {
+				require.NotNil(t, runtimeConf.TCPMiddlewares[key])
+				assert.Equal(t, expectedTCPMiddleware.UsedBy, runtimeConf.TCPMiddlewares[key].UsedBy)
+			}
 		})
 	}
 }
@@ -0,0 +1,67 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// checkGRPCHealth checks the health of a server with the gRPC health checking protocol (grpc.health.v1),
+// i.e. the server is healthy if it reports serving for the empty service name.
+// The connection uses TLS when the scheme of the checked URL is https, and h2c otherwise.
+func checkGRPCHealth(serverURL *url.URL, backend *BackendConfig) error {
+	u, err := backend.checkURL(serverURL)
+	if err != nil {
+		return fmt.Errorf("failed to build gRPC health check target: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backend.Timeout)
+	defer cancel()
+
+	opts := []grpc.DialOption{grpc.WithBlock()}
+	if u.Scheme == "https" {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(grpcTLSConfig(backend.Transport))))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	if backend.Hostname != "" {
+		opts = append(opts, grpc.WithAuthority(backend.Hostname))
+	}
+
+	conn, err := grpc.DialContext(ctx, u.Host, opts...)
+	if err != nil {
+		return fmt.Errorf("gRPC connection failed: %s", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if len(backend.Headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(backend.Headers))
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("gRPC health check failed: %s", err)
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("received gRPC health status: %s", resp.GetStatus())
+	}
+
+	return nil
+}
+
+// grpcTLSConfig returns the TLS configuration of the given round tripper, if it is an http.Transport.
+func grpcTLSConfig(roundTripper http.RoundTripper) *tls.Config {
+	if transport, ok := roundTripper.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		return transport.TLSClientConfig.Clone()
+	}
+	return &tls.Config{}
+}
@@ -0,0 +1,75 @@
+package healthcheck
+
+import (
+	"net"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCheckGRPCHealth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	healthServer := health.NewServer()
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().String()
+	require.NoError(t, closedListener.Close())
+
+	testCases := []struct {
+		desc          string
+		address       string
+		status        healthpb.HealthCheckResponse_ServingStatus
+		expectedError bool
+	}{
+		{
+			desc:    "serving",
+			address: listener.Addr().String(),
+			status:  healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			desc:          "not serving",
+			address:       listener.Addr().String(),
+			status:        healthpb.HealthCheckResponse_NOT_SERVING,
+			expectedError: true,
+		},
+		{
+			desc:          "server not listening",
+			address:       closedAddress,
+			status:        healthpb.HealthCheckResponse_SERVING,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			healthServer.SetServingStatus("", test.status)
+
+			backend := NewBackendConfig(Options{
+				Mode:    ModeGRPC,
+				Timeout: healthCheckTimeout,
+				Headers: map[string]string{"X-Custom": "foo"},
+			}, "backendName")
+
+			err := checkHealth(testhelpers.MustParseURL("h2c://"+test.address), backend)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -2,10 +2,14 @@ package healthcheck
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"sync"
 	"time"
@@ -13,6 +17,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/go-kit/kit/metrics"
 	"github.com/vulcand/oxy/roundrobin"
 )
@@ -22,6 +27,15 @@ const (
 	serverDown = "DOWN"
 )
 
+// Health check modes.
+const (
+	ModeHTTP = "http"
+	ModeGRPC = "grpc"
+)
+
+// maxBodySize is the maximum number of bytes of a health check response body read to match the expected body.
+const maxBodySize = 1 << 20
+
 var singleton *HealthCheck
 var once sync.Once
 
@@ -33,12 +47,37 @@ type Balancer interface {
 	UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error
 }
 
+// WeightedBalancer is a Balancer that knows the weight of its servers.
+type WeightedBalancer interface {
+	ServerWeight(u *url.URL) (int, bool)
+}
+
+// WeightedUpserter is a Balancer whose servers are given their weight directly,
+// rather than with roundrobin.ServerOption.
+type WeightedUpserter interface {
+	UpsertWeightedServer(u *url.URL, weight int) error
+}
+
+// UpsertWeightedServer adds the given server, with the given weight, to the Balancer.
+func UpsertWeightedServer(lb Balancer, u *url.URL, weight int) error {
+	if wu, ok := lb.(WeightedUpserter); ok {
+		return wu.UpsertWeightedServer(u, weight)
+	}
+	return lb.UpsertServer(u, roundrobin.Weight(weight))
+}
+
 // BalancerHandler includes functionality for load-balancing management.
 type BalancerHandler interface {
 	ServeHTTP(w http.ResponseWriter, req *http.Request)
 	Balancer
 }
 
+// StatusUpdater should be implemented by a service that, when its status changes (e.g. all if its children are down),
+// needs to propagate upwards (to their parent(s)) that change.
+type StatusUpdater interface {
+	RegisterStatusUpdater(fn func(up bool)) error
+}
+
 // metricsRegistry is a local interface in the health check package, exposing only the required metrics
 // necessary for the health check package. This makes it easier for the tests.
 type metricsRegistry interface {
@@ -47,6 +86,8 @@ type metricsRegistry interface {
 
 // Options are the public health check options.
 type Options struct {
+	// Mode is ModeHTTP (the default) or ModeGRPC.
+	Mode            string
 	Headers         map[string]string
 	Hostname        string
 	Scheme          string
@@ -57,10 +98,20 @@ type Options struct {
 	Interval        time.Duration
 	Timeout         time.Duration
 	LB              Balancer
+	// ExpectedStatus are the status codes of a healthy response. When empty, 2XX and 3XX responses are healthy.
+	ExpectedStatus types.HTTPCodeRanges
+	// ExpectedBody, if not nil, must match the body of a healthy response.
+	ExpectedBody *regexp.Regexp
+	// HealthyThreshold is the number of consecutive successful checks before a server is returned to the load-balancer.
+	HealthyThreshold int
+	// UnhealthyThreshold is the number of consecutive failed checks before a server is removed from the load-balancer.
+	UnhealthyThreshold int
 }
 
 func (opt Options) String() string {
-	return fmt.Sprintf("[Hostname: %s Headers: %v Path: %s Port: %d Interval: %s Timeout: %s FollowRedirects: %v]", opt.Hostname, opt.Headers, opt.Path, opt.Port, opt.Interval, opt.Timeout, opt.FollowRedirects)
+	return fmt.Sprintf("[Mode: %s Hostname: %s Headers: %v Path: %s Port: %d Interval: %s Timeout: %s FollowRedirects: %v ExpectedStatus: %v ExpectedBody: %q HealthyThreshold: %d UnhealthyThreshold: %d]",
+		opt.Mode, opt.Hostname, opt.Headers, opt.Path, opt.Port, opt.Interval, opt.Timeout, opt.FollowRedirects,
+		opt.ExpectedStatus, opt.ExpectedBody, opt.HealthyThreshold, opt.UnhealthyThreshold)
 }
 
 type backendURL struct {
@@ -73,9 +124,13 @@ type BackendConfig struct {
 	Options
 	name         string
 	disabledURLs []backendURL
+	// results are the numbers of consecutive check results going against the current state of the servers,
+	// i.e. failures for the enabled servers, and successes for the disabled ones.
+	results map[string]int
 }
 
-func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
+// checkURL returns the URL checked for the given server.
+func (b *BackendConfig) checkURL(serverURL *url.URL) (*url.URL, error) {
 	u, err := serverURL.Parse(b.Path)
 	if err != nil {
 		return nil, err
@@ -89,9 +144,40 @@ func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
 		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(b.Port))
 	}
 
+	return u, nil
+}
+
+func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
+	u, err := b.checkURL(serverURL)
+	if err != nil {
+		return nil, err
+	}
+
 	return http.NewRequest(http.MethodGet, u.String(), http.NoBody)
 }
 
+// thresholdReached records a check result going against the current state of the given server,
+// and reports whether threshold consecutive such results have been recorded, in which case the count is reset.
+func (b *BackendConfig) thresholdReached(serverURL *url.URL, threshold int) bool {
+	if b.results == nil {
+		b.results = make(map[string]int)
+	}
+
+	key := serverURL.String()
+	b.results[key]++
+	if b.results[key] < threshold {
+		return false
+	}
+
+	delete(b.results, key)
+	return true
+}
+
+// resetResults forgets the results recorded by thresholdReached for the given server.
+func (b *BackendConfig) resetResults(serverURL *url.URL) {
+	delete(b.results, serverURL.String())
+}
+
 // this function adds additional http headers and hostname to http.request
 func (b *BackendConfig) addHeadersAndHost(req *http.Request) *http.Request {
 	if b.Options.Hostname != "" {
@@ -155,16 +241,22 @@ func (hc *HealthCheck) checkBackend(ctx context.Context, backend *BackendConfig)
 	// FIXME re enable metrics
 	for _, disabledURL := range backend.disabledURLs {
 		// FIXME serverUpMetricValue := float64(0)
-		if err := checkHealth(disabledURL.url, backend); err == nil {
+		err := checkHealth(disabledURL.url, backend)
+		switch {
+		case err != nil:
+			backend.resetResults(disabledURL.url)
+			logger.Warnf("Health check still failing. Backend: %q URL: %q Reason: %s", backend.name, disabledURL.url.String(), err)
+			newDisabledURLs = append(newDisabledURLs, disabledURL)
+		case !backend.thresholdReached(disabledURL.url, backend.HealthyThreshold):
+			logger.Debugf("Health check succeeded, waiting for the healthy threshold. Backend: %q URL: %q", backend.name, disabledURL.url.String())
+			newDisabledURLs = append(newDisabledURLs, disabledURL)
+		default:
 			logger.Warnf("Health check up: Returning to server list. Backend: %q URL: %q Weight: %d",
 				backend.name, disabledURL.url.String(), disabledURL.weight)
-			if err = backend.LB.UpsertServer(disabledURL.url, roundrobin.Weight(disabledURL.weight)); err != nil {
+			if err = UpsertWeightedServer(backend.LB, disabledURL.url, disabledURL.weight); err != nil {
 				logger.Error(err)
 			}
 			// FIXME serverUpMetricValue = 1
-		} else {
-			logger.Warnf("Health check still failing. Backend: %q URL: %q Reason: %s", backend.name, disabledURL.url.String(), err)
-			newDisabledURLs = append(newDisabledURLs, disabledURL)
 		}
 		// FIXME labelValues := []string{"backend", backend.name, "url", backendurl.url.String()}
 		// FIXME hc.metrics.BackendServerUpGauge().With(labelValues...).Set(serverUpMetricValue)
@@ -174,12 +266,17 @@ func (hc *HealthCheck) checkBackend(ctx context.Context, backend *BackendConfig)
 	// FIXME re enable metrics
 	for _, enableURL := range enabledURLs {
 		// FIXME serverUpMetricValue := float64(1)
-		if err := checkHealth(enableURL, backend); err != nil {
+		err := checkHealth(enableURL, backend)
+		switch {
+		case err == nil:
+			backend.resetResults(enableURL)
+		case !backend.thresholdReached(enableURL, backend.UnhealthyThreshold):
+			logger.Debugf("Health check failed, waiting for the unhealthy threshold. Backend: %q URL: %q Reason: %s", backend.name, enableURL.String(), err)
+		default:
 			weight := 1
-			rr, ok := backend.LB.(*roundrobin.RoundRobin)
-			if ok {
+			if wb, ok := backend.LB.(WeightedBalancer); ok {
 				var gotWeight bool
-				weight, gotWeight = rr.ServerWeight(enableURL)
+				weight, gotWeight = wb.ServerWeight(enableURL)
 				if !gotWeight {
 					weight = 1
 				}
@@ -228,6 +325,10 @@ func NewBackendConfig(options Options, backendName string) *BackendConfig {
 // checkHealth returns a nil error in case it was successful and otherwise
 // a non-nil error with a meaningful description why the health check failed.
 func checkHealth(serverURL *url.URL, backend *BackendConfig) error {
+	if backend.Mode == ModeGRPC {
+		return checkGRPCHealth(serverURL, backend)
+	}
+
 	req, err := backend.newRequest(serverURL)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %s", err)
@@ -253,48 +354,153 @@ func checkHealth(serverURL *url.URL, backend *BackendConfig) error {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+	if len(backend.ExpectedStatus) > 0 {
+		if !backend.ExpectedStatus.Contains(resp.StatusCode) {
+			return fmt.Errorf("received unexpected status code: %v", resp.StatusCode)
+		}
+	} else if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
 		return fmt.Errorf("received error status code: %v", resp.StatusCode)
 	}
 
+	if backend.ExpectedBody != nil {
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %s", err)
+		}
+
+		if !backend.ExpectedBody.Match(body) {
+			return fmt.Errorf("response body does not match %q", backend.ExpectedBody)
+		}
+	}
+
 	return nil
 }
 
-// NewLBStatusUpdater returns a new LbStatusUpdater
-func NewLBStatusUpdater(bh BalancerHandler, info *runtime.ServiceInfo) *LbStatusUpdater {
+// NewLBStatusUpdater returns a new LbStatusUpdater.
+// wantsHealthCheck reports whether the servers of the BalancerHandler are checked (actively or passively),
+// i.e. whether its status can be propagated to its parent services.
+func NewLBStatusUpdater(bh BalancerHandler, info *runtime.ServiceInfo, wantsHealthCheck bool) *LbStatusUpdater {
 	return &LbStatusUpdater{
-		BalancerHandler: bh,
-		serviceInfo:     info,
+		BalancerHandler:  bh,
+		serviceInfo:      info,
+		wantsHealthCheck: wantsHealthCheck,
 	}
 }
 
 // LbStatusUpdater wraps a BalancerHandler and a ServiceInfo,
 // so it can keep track of the status of a server in the ServiceInfo.
+// It also propagates the status of the BalancerHandler, i.e. whether it has at least one server up,
+// to the functions registered with RegisterStatusUpdater.
 type LbStatusUpdater struct {
 	BalancerHandler
-	serviceInfo *runtime.ServiceInfo // can be nil
+	serviceInfo      *runtime.ServiceInfo // can be nil
+	wantsHealthCheck bool
+
+	mu       sync.Mutex
+	down     bool
+	updaters []func(up bool)
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// fn is run right away if the Balancer is already down.
+func (lb *LbStatusUpdater) RegisterStatusUpdater(fn func(up bool)) error {
+	if !lb.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this loadbalancer service")
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.updateStatus()
+	lb.updaters = append(lb.updaters, fn)
+	if lb.down {
+		fn(false)
+	}
+
+	return nil
 }
 
 // RemoveServer removes the given server from the BalancerHandler,
 // and updates the status of the server to "DOWN".
 func (lb *LbStatusUpdater) RemoveServer(u *url.URL) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
 	err := lb.BalancerHandler.RemoveServer(u)
 	if err == nil && lb.serviceInfo != nil {
 		lb.serviceInfo.UpdateServerStatus(u.String(), serverDown)
 	}
+
+	lb.updateStatus()
 	return err
 }
 
 // UpsertServer adds the given server to the BalancerHandler,
 // and updates the status of the server to "UP".
 func (lb *LbStatusUpdater) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
 	err := lb.BalancerHandler.UpsertServer(u, options...)
 	if err == nil && lb.serviceInfo != nil {
 		lb.serviceInfo.UpdateServerStatus(u.String(), serverUp)
 	}
+
+	lb.updateStatus()
 	return err
 }
 
+// UpsertWeightedServer adds the given server, with the given weight, to the BalancerHandler,
+// and updates the status of the server to "UP".
+func (lb *LbStatusUpdater) UpsertWeightedServer(u *url.URL, weight int) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	err := UpsertWeightedServer(lb.BalancerHandler, u, weight)
+	if err == nil && lb.serviceInfo != nil {
+		lb.serviceInfo.UpdateServerStatus(u.String(), serverUp)
+	}
+
+	lb.updateStatus()
+	return err
+}
+
+// updateStatus runs the registered hooks if the BalancerHandler went down (no more servers), or back up,
+// and keeps track of its status in the ServiceInfo.
+func (lb *LbStatusUpdater) updateStatus() {
+	if !lb.wantsHealthCheck {
+		return
+	}
+
+	down := len(lb.BalancerHandler.Servers()) == 0
+	if lb.serviceInfo != nil {
+		status := serverUp
+		if down {
+			status = serverDown
+		}
+		lb.serviceInfo.UpdateHealthStatus(status)
+	}
+
+	if down == lb.down {
+		return
+	}
+	lb.down = down
+
+	for _, fn := range lb.updaters {
+		fn(!down)
+	}
+}
+
+// ServerWeight returns the weight of the given server in the BalancerHandler,
+// if the BalancerHandler keeps track of weights.
+func (lb *LbStatusUpdater) ServerWeight(u *url.URL) (int, bool) {
+	if wb, ok := lb.BalancerHandler.(WeightedBalancer); ok {
+		return wb.ServerWeight(u)
+	}
+	return 0, false
+}
+
 // Balancers is a list of Balancers(s) that implements the Balancer interface.
 type Balancers []Balancer
 
@@ -329,3 +535,30 @@ func (b Balancers) UpsertServer(u *url.URL, options ...roundrobin.ServerOption)
 	}
 	return nil
 }
+
+// UpsertWeightedServer adds the given server, with the given weight, to all the BalancerHandler,
+// and updates the status of the server to "UP".
+func (b Balancers) UpsertWeightedServer(u *url.URL, weight int) error {
+	for _, lb := range b {
+		if err := UpsertWeightedServer(lb, u, weight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServerWeight returns the weight of the given server,
+// as known by the first Balancer that keeps track of it.
+func (b Balancers) ServerWeight(u *url.URL) (int, bool) {
+	for _, lb := range b {
+		wb, ok := lb.(WeightedBalancer)
+		if !ok {
+			continue
+		}
+
+		if weight, found := wb.ServerWeight(u); found {
+			return weight, true
+		}
+	}
+	return 0, false
+}
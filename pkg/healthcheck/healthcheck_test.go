@@ -5,12 +5,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"regexp"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vulcand/oxy/roundrobin"
@@ -29,6 +31,8 @@ func TestSetBackendsConfiguration(t *testing.T) {
 		desc                       string
 		startHealthy               bool
 		healthSequence             []int
+		healthyThreshold           int
+		unhealthyThreshold         int
 		expectedNumRemovedServers  int
 		expectedNumUpsertedServers int
 		expectedGaugeValue         float64
@@ -89,6 +93,42 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			expectedNumUpsertedServers: 1,
 			expectedGaugeValue:         1,
 		},
+		{
+			desc:                       "healthy server failing less than the unhealthy threshold",
+			startHealthy:               true,
+			healthSequence:             []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusServiceUnavailable},
+			unhealthyThreshold:         2,
+			expectedNumRemovedServers:  0,
+			expectedNumUpsertedServers: 0,
+			expectedGaugeValue:         1,
+		},
+		{
+			desc:                       "healthy server reaching the unhealthy threshold",
+			startHealthy:               true,
+			healthSequence:             []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			unhealthyThreshold:         2,
+			expectedNumRemovedServers:  1,
+			expectedNumUpsertedServers: 0,
+			expectedGaugeValue:         0,
+		},
+		{
+			desc:                       "sick server succeeding less than the healthy threshold",
+			startHealthy:               false,
+			healthSequence:             []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusOK},
+			healthyThreshold:           2,
+			expectedNumRemovedServers:  0,
+			expectedNumUpsertedServers: 0,
+			expectedGaugeValue:         0,
+		},
+		{
+			desc:                       "sick server reaching the healthy threshold",
+			startHealthy:               false,
+			healthSequence:             []int{http.StatusOK, http.StatusOK},
+			healthyThreshold:           2,
+			expectedNumRemovedServers:  0,
+			expectedNumUpsertedServers: 1,
+			expectedGaugeValue:         1,
+		},
 	}
 
 	for _, test := range testCases {
@@ -105,10 +145,12 @@ func TestSetBackendsConfiguration(t *testing.T) {
 
 			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
 			backend := NewBackendConfig(Options{
-				Path:     "/path",
-				Interval: healthCheckInterval,
-				Timeout:  healthCheckTimeout,
-				LB:       lb,
+				Path:               "/path",
+				Interval:           healthCheckInterval,
+				Timeout:            healthCheckTimeout,
+				LB:                 lb,
+				HealthyThreshold:   test.healthyThreshold,
+				UnhealthyThreshold: test.unhealthyThreshold,
 			}, "backendName")
 
 			serverURL := testhelpers.MustParseURL(ts.URL)
@@ -287,6 +329,89 @@ func TestNewRequest(t *testing.T) {
 	}
 }
 
+func TestCheckHealth(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		statusCode     int
+		body           string
+		expectedStatus []string
+		expectedBody   string
+		expectedError  bool
+	}{
+		{
+			desc:       "default status codes",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc:          "default status codes with an error status",
+			statusCode:    http.StatusBadRequest,
+			expectedError: true,
+		},
+		{
+			desc:           "status in the expected ranges",
+			statusCode:     http.StatusTooManyRequests,
+			expectedStatus: []string{"200", "400-499"},
+		},
+		{
+			desc:           "status not in the expected ranges",
+			statusCode:     http.StatusNoContent,
+			expectedStatus: []string{"200", "400-499"},
+			expectedError:  true,
+		},
+		{
+			desc:         "body containing the expected substring",
+			statusCode:   http.StatusOK,
+			body:         `{"status": "ok"}`,
+			expectedBody: `"status": "ok"`,
+		},
+		{
+			desc:         "body matching the expected regular expression",
+			statusCode:   http.StatusOK,
+			body:         `{"status": "ok", "version": "1.2.3"}`,
+			expectedBody: `"version": "1\.\d+`,
+		},
+		{
+			desc:          "body not matching the expected regular expression",
+			statusCode:    http.StatusOK,
+			body:          `{"status": "degraded"}`,
+			expectedBody:  `"status": "ok"`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(test.statusCode)
+				_, _ = rw.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			expectedStatus, err := types.NewHTTPCodeRanges(test.expectedStatus)
+			require.NoError(t, err)
+
+			options := Options{
+				Path:           "/health",
+				Timeout:        healthCheckTimeout,
+				ExpectedStatus: expectedStatus,
+			}
+			if test.expectedBody != "" {
+				options.ExpectedBody = regexp.MustCompile(test.expectedBody)
+			}
+
+			err = checkHealth(testhelpers.MustParseURL(server.URL), NewBackendConfig(options, "backendName"))
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestAddHeadersAndHost(t *testing.T) {
 	testCases := []struct {
 		desc             string
@@ -444,7 +569,7 @@ func (th *testHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func TestLBStatusUpdater(t *testing.T) {
 	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
 	svInfo := &runtime.ServiceInfo{}
-	lbsu := NewLBStatusUpdater(lb, svInfo)
+	lbsu := NewLBStatusUpdater(lb, svInfo, true)
 	newServer, err := url.Parse("http://foo.com")
 	assert.Nil(t, err)
 	err = lbsu.UpsertServer(newServer, roundrobin.Weight(1))
@@ -470,6 +595,154 @@ func TestLBStatusUpdater(t *testing.T) {
 	}
 }
 
+func TestLBStatusUpdaterPropagation(t *testing.T) {
+	serverA := testhelpers.MustParseURL("http://foo.com")
+	serverB := testhelpers.MustParseURL("http://bar.com")
+
+	lbsu := NewLBStatusUpdater(&testLoadBalancer{RWMutex: &sync.RWMutex{}}, nil, true)
+	require.NoError(t, lbsu.UpsertServer(serverA))
+	require.NoError(t, lbsu.UpsertServer(serverB))
+
+	var statuses []bool
+	err := lbsu.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+	require.NoError(t, err)
+
+	// The status is propagated only when the last server goes down, and when the first one goes back up.
+	require.NoError(t, lbsu.RemoveServer(serverA))
+	assert.Empty(t, statuses)
+
+	require.NoError(t, lbsu.RemoveServer(serverB))
+	assert.Equal(t, []bool{false}, statuses)
+
+	require.NoError(t, lbsu.UpsertServer(serverA))
+	require.NoError(t, lbsu.UpsertServer(serverB))
+	assert.Equal(t, []bool{false, true}, statuses)
+
+	// A hook registered while there is no server is run right away.
+	empty := NewLBStatusUpdater(&testLoadBalancer{RWMutex: &sync.RWMutex{}}, nil, true)
+	var emptyStatuses []bool
+	err = empty.RegisterStatusUpdater(func(up bool) {
+		emptyStatuses = append(emptyStatuses, up)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false}, emptyStatuses)
+
+	require.NoError(t, empty.UpsertServer(serverA))
+	assert.Equal(t, []bool{false, true}, emptyStatuses)
+}
+
+func TestLBStatusUpdaterWithoutHealthCheck(t *testing.T) {
+	lbsu := NewLBStatusUpdater(&testLoadBalancer{RWMutex: &sync.RWMutex{}}, nil, false)
+
+	err := lbsu.RegisterStatusUpdater(func(up bool) {})
+	assert.Error(t, err)
+}
+
+func TestBalancersServerWeight(t *testing.T) {
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	lbs := Balancers{
+		NewLBStatusUpdater(&testLoadBalancer{RWMutex: &sync.RWMutex{}}, nil, true),
+		NewLBStatusUpdater(rr, nil, true),
+	}
+
+	serverURL := testhelpers.MustParseURL("http://foo.com")
+	err = lbs.UpsertServer(serverURL, roundrobin.Weight(3))
+	require.NoError(t, err)
+
+	weight, found := lbs.ServerWeight(serverURL)
+	assert.True(t, found)
+	assert.Equal(t, 3, weight)
+
+	_, found = lbs.ServerWeight(testhelpers.MustParseURL("http://bar.com"))
+	assert.False(t, found)
+}
+
+func TestBalancersUpsertWeightedServer(t *testing.T) {
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	weighted := &weightedTestLoadBalancer{
+		testLoadBalancer: &testLoadBalancer{RWMutex: &sync.RWMutex{}},
+		weights:          make(map[string]int),
+	}
+
+	lbs := Balancers{
+		NewLBStatusUpdater(weighted, nil, true),
+		NewLBStatusUpdater(rr, nil, true),
+	}
+
+	serverURL := testhelpers.MustParseURL("http://foo.com")
+	require.NoError(t, lbs.UpsertWeightedServer(serverURL, 3))
+
+	assert.Equal(t, 3, weighted.weights[serverURL.String()])
+	assert.Empty(t, weighted.Options())
+
+	weight, found := rr.ServerWeight(serverURL)
+	assert.True(t, found)
+	assert.Equal(t, 3, weight)
+}
+
+type weightedTestLoadBalancer struct {
+	*testLoadBalancer
+	weights map[string]int
+}
+
+func (lb *weightedTestLoadBalancer) UpsertWeightedServer(u *url.URL, weight int) error {
+	lb.weights[u.String()] = weight
+	return lb.UpsertServer(u)
+}
+
+func TestWeightPreservedAcrossHealthCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := newTestServer(cancel, []int{http.StatusServiceUnavailable, http.StatusOK})
+	defer ts.Close()
+
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	serverURL := testhelpers.MustParseURL(ts.URL)
+	lb := Balancers{NewLBStatusUpdater(rr, nil, true)}
+	err = lb.UpsertServer(serverURL, roundrobin.Weight(5))
+	require.NoError(t, err)
+
+	backend := NewBackendConfig(Options{
+		Path:     "/path",
+		Interval: healthCheckInterval,
+		Timeout:  healthCheckTimeout,
+		LB:       lb,
+	}, "backendName")
+
+	check := HealthCheck{
+		Backends: make(map[string]*BackendConfig),
+		metrics:  testhelpers.NewCollectingHealthCheckMetrics(),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	go func() {
+		check.execute(ctx, backend)
+		wg.Done()
+	}()
+
+	timeout := time.Duration(2*int(healthCheckInterval) + 500)
+	select {
+	case <-time.After(timeout):
+		t.Fatal("test did not complete in time")
+	case <-ctx.Done():
+		wg.Wait()
+	}
+
+	weight, found := rr.ServerWeight(serverURL)
+	require.True(t, found)
+	assert.Equal(t, 5, weight)
+}
+
 func TestNotFollowingRedirects(t *testing.T) {
 	redirectServerCalled := false
 	redirectTestServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
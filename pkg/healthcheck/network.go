@@ -0,0 +1,169 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+const (
+	defaultNetworkInterval = 30 * time.Second
+	defaultNetworkTimeout  = 5 * time.Second
+)
+
+// ParseNetworkDurations returns the interval and the timeout of the health check of a TCP or UDP service,
+// parsed from the given values, or the default ones (30s and 5s) if they are not set or invalid.
+func ParseNetworkDurations(ctx context.Context, serviceName, rawInterval, rawTimeout string) (interval, timeout time.Duration) {
+	logger := log.FromContext(ctx)
+
+	interval = defaultNetworkInterval
+	if rawInterval != "" {
+		intervalOverride, err := time.ParseDuration(rawInterval)
+		switch {
+		case err != nil:
+			logger.Errorf("Illegal health check interval for service '%s': %s", serviceName, err)
+		case intervalOverride <= 0:
+			logger.Errorf("Health check interval smaller than zero for service '%s'", serviceName)
+		default:
+			interval = intervalOverride
+		}
+	}
+
+	timeout = defaultNetworkTimeout
+	if rawTimeout != "" {
+		timeoutOverride, err := time.ParseDuration(rawTimeout)
+		switch {
+		case err != nil:
+			logger.Errorf("Illegal health check timeout for service '%s': %s", serviceName, err)
+		case timeoutOverride <= 0:
+			logger.Errorf("Health check timeout smaller than zero for service '%s'", serviceName)
+		default:
+			timeout = timeoutOverride
+		}
+	}
+
+	if timeout >= interval {
+		logger.Warnf("Health check timeout for service '%s' should be lower than the health check interval.", serviceName)
+	}
+
+	return interval, timeout
+}
+
+// addressBalancer is a load-balancer whose servers are identified by their network address.
+type addressBalancer interface {
+	enableServer(address string)
+	disableServer(address string) error
+}
+
+// addressServers holds the state of the health checked servers of a TCP or UDP service.
+type addressServers struct {
+	name string
+	// balancers holds one load-balancer per service handler,
+	// as there is one service handler per reference to a service.
+	balancers    []addressBalancer
+	addresses    []string
+	disabled     map[string]bool
+	updateStatus func(address, status string) // can be nil
+}
+
+func newAddressServers(name string, updateStatus func(address, status string)) addressServers {
+	return addressServers{
+		name:         name,
+		disabled:     make(map[string]bool),
+		updateStatus: updateStatus,
+	}
+}
+
+func (s *addressServers) addBalancer(lb addressBalancer, addresses []string) {
+	for _, address := range addresses {
+		if !containsAddress(s.addresses, address) {
+			s.addresses = append(s.addresses, address)
+		}
+	}
+
+	s.balancers = append(s.balancers, lb)
+}
+
+// execute runs the check on the servers every interval, until ctx is done.
+func (s *addressServers) execute(ctx context.Context, interval time.Duration, check func(ctx context.Context, address string) error) {
+	logger := log.FromContext(ctx)
+	logger.Debugf("Initial health check for service: %q", s.name)
+
+	s.checkServers(ctx, check)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debugf("Stopping current health check goroutines of service: %s", s.name)
+			return
+		case <-ticker.C:
+			logger.Debugf("Refreshing health check for service: %s", s.name)
+			s.checkServers(ctx, check)
+		}
+	}
+}
+
+func (s *addressServers) checkServers(ctx context.Context, check func(ctx context.Context, address string) error) {
+	logger := log.FromContext(ctx)
+
+	for _, address := range s.addresses {
+		err := check(ctx, address)
+
+		switch {
+		case err == nil && s.disabled[address]:
+			logger.Warnf("Health check up: Returning to server list. Service: %q Address: %q", s.name, address)
+			for _, lb := range s.balancers {
+				lb.enableServer(address)
+			}
+			delete(s.disabled, address)
+			s.setStatus(address, serverUp)
+
+		case err != nil && s.disabled[address]:
+			logger.Warnf("Health check still failing. Service: %q Address: %q Reason: %s", s.name, address, err)
+
+		case err != nil:
+			logger.Warnf("Health check failed, removing from server list. Service: %q Address: %q Reason: %s", s.name, address, err)
+			for _, lb := range s.balancers {
+				if errDisable := lb.disableServer(address); errDisable != nil {
+					logger.Error(errDisable)
+				}
+			}
+			s.disabled[address] = true
+			s.setStatus(address, serverDown)
+		}
+	}
+}
+
+func (s *addressServers) setStatus(address, status string) {
+	if s.updateStatus != nil {
+		s.updateStatus(address, status)
+	}
+}
+
+func containsAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// overridePort returns the address with its port replaced by the given one, if not zero.
+func overridePort(address string, port int) (string, error) {
+	if port == 0 {
+		return address, nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid server address: %w", err)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
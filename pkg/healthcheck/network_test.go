@@ -0,0 +1,58 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNetworkDurations(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		interval         string
+		timeout          string
+		expectedInterval time.Duration
+		expectedTimeout  time.Duration
+	}{
+		{
+			desc:             "defaults",
+			expectedInterval: 30 * time.Second,
+			expectedTimeout:  5 * time.Second,
+		},
+		{
+			desc:             "overridden values",
+			interval:         "10s",
+			timeout:          "2s",
+			expectedInterval: 10 * time.Second,
+			expectedTimeout:  2 * time.Second,
+		},
+		{
+			desc:             "invalid values",
+			interval:         "foo",
+			timeout:          "bar",
+			expectedInterval: 30 * time.Second,
+			expectedTimeout:  5 * time.Second,
+		},
+		{
+			desc:             "negative values",
+			interval:         "-1s",
+			timeout:          "-1s",
+			expectedInterval: 30 * time.Second,
+			expectedTimeout:  5 * time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			interval, timeout := ParseNetworkDurations(context.Background(), "foo", test.interval, test.timeout)
+
+			assert.Equal(t, test.expectedInterval, interval)
+			assert.Equal(t, test.expectedTimeout, timeout)
+		})
+	}
+}
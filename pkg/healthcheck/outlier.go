@@ -0,0 +1,260 @@
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/go-kit/kit/metrics"
+)
+
+// OutlierOptions are the outlier detection options.
+type OutlierOptions struct {
+	ConsecutiveErrors  int
+	BaseEjectionTime   time.Duration
+	MaxEjectionTime    time.Duration
+	MaxEjectionPercent int
+}
+
+func (opt OutlierOptions) String() string {
+	return fmt.Sprintf("[ConsecutiveErrors: %d BaseEjectionTime: %s MaxEjectionTime: %s MaxEjectionPercent: %d]",
+		opt.ConsecutiveErrors, opt.BaseEjectionTime, opt.MaxEjectionTime, opt.MaxEjectionPercent)
+}
+
+type outlierServer struct {
+	url    *url.URL
+	weight int
+
+	consecutiveErrors int
+	ejected           bool
+	// ejections is the number of times the server has been ejected in a row,
+	// i.e. without having stayed in the load-balancer for more than the maximum ejection time in between.
+	ejections  int
+	returnedAt time.Time
+}
+
+// OutlierDetector ejects the servers of a load-balancer after consecutive errors observed on live traffic,
+// i.e. a passive health check.
+type OutlierDetector struct {
+	name    string
+	options OutlierOptions
+	gauge   metrics.Gauge // can be nil
+	logger  log.Logger
+
+	mu      sync.Mutex
+	lb      Balancer
+	servers map[string]*outlierServer
+}
+
+// NewOutlierDetector returns a new OutlierDetector.
+// SetBalancer must be called before the handler returned by Handler serves any request.
+func NewOutlierDetector(ctx context.Context, serviceName string, options OutlierOptions, gauge metrics.Gauge) *OutlierDetector {
+	return &OutlierDetector{
+		name:    serviceName,
+		options: options,
+		gauge:   gauge,
+		logger:  log.FromContext(ctx),
+		servers: make(map[string]*outlierServer),
+	}
+}
+
+// SetBalancer sets the load-balancer whose servers are monitored.
+func (d *OutlierDetector) SetBalancer(lb Balancer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.lb = lb
+	d.servers = make(map[string]*outlierServer)
+
+	for _, u := range lb.Servers() {
+		weight := 1
+		if wb, ok := lb.(WeightedBalancer); ok {
+			if w, found := wb.ServerWeight(u); found {
+				weight = w
+			}
+		}
+
+		d.servers[serverKey(u)] = &outlierServer{url: u, weight: weight}
+		d.setGauge(u, 1)
+	}
+}
+
+// Handler returns a handler recording the outcome of the requests forwarded to the servers by next.
+// It expects the URL of the requests to be the URL of the server, as set by the load-balancer.
+func (d *OutlierDetector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		recorder := newStatusRecorder(rw)
+		next.ServeHTTP(recorder, req)
+		d.observe(req.URL, recorder.getCode())
+	})
+}
+
+func (d *OutlierDetector) observe(u *url.URL, statusCode int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	srv, ok := d.servers[serverKey(u)]
+	if !ok || srv.ejected {
+		return
+	}
+
+	if statusCode < http.StatusInternalServerError {
+		srv.consecutiveErrors = 0
+		return
+	}
+
+	srv.consecutiveErrors++
+	if srv.consecutiveErrors < d.options.ConsecutiveErrors {
+		return
+	}
+	srv.consecutiveErrors = 0
+
+	if !d.canEject() {
+		d.logger.Warnf("Outlier detection: maximum number of ejected servers reached, not ejecting. Service: %q URL: %q", d.name, srv.url)
+		return
+	}
+
+	d.eject(srv)
+}
+
+// canEject reports whether one more server can be ejected, according to the maximum ejection percent.
+func (d *OutlierDetector) canEject() bool {
+	var ejected int
+	for _, srv := range d.servers {
+		if srv.ejected {
+			ejected++
+		}
+	}
+
+	maxEjected := len(d.servers) * d.options.MaxEjectionPercent / 100
+	if maxEjected < 1 {
+		maxEjected = 1
+	}
+
+	return ejected < maxEjected
+}
+
+func (d *OutlierDetector) eject(srv *outlierServer) {
+	if err := d.lb.RemoveServer(srv.url); err != nil {
+		// The server has already been removed, e.g. by the active health check.
+		d.logger.Debugf("Outlier detection: unable to eject server %q of service %q: %v", srv.url, d.name, err)
+		return
+	}
+
+	now := time.Now()
+	if !srv.returnedAt.IsZero() && now.Sub(srv.returnedAt) > d.options.MaxEjectionTime {
+		srv.ejections = 0
+	}
+	srv.ejections++
+	srv.ejected = true
+
+	duration := ejectionTime(d.options.BaseEjectionTime, d.options.MaxEjectionTime, srv.ejections)
+
+	d.logger.Warnf("Outlier detection: ejecting server for %s. Service: %q URL: %q", duration, d.name, srv.url)
+	d.setGauge(srv.url, 0)
+
+	time.AfterFunc(duration, func() {
+		d.restore(srv)
+	})
+}
+
+func (d *OutlierDetector) restore(srv *outlierServer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	srv.ejected = false
+	srv.returnedAt = time.Now()
+
+	d.logger.Warnf("Outlier detection: returning server to the load-balancer. Service: %q URL: %q Weight: %d", d.name, srv.url, srv.weight)
+	if err := UpsertWeightedServer(d.lb, srv.url, srv.weight); err != nil {
+		d.logger.Error(err)
+		return
+	}
+
+	d.setGauge(srv.url, 1)
+}
+
+func (d *OutlierDetector) setGauge(u *url.URL, value float64) {
+	if d.gauge != nil {
+		d.gauge.With("service", d.name, "url", u.String()).Set(value)
+	}
+}
+
+// ejectionTime returns base doubled for each ejection after the first one, capped at maxTime.
+func ejectionTime(base, maxTime time.Duration, ejections int) time.Duration {
+	duration := base
+	for i := 1; i < ejections && duration < maxTime; i++ {
+		duration *= 2
+	}
+
+	if duration > maxTime {
+		return maxTime
+	}
+	return duration
+}
+
+// serverKey returns the key identifying a server, the way the load-balancers do.
+func serverKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+type statusRecorder interface {
+	http.ResponseWriter
+	http.Flusher
+	getCode() int
+}
+
+func newStatusRecorder(rw http.ResponseWriter) statusRecorder {
+	rec := &responseStatusRecorder{
+		ResponseWriter: rw,
+		statusCode:     http.StatusOK,
+	}
+	if _, ok := rw.(http.CloseNotifier); !ok {
+		return rec
+	}
+	return &responseStatusRecorderWithCloseNotify{rec}
+}
+
+// responseStatusRecorder captures the status code of the response.
+type responseStatusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+type responseStatusRecorderWithCloseNotify struct {
+	*responseStatusRecorder
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone away.
+func (r *responseStatusRecorderWithCloseNotify) CloseNotify() <-chan bool {
+	return r.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (r *responseStatusRecorder) getCode() int {
+	return r.statusCode
+}
+
+// WriteHeader captures the status code for later retrieval.
+func (r *responseStatusRecorder) WriteHeader(status int) {
+	r.ResponseWriter.WriteHeader(status)
+	r.statusCode = status
+}
+
+// Hijack hijacks the connection.
+func (r *responseStatusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseStatusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,169 @@
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutlierDetector(t *testing.T) {
+	serverA := testhelpers.MustParseURL("http://127.0.0.1:8080")
+	serverB := testhelpers.MustParseURL("http://127.0.0.1:8081")
+
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+	info := &runtime.ServiceInfo{}
+	lbsu := NewLBStatusUpdater(lb, info, true)
+	require.NoError(t, lbsu.UpsertServer(serverA))
+	require.NoError(t, lbsu.UpsertServer(serverB))
+
+	gauge := newLockedGauge()
+	detector := NewOutlierDetector(context.Background(), "service", OutlierOptions{
+		ConsecutiveErrors:  3,
+		BaseEjectionTime:   200 * time.Millisecond,
+		MaxEjectionTime:    time.Second,
+		MaxEjectionPercent: 50,
+	}, gauge)
+	detector.SetBalancer(lbsu)
+
+	assert.Equal(t, 1.0, gauge.value(serverA.String()))
+	assert.Equal(t, 1.0, gauge.value(serverB.String()))
+
+	var statusCode int
+	handler := detector.Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(statusCode)
+	}))
+
+	serve := func(u *url.URL, code int) {
+		statusCode = code
+		req := httptest.NewRequest(http.MethodGet, u.String(), nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// A success resets the consecutive errors.
+	serve(serverA, http.StatusBadGateway)
+	serve(serverA, http.StatusServiceUnavailable)
+	serve(serverA, http.StatusNotFound)
+	serve(serverA, http.StatusBadGateway)
+	serve(serverA, http.StatusBadGateway)
+	assert.Equal(t, 0, lb.removed())
+
+	serve(serverA, http.StatusGatewayTimeout)
+	assert.Equal(t, 1, lb.removed())
+	assert.Equal(t, []*url.URL{serverB}, lb.Servers())
+	assert.Equal(t, map[string]string{serverA.String(): serverDown, serverB.String(): serverUp}, info.GetAllStatus())
+	assert.Equal(t, 0.0, gauge.value(serverA.String()))
+
+	// At most 50% of the servers can be ejected.
+	serve(serverB, http.StatusBadGateway)
+	serve(serverB, http.StatusBadGateway)
+	serve(serverB, http.StatusBadGateway)
+	assert.Equal(t, 1, lb.removed())
+
+	// The server is returned to the load-balancer after the ejection time.
+	assert.Eventually(t, func() bool { return lb.upserted() == 3 }, time.Second, 20*time.Millisecond)
+	assert.Equal(t, map[string]string{serverA.String(): serverUp, serverB.String(): serverUp}, info.GetAllStatus())
+	assert.Equal(t, 1.0, gauge.value(serverA.String()))
+}
+
+func TestEjectionTime(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		ejections int
+		expected  time.Duration
+	}{
+		{
+			desc:      "first ejection",
+			ejections: 1,
+			expected:  30 * time.Second,
+		},
+		{
+			desc:      "second ejection",
+			ejections: 2,
+			expected:  time.Minute,
+		},
+		{
+			desc:      "third ejection",
+			ejections: 3,
+			expected:  2 * time.Minute,
+		},
+		{
+			desc:      "capped",
+			ejections: 5,
+			expected:  5 * time.Minute,
+		},
+		{
+			desc:      "many ejections",
+			ejections: 1000,
+			expected:  5 * time.Minute,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, ejectionTime(30*time.Second, 5*time.Minute, test.ejections))
+		})
+	}
+}
+
+func (lb *testLoadBalancer) removed() int {
+	lb.RLock()
+	defer lb.RUnlock()
+	return lb.numRemovedServers
+}
+
+func (lb *testLoadBalancer) upserted() int {
+	lb.RLock()
+	defer lb.RUnlock()
+	return lb.numUpsertedServers
+}
+
+// lockedGauge is a metrics.Gauge recording the last value set for each url label value.
+type lockedGauge struct {
+	mu     *sync.Mutex
+	url    string
+	values map[string]float64
+}
+
+func newLockedGauge() *lockedGauge {
+	return &lockedGauge{mu: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (g *lockedGauge) With(labelValues ...string) metrics.Gauge {
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "url" {
+			return &lockedGauge{mu: g.mu, url: labelValues[i+1], values: g.values}
+		}
+	}
+	return g
+}
+
+func (g *lockedGauge) Set(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[g.url] = value
+}
+
+func (g *lockedGauge) Add(delta float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[g.url] += delta
+}
+
+func (g *lockedGauge) value(u string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.values[u]
+}
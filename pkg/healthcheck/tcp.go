@@ -0,0 +1,189 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/tcp"
+)
+
+// maxExpectReadSize is the maximum number of bytes read from a server while looking for the expected answer.
+const maxExpectReadSize = 64 * 1024
+
+var tcpSingleton *TCPHealthCheck
+var tcpOnce sync.Once
+
+// TCPBalancer is the set of operations required to manage the list of servers in a
+// TCP load-balancer.
+type TCPBalancer interface {
+	AddWeightServer(handler tcp.Handler, weight *int)
+	RemoveServer(handler tcp.Handler) error
+}
+
+// TCPOptions are the public TCP health check options.
+type TCPOptions struct {
+	Port     int
+	Send     string
+	Expect   string
+	Interval time.Duration
+	Timeout  time.Duration
+	// ProxyProtocolVersion, if not zero, is the version of the PROXY protocol header (announcing a local connection)
+	// sent to the servers before the health check payload.
+	ProxyProtocolVersion int
+}
+
+func (opt TCPOptions) String() string {
+	return fmt.Sprintf("[Port: %d Send: %q Expect: %q Interval: %s Timeout: %s ProxyProtocolVersion: %d]",
+		opt.Port, opt.Send, opt.Expect, opt.Interval, opt.Timeout, opt.ProxyProtocolVersion)
+}
+
+// tcpBalancerServers is a TCPBalancer, with its server handlers keyed by server address.
+type tcpBalancerServers struct {
+	lb       TCPBalancer
+	handlers map[string]tcp.Handler
+}
+
+func (b tcpBalancerServers) enableServer(address string) {
+	if handler, ok := b.handlers[address]; ok {
+		b.lb.AddWeightServer(handler, nil)
+	}
+}
+
+func (b tcpBalancerServers) disableServer(address string) error {
+	if handler, ok := b.handlers[address]; ok {
+		return b.lb.RemoveServer(handler)
+	}
+	return nil
+}
+
+// TCPServiceConfig is the health check configuration of a TCP service.
+type TCPServiceConfig struct {
+	TCPOptions
+	addressServers
+}
+
+// NewTCPServiceConfig instantiates a new TCPServiceConfig.
+func NewTCPServiceConfig(options TCPOptions, serviceName string, info *runtime.TCPServiceInfo) *TCPServiceConfig {
+	var updateStatus func(address, status string)
+	if info != nil {
+		updateStatus = info.UpdateServerStatus
+	}
+
+	return &TCPServiceConfig{
+		TCPOptions:     options,
+		addressServers: newAddressServers(serviceName, updateStatus),
+	}
+}
+
+// AddBalancer adds a TCPBalancer to the health checked ones,
+// along with its server handlers keyed by server address.
+func (s *TCPServiceConfig) AddBalancer(lb TCPBalancer, handlers map[string]tcp.Handler) {
+	addresses := make([]string, 0, len(handlers))
+	for address := range handlers {
+		addresses = append(addresses, address)
+	}
+
+	s.addBalancer(tcpBalancerServers{lb: lb, handlers: handlers}, addresses)
+}
+
+// TCPHealthCheck struct
+type TCPHealthCheck struct {
+	Services map[string]*TCPServiceConfig
+	cancel   context.CancelFunc
+}
+
+// GetTCPHealthCheck returns the TCP health check which is guaranteed to be a singleton.
+func GetTCPHealthCheck() *TCPHealthCheck {
+	tcpOnce.Do(func() {
+		tcpSingleton = &TCPHealthCheck{
+			Services: make(map[string]*TCPServiceConfig),
+		}
+	})
+	return tcpSingleton
+}
+
+// SetServicesConfiguration sets the services configuration,
+// and stops the health checks of the previous configuration.
+func (hc *TCPHealthCheck) SetServicesConfiguration(parentCtx context.Context, services map[string]*TCPServiceConfig) {
+	hc.Services = services
+	if hc.cancel != nil {
+		hc.cancel()
+	}
+	ctx, cancel := context.WithCancel(parentCtx)
+	hc.cancel = cancel
+
+	for _, service := range services {
+		currentService := service
+		safe.Go(func() {
+			currentService.execute(ctx, currentService.Interval, func(ctx context.Context, address string) error {
+				return checkTCPHealth(ctx, address, currentService.TCPOptions)
+			})
+		})
+	}
+}
+
+// checkTCPHealth returns a nil error in case it was successful and otherwise
+// a non-nil error with a meaningful description why the health check failed.
+func checkTCPHealth(ctx context.Context, address string, options TCPOptions) error {
+	address, err := overridePort(address, options.Port)
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: options.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if options.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(options.Timeout)); err != nil {
+			return err
+		}
+	}
+
+	if options.ProxyProtocolVersion != 0 {
+		if err := tcp.WriteProxyProtocolHeader(conn, options.ProxyProtocolVersion, nil, nil); err != nil {
+			return fmt.Errorf("unable to send the PROXY protocol header: %w", err)
+		}
+	}
+
+	if options.Send != "" {
+		if _, err := conn.Write([]byte(options.Send)); err != nil {
+			return fmt.Errorf("unable to send the health check payload: %w", err)
+		}
+	}
+
+	if options.Expect == "" {
+		return nil
+	}
+
+	expect := []byte(options.Expect)
+	var received []byte
+	buf := make([]byte, 1024)
+	for len(received) < maxExpectReadSize {
+		n, err := conn.Read(buf)
+		received = append(received, buf[:n]...)
+		if bytes.Contains(received, expect) {
+			return nil
+		}
+
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("connection closed before receiving %q", options.Expect)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read the health check answer: %w", err)
+		}
+	}
+
+	return fmt.Errorf("%q not found in the first %d bytes of the answer", options.Expect, maxExpectReadSize)
+}
@@ -0,0 +1,209 @@
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTCPHealth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				reader := bufio.NewReader(conn)
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				// Skips the PROXY protocol v1 header.
+				if strings.HasPrefix(line, "PROXY ") {
+					if line, err = reader.ReadString('\n'); err != nil {
+						return
+					}
+				}
+				if line == "PING\n" {
+					_, _ = conn.Write([]byte("+PONG\n"))
+				}
+			}()
+		}
+	}()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().String()
+	require.NoError(t, closedListener.Close())
+
+	testCases := []struct {
+		desc          string
+		address       string
+		options       TCPOptions
+		expectedError bool
+	}{
+		{
+			desc:    "connection only",
+			address: listener.Addr().String(),
+		},
+		{
+			desc:          "connection refused",
+			address:       closedAddress,
+			expectedError: true,
+		},
+		{
+			desc:    "port override",
+			address: closedAddress,
+			options: TCPOptions{Port: mustAtoi(t, port)},
+		},
+		{
+			desc:    "expected answer",
+			address: listener.Addr().String(),
+			options: TCPOptions{Send: "PING\n", Expect: "PONG"},
+		},
+		{
+			desc:    "expected answer after PROXY protocol header",
+			address: listener.Addr().String(),
+			options: TCPOptions{Send: "PING\n", Expect: "PONG", ProxyProtocolVersion: 1},
+		},
+		{
+			desc:          "unexpected answer",
+			address:       listener.Addr().String(),
+			options:       TCPOptions{Send: "PING\n", Expect: "OK"},
+			expectedError: true,
+		},
+		{
+			desc:          "no answer",
+			address:       listener.Addr().String(),
+			options:       TCPOptions{Send: "PING", Expect: "PONG"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			options := test.options
+			options.Timeout = healthCheckTimeout
+
+			err := checkTCPHealth(context.Background(), test.address, options)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTCPSetServicesConfiguration(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	handler := &testTCPHandler{}
+	lb := &testTCPLoadBalancer{}
+	info := &runtime.TCPServiceInfo{}
+
+	serviceConfig := NewTCPServiceConfig(TCPOptions{Interval: healthCheckInterval, Timeout: healthCheckTimeout}, "service", info)
+	serviceConfig.AddBalancer(lb, map[string]tcp.Handler{address: handler})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hc := &TCPHealthCheck{}
+	hc.SetServicesConfiguration(ctx, map[string]*TCPServiceConfig{"service": serviceConfig})
+
+	time.Sleep(healthCheckInterval / 2)
+	assert.Equal(t, 0, lb.removed())
+	assert.Nil(t, info.GetAllStatus())
+
+	// The server stops listening, and is removed from the load-balancer.
+	require.NoError(t, listener.Close())
+
+	assert.Eventually(t, func() bool { return lb.removed() == 1 }, 2*healthCheckInterval, healthCheckInterval/10)
+	assert.Equal(t, map[string]string{address: serverDown}, info.GetAllStatus())
+
+	// The server listens again, and is added back to the load-balancer.
+	listener, err = net.Listen("tcp", address)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	assert.Eventually(t, func() bool { return lb.added() == 1 }, 2*healthCheckInterval, healthCheckInterval/10)
+	assert.Equal(t, map[string]string{address: serverUp}, info.GetAllStatus())
+	assert.Equal(t, 1, lb.removed())
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	t.Helper()
+
+	i, err := strconv.Atoi(s)
+	require.NoError(t, err)
+	return i
+}
+
+type testTCPHandler struct{}
+
+func (h *testTCPHandler) ServeTCP(conn tcp.WriteCloser) {}
+
+type testTCPLoadBalancer struct {
+	sync.Mutex
+	numAdded   int
+	numRemoved int
+}
+
+func (lb *testTCPLoadBalancer) AddWeightServer(handler tcp.Handler, weight *int) {
+	lb.Lock()
+	defer lb.Unlock()
+	lb.numAdded++
+}
+
+func (lb *testTCPLoadBalancer) RemoveServer(handler tcp.Handler) error {
+	lb.Lock()
+	defer lb.Unlock()
+	lb.numRemoved++
+	return nil
+}
+
+func (lb *testTCPLoadBalancer) added() int {
+	lb.Lock()
+	defer lb.Unlock()
+	return lb.numAdded
+}
+
+func (lb *testTCPLoadBalancer) removed() int {
+	lb.Lock()
+	defer lb.Unlock()
+	return lb.numRemoved
+}
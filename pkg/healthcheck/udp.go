@@ -0,0 +1,164 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/udp"
+)
+
+// udpReceiveMTU is the maximum size of a health check answer datagram.
+const udpReceiveMTU = 8192
+
+var udpSingleton *UDPHealthCheck
+var udpOnce sync.Once
+
+// UDPBalancer is the set of operations required to manage the list of servers in a
+// UDP load-balancer.
+type UDPBalancer interface {
+	AddWeightedServer(handler udp.Handler, weight *int)
+	RemoveServer(handler udp.Handler) error
+}
+
+// UDPOptions are the public UDP health check options.
+type UDPOptions struct {
+	Port     int
+	Send     string
+	Expect   string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+func (opt UDPOptions) String() string {
+	return fmt.Sprintf("[Port: %d Send: %q Expect: %q Interval: %s Timeout: %s]", opt.Port, opt.Send, opt.Expect, opt.Interval, opt.Timeout)
+}
+
+// udpBalancerServers is a UDPBalancer, with its server handlers keyed by server address.
+type udpBalancerServers struct {
+	lb       UDPBalancer
+	handlers map[string]udp.Handler
+}
+
+func (b udpBalancerServers) enableServer(address string) {
+	if handler, ok := b.handlers[address]; ok {
+		b.lb.AddWeightedServer(handler, nil)
+	}
+}
+
+func (b udpBalancerServers) disableServer(address string) error {
+	if handler, ok := b.handlers[address]; ok {
+		return b.lb.RemoveServer(handler)
+	}
+	return nil
+}
+
+// UDPServiceConfig is the health check configuration of a UDP service.
+type UDPServiceConfig struct {
+	UDPOptions
+	addressServers
+}
+
+// NewUDPServiceConfig instantiates a new UDPServiceConfig.
+func NewUDPServiceConfig(options UDPOptions, serviceName string, info *runtime.UDPServiceInfo) *UDPServiceConfig {
+	var updateStatus func(address, status string)
+	if info != nil {
+		updateStatus = info.UpdateServerStatus
+	}
+
+	return &UDPServiceConfig{
+		UDPOptions:     options,
+		addressServers: newAddressServers(serviceName, updateStatus),
+	}
+}
+
+// AddBalancer adds a UDPBalancer to the health checked ones,
+// along with its server handlers keyed by server address.
+func (s *UDPServiceConfig) AddBalancer(lb UDPBalancer, handlers map[string]udp.Handler) {
+	addresses := make([]string, 0, len(handlers))
+	for address := range handlers {
+		addresses = append(addresses, address)
+	}
+
+	s.addBalancer(udpBalancerServers{lb: lb, handlers: handlers}, addresses)
+}
+
+// UDPHealthCheck struct
+type UDPHealthCheck struct {
+	Services map[string]*UDPServiceConfig
+	cancel   context.CancelFunc
+}
+
+// GetUDPHealthCheck returns the UDP health check which is guaranteed to be a singleton.
+func GetUDPHealthCheck() *UDPHealthCheck {
+	udpOnce.Do(func() {
+		udpSingleton = &UDPHealthCheck{
+			Services: make(map[string]*UDPServiceConfig),
+		}
+	})
+	return udpSingleton
+}
+
+// SetServicesConfiguration sets the services configuration,
+// and stops the health checks of the previous configuration.
+func (hc *UDPHealthCheck) SetServicesConfiguration(parentCtx context.Context, services map[string]*UDPServiceConfig) {
+	hc.Services = services
+	if hc.cancel != nil {
+		hc.cancel()
+	}
+	ctx, cancel := context.WithCancel(parentCtx)
+	hc.cancel = cancel
+
+	for _, service := range services {
+		currentService := service
+		safe.Go(func() {
+			currentService.execute(ctx, currentService.Interval, func(ctx context.Context, address string) error {
+				return checkUDPHealth(ctx, address, currentService.UDPOptions)
+			})
+		})
+	}
+}
+
+// checkUDPHealth returns a nil error in case it was successful and otherwise
+// a non-nil error with a meaningful description why the health check failed.
+// As UDP is connectionless, the server is considered healthy only if it answers the probe.
+func checkUDPHealth(ctx context.Context, address string, options UDPOptions) error {
+	address, err := overridePort(address, options.Port)
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: options.Timeout}
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return fmt.Errorf("unable to reach the server: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if options.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(options.Timeout)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := conn.Write([]byte(options.Send)); err != nil {
+		return fmt.Errorf("unable to send the health check payload: %w", err)
+	}
+
+	buf := make([]byte, udpReceiveMTU)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return fmt.Errorf("no answer to the health check payload: %w", err)
+		}
+
+		if options.Expect == "" || bytes.Contains(buf[:n], []byte(options.Expect)) {
+			return nil
+		}
+	}
+}
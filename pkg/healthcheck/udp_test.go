@@ -0,0 +1,158 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/udp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUDPHealth(t *testing.T) {
+	pConn := newUDPPingServer(t, "127.0.0.1:0")
+	defer pConn.Close()
+
+	_, port, err := net.SplitHostPort(pConn.LocalAddr().String())
+	require.NoError(t, err)
+
+	closedConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedConn.LocalAddr().String()
+	require.NoError(t, closedConn.Close())
+
+	testCases := []struct {
+		desc          string
+		address       string
+		options       UDPOptions
+		expectedError bool
+	}{
+		{
+			desc:    "any answer",
+			address: pConn.LocalAddr().String(),
+			options: UDPOptions{Send: "PING"},
+		},
+		{
+			desc:    "expected answer",
+			address: pConn.LocalAddr().String(),
+			options: UDPOptions{Send: "PING", Expect: "PONG"},
+		},
+		{
+			desc:    "port override",
+			address: closedAddress,
+			options: UDPOptions{Port: mustAtoi(t, port), Send: "PING", Expect: "PONG"},
+		},
+		{
+			desc:          "unexpected answer",
+			address:       pConn.LocalAddr().String(),
+			options:       UDPOptions{Send: "PING", Expect: "OK"},
+			expectedError: true,
+		},
+		{
+			desc:          "no answer",
+			address:       pConn.LocalAddr().String(),
+			options:       UDPOptions{Send: "HELLO"},
+			expectedError: true,
+		},
+		{
+			desc:          "server not listening",
+			address:       closedAddress,
+			options:       UDPOptions{Send: "PING"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			options := test.options
+			options.Timeout = healthCheckTimeout
+
+			err := checkUDPHealth(context.Background(), test.address, options)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUDPSetServicesConfiguration(t *testing.T) {
+	pConn := newUDPPingServer(t, "127.0.0.1:0")
+	address := pConn.LocalAddr().String()
+
+	handler := &testUDPHandler{}
+	lb := &testTCPLoadBalancer{}
+	info := &runtime.UDPServiceInfo{}
+
+	serviceConfig := NewUDPServiceConfig(UDPOptions{Send: "PING", Expect: "PONG", Interval: healthCheckInterval, Timeout: healthCheckTimeout}, "service", info)
+	serviceConfig.AddBalancer(testUDPLoadBalancer{lb}, map[string]udp.Handler{address: handler})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hc := &UDPHealthCheck{}
+	hc.SetServicesConfiguration(ctx, map[string]*UDPServiceConfig{"service": serviceConfig})
+
+	time.Sleep(healthCheckInterval / 2)
+	assert.Equal(t, 0, lb.removed())
+	assert.Nil(t, info.GetAllStatus())
+
+	// The server stops answering, and is removed from the load-balancer.
+	require.NoError(t, pConn.Close())
+
+	assert.Eventually(t, func() bool { return lb.removed() == 1 }, 3*healthCheckInterval, healthCheckInterval/10)
+	assert.Equal(t, map[string]string{address: serverDown}, info.GetAllStatus())
+
+	// The server answers again, and is added back to the load-balancer.
+	pConn = newUDPPingServer(t, address)
+	defer pConn.Close()
+
+	assert.Eventually(t, func() bool { return lb.added() == 1 }, 3*healthCheckInterval, healthCheckInterval/10)
+	assert.Equal(t, map[string]string{address: serverUp}, info.GetAllStatus())
+	assert.Equal(t, 1, lb.removed())
+}
+
+// newUDPPingServer starts a UDP server answering PONG to PING.
+func newUDPPingServer(t *testing.T, address string) net.PacketConn {
+	t.Helper()
+
+	pConn, err := net.ListenPacket("udp", address)
+	require.NoError(t, err)
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := pConn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if string(buf[:n]) == "PING" {
+				_, _ = pConn.WriteTo([]byte("PONG"), addr)
+			}
+		}
+	}()
+
+	return pConn
+}
+
+type testUDPHandler struct{}
+
+func (h *testUDPHandler) ServeUDP(conn *udp.Conn) {}
+
+// testUDPLoadBalancer adapts a testTCPLoadBalancer to the UDPBalancer interface.
+type testUDPLoadBalancer struct {
+	*testTCPLoadBalancer
+}
+
+func (lb testUDPLoadBalancer) AddWeightedServer(handler udp.Handler, weight *int) {
+	lb.testTCPLoadBalancer.AddWeightServer(nil, weight)
+}
+
+func (lb testUDPLoadBalancer) RemoveServer(handler udp.Handler) error {
+	return lb.testTCPLoadBalancer.RemoveServer(nil)
+}
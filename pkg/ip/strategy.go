@@ -66,3 +66,41 @@ func (s *CheckerStrategy) GetIP(req *http.Request) string {
 	}
 	return ""
 }
+
+// ForwardedStrategy a strategy based on the trust in the forwarded headers.
+// The X-Forwarded-For header is only used if the remote address is trusted (or if Insecure is set),
+// in which case the rightmost IP which is not trusted is returned.
+type ForwardedStrategy struct {
+	Insecure bool
+	Checker  *Checker
+}
+
+// GetIP return the selected IP
+func (s *ForwardedStrategy) GetIP(req *http.Request) string {
+	remoteAddr := (&RemoteAddrStrategy{}).GetIP(req)
+	if !s.Insecure && !s.isTrusted(remoteAddr) {
+		return remoteAddr
+	}
+
+	xff := strings.Join(req.Header.Values(xForwardedFor), ",")
+	if strings.TrimSpace(xff) == "" {
+		return remoteAddr
+	}
+
+	xffs := strings.Split(xff, ",")
+	for i := len(xffs) - 1; i > 0; i-- {
+		xffTrimmed := strings.TrimSpace(xffs[i])
+		if !s.isTrusted(xffTrimmed) {
+			return xffTrimmed
+		}
+	}
+	return strings.TrimSpace(xffs[0])
+}
+
+func (s *ForwardedStrategy) isTrusted(addr string) bool {
+	if s.Checker == nil {
+		return false
+	}
+	contain, _ := s.Checker.Contains(addr)
+	return contain
+}
@@ -123,3 +123,71 @@ func TestExcludedIPsStrategy_GetIP(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardedStrategy_GetIP(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		insecure      bool
+		trustedIPs    []string
+		remoteAddr    string
+		xForwardedFor string
+		expected      string
+	}{
+		{
+			desc:          "Untrusted remote address",
+			trustedIPs:    []string{"10.0.0.0/8"},
+			remoteAddr:    "192.0.2.1:1234",
+			xForwardedFor: "10.0.0.4",
+			expected:      "192.0.2.1",
+		},
+		{
+			desc:       "Trusted remote address without X-Forwarded-For",
+			trustedIPs: []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.1:1234",
+			expected:   "10.0.0.1",
+		},
+		{
+			desc:          "Trusted remote address",
+			trustedIPs:    []string{"10.0.0.0/8"},
+			remoteAddr:    "10.0.0.1:1234",
+			xForwardedFor: "192.0.2.3, 192.0.2.2, 10.0.0.2",
+			expected:      "192.0.2.2",
+		},
+		{
+			desc:          "Trusted remote address and only trusted hops",
+			trustedIPs:    []string{"10.0.0.0/8"},
+			remoteAddr:    "10.0.0.1:1234",
+			xForwardedFor: "10.0.0.3, 10.0.0.2",
+			expected:      "10.0.0.3",
+		},
+		{
+			desc:          "Insecure",
+			insecure:      true,
+			remoteAddr:    "192.0.2.1:1234",
+			xForwardedFor: "192.0.2.3, 192.0.2.2",
+			expected:      "192.0.2.2",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			strategy := ForwardedStrategy{Insecure: test.insecure}
+			if len(test.trustedIPs) > 0 {
+				checker, err := NewChecker(test.trustedIPs)
+				require.NoError(t, err)
+				strategy.Checker = checker
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+			req.RemoteAddr = test.remoteAddr
+			if test.xForwardedFor != "" {
+				req.Header.Set(xForwardedFor, test.xForwardedFor)
+			}
+			actual := strategy.GetIP(req)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
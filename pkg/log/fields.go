@@ -2,15 +2,16 @@ package log
 
 // Log entry name
 const (
-	EntryPointName      = "entryPointName"
-	RouterName          = "routerName"
-	Rule                = "rule"
-	MiddlewareName      = "middlewareName"
-	MiddlewareType      = "middlewareType"
-	ProviderName        = "providerName"
-	ServiceName         = "serviceName"
-	MetricsProviderName = "metricsProviderName"
-	TracingProviderName = "tracingProviderName"
-	ServerName          = "serverName"
-	TLSStoreName        = "tlsStoreName"
+	EntryPointName       = "entryPointName"
+	RouterName           = "routerName"
+	Rule                 = "rule"
+	MiddlewareName       = "middlewareName"
+	MiddlewareType       = "middlewareType"
+	ProviderName         = "providerName"
+	ServiceName          = "serviceName"
+	MetricsProviderName  = "metricsProviderName"
+	TracingProviderName  = "tracingProviderName"
+	ServerName           = "serverName"
+	TLSStoreName         = "tlsStoreName"
+	ServersTransportName = "serversTransportName"
 )
@@ -32,6 +32,8 @@ const (
 	ddEntryPointOpenConnsName     = "entrypoint.connections.open"
 	ddOpenConnsName               = "service.connections.open"
 	ddServerUpName                = "service.server.up"
+	ddMirrorSkippedTotalName      = "service.mirror.skipped.total"
+	ddHedgedRequestsTotalName     = "service.hedged.requests.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -61,6 +63,8 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = datadogClient.NewGauge(ddOpenConnsName)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServerUpName)
+		registry.serviceMirrorSkippedCounter = datadogClient.NewCounter(ddMirrorSkippedTotalName, 1.0)
+		registry.serviceHedgedRequestsCounter = datadogClient.NewCounter(ddHedgedRequestsTotalName, 1.0)
 	}
 
 	return registry
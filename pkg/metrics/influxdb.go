@@ -38,6 +38,8 @@ const (
 	influxDBEntryPointOpenConnsName     = "traefik.entrypoint.connections.open"
 	influxDBOpenConnsName               = "traefik.service.connections.open"
 	influxDBServerUpName                = "traefik.service.server.up"
+	influxDBMirrorSkippedTotalName      = "traefik.service.mirror.skipped.total"
+	influxDBHedgedRequestsTotalName     = "traefik.service.hedged.requests.total"
 )
 
 const (
@@ -75,6 +77,8 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.serviceRetriesCounter = influxDBClient.NewCounter(influxDBRetriesTotalName)
 		registry.serviceOpenConnsGauge = influxDBClient.NewGauge(influxDBOpenConnsName)
 		registry.serviceServerUpGauge = influxDBClient.NewGauge(influxDBServerUpName)
+		registry.serviceMirrorSkippedCounter = influxDBClient.NewCounter(influxDBMirrorSkippedTotalName)
+		registry.serviceHedgedRequestsCounter = influxDBClient.NewCounter(influxDBHedgedRequestsTotalName)
 	}
 
 	return registry
@@ -35,6 +35,8 @@ func TestInfluxDB(t *testing.T) {
 		`(traefik\.config\.reload\.total(?:[a-z=0-9A-Z,]+)? count=1) [\d]{19}`,
 		`(traefik\.config\.reload\.total\.failure(?:[a-z=0-9A-Z,]+)? count=1) [\d]{19}`,
 		`(traefik\.service\.server\.up,service=test(?:[a-z=0-9A-Z,]+)?,url=http://127.0.0.1 value=1) [\d]{19}`,
+		`(traefik\.service\.mirror\.skipped\.total,service=test count=1) [\d]{19}`,
+		`(traefik\.service\.hedged\.requests\.total,service=test count=1) [\d]{19}`,
 	}
 
 	msgService := udp.ReceiveString(t, func() {
@@ -46,6 +48,8 @@ func TestInfluxDB(t *testing.T) {
 		influxDBRegistry.ConfigReloadsCounter().Add(1)
 		influxDBRegistry.ConfigReloadsFailureCounter().Add(1)
 		influxDBRegistry.ServiceServerUpGauge().With("service", "test", "url", "http://127.0.0.1").Set(1)
+		influxDBRegistry.ServiceMirrorSkippedCounter().With("service", "test").Add(1)
+		influxDBRegistry.ServiceHedgedRequestsCounter().With("service", "test").Add(1)
 	})
 
 	assertMessage(t, msgService, expectedService)
@@ -94,6 +98,8 @@ func TestInfluxDBHTTP(t *testing.T) {
 		`(traefik\.config\.reload\.total(?:[a-z=0-9A-Z,]+)? count=1) [\d]{19}`,
 		`(traefik\.config\.reload\.total\.failure(?:[a-z=0-9A-Z,]+)? count=1) [\d]{19}`,
 		`(traefik\.service\.server\.up,service=test(?:[a-z=0-9A-Z,]+)?,url=http://127.0.0.1 value=1) [\d]{19}`,
+		`(traefik\.service\.mirror\.skipped\.total,service=test count=1) [\d]{19}`,
+		`(traefik\.service\.hedged\.requests\.total,service=test count=1) [\d]{19}`,
 	}
 
 	influxDBRegistry.ServiceReqsCounter().With("service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
@@ -104,6 +110,8 @@ func TestInfluxDBHTTP(t *testing.T) {
 	influxDBRegistry.ConfigReloadsCounter().Add(1)
 	influxDBRegistry.ConfigReloadsFailureCounter().Add(1)
 	influxDBRegistry.ServiceServerUpGauge().With("service", "test", "url", "http://127.0.0.1").Set(1)
+	influxDBRegistry.ServiceMirrorSkippedCounter().With("service", "test").Add(1)
+	influxDBRegistry.ServiceHedgedRequestsCounter().With("service", "test").Add(1)
 	msgService := <-c
 
 	assertMessage(t, *msgService, expectedService)
@@ -29,6 +29,8 @@ type Registry interface {
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+	ServiceMirrorSkippedCounter() metrics.Counter
+	ServiceHedgedRequestsCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -53,6 +55,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var serviceMirrorSkippedCounter []metrics.Counter
+	var serviceHedgedRequestsCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -91,11 +95,17 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ServiceMirrorSkippedCounter() != nil {
+			serviceMirrorSkippedCounter = append(serviceMirrorSkippedCounter, r.ServiceMirrorSkippedCounter())
+		}
+		if r.ServiceHedgedRequestsCounter() != nil {
+			serviceHedgedRequestsCounter = append(serviceHedgedRequestsCounter, r.ServiceHedgedRequestsCounter())
+		}
 	}
 
 	return &standardRegistry{
 		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
+		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0 || len(serviceMirrorSkippedCounter) > 0 || len(serviceHedgedRequestsCounter) > 0,
 		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
 		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
 		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
@@ -108,6 +118,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
 		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		serviceMirrorSkippedCounter:    multi.NewCounter(serviceMirrorSkippedCounter...),
+		serviceHedgedRequestsCounter:   multi.NewCounter(serviceHedgedRequestsCounter...),
 	}
 }
 
@@ -126,6 +138,8 @@ type standardRegistry struct {
 	serviceOpenConnsGauge          metrics.Gauge
 	serviceRetriesCounter          metrics.Counter
 	serviceServerUpGauge           metrics.Gauge
+	serviceMirrorSkippedCounter    metrics.Counter
+	serviceHedgedRequestsCounter   metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -183,3 +197,11 @@ func (r *standardRegistry) ServiceRetriesCounter() metrics.Counter {
 func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
+
+func (r *standardRegistry) ServiceMirrorSkippedCounter() metrics.Counter {
+	return r.serviceMirrorSkippedCounter
+}
+
+func (r *standardRegistry) ServiceHedgedRequestsCounter() metrics.Counter {
+	return r.serviceHedgedRequestsCounter
+}
@@ -36,12 +36,14 @@ const (
 	// service level.
 
 	// MetricServicePrefix prefix of all service metric names
-	MetricServicePrefix     = MetricNamePrefix + "service_"
-	serviceReqsTotalName    = MetricServicePrefix + "requests_total"
-	serviceReqDurationName  = MetricServicePrefix + "request_duration_seconds"
-	serviceOpenConnsName    = MetricServicePrefix + "open_connections"
-	serviceRetriesTotalName = MetricServicePrefix + "retries_total"
-	serviceServerUpName     = MetricServicePrefix + "server_up"
+	MetricServicePrefix            = MetricNamePrefix + "service_"
+	serviceReqsTotalName           = MetricServicePrefix + "requests_total"
+	serviceReqDurationName         = MetricServicePrefix + "request_duration_seconds"
+	serviceOpenConnsName           = MetricServicePrefix + "open_connections"
+	serviceRetriesTotalName        = MetricServicePrefix + "retries_total"
+	serviceServerUpName            = MetricServicePrefix + "server_up"
+	serviceMirrorSkippedTotalName  = MetricServicePrefix + "mirror_skipped_total"
+	serviceHedgedRequestsTotalName = MetricServicePrefix + "hedged_requests_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -177,6 +179,14 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
+		serviceMirrorSkipped := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceMirrorSkippedTotalName,
+			Help: "How many requests were not mirrored by a mirroring service, because their body was too large.",
+		}, []string{"service"})
+		serviceHedgedRequests := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceHedgedRequestsTotalName,
+			Help: "How many hedged requests were sent to a second server of a service, because the first one was too slow to answer.",
+		}, []string{"service"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			serviceReqs.cv.Describe,
@@ -184,6 +194,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceOpenConns.gv.Describe,
 			serviceRetries.cv.Describe,
 			serviceServerUp.gv.Describe,
+			serviceMirrorSkipped.cv.Describe,
+			serviceHedgedRequests.cv.Describe,
 		}...)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -191,6 +203,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceOpenConnsGauge = serviceOpenConns
 		reg.serviceRetriesCounter = serviceRetries
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.serviceMirrorSkippedCounter = serviceMirrorSkipped
+		reg.serviceHedgedRequestsCounter = serviceHedgedRequests
 	}
 
 	return reg
@@ -149,6 +149,14 @@ func TestPrometheus(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		ServiceMirrorSkippedCounter().
+		With("service", "service1").
+		Add(1)
+	prometheusRegistry.
+		ServiceHedgedRequestsCounter().
+		With("service", "service1").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -248,6 +256,20 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: serviceMirrorSkippedTotalName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildCounterAssert(t, serviceMirrorSkippedTotalName, 1),
+		},
+		{
+			name: serviceHedgedRequestsTotalName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildCounterAssert(t, serviceHedgedRequestsTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -27,6 +27,8 @@ const (
 	statsdEntryPointOpenConnsName     = "entrypoint.connections.open"
 	statsdOpenConnsName               = "service.connections.open"
 	statsdServerUpName                = "service.server.up"
+	statsdMirrorSkippedTotalName      = "service.mirror.skipped.total"
+	statsdHedgedRequestsTotalName     = "service.hedged.requests.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -66,6 +68,8 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = statsdClient.NewGauge(statsdOpenConnsName)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServerUpName)
+		registry.serviceMirrorSkippedCounter = statsdClient.NewCounter(statsdMirrorSkippedTotalName, 1.0)
+		registry.serviceHedgedRequestsCounter = statsdClient.NewCounter(statsdHedgedRequestsTotalName, 1.0)
 	}
 
 	return registry
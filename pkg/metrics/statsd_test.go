@@ -33,6 +33,8 @@ func TestStatsD(t *testing.T) {
 		"traefik.entrypoint.request.duration:10000.000000|ms",
 		"traefik.entrypoint.connections.open:1.000000|g\n",
 		"traefik.service.server.up:1.000000|g\n",
+		"traefik.service.mirror.skipped.total:1.000000|c\n",
+		"traefik.service.hedged.requests.total:1.000000|c\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -47,6 +49,8 @@ func TestStatsD(t *testing.T) {
 		statsdRegistry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		statsdRegistry.EntryPointOpenConnsGauge().With("entrypoint", "test").Set(1)
 		statsdRegistry.ServiceServerUpGauge().With("service:test", "url", "http://127.0.0.1").Set(1)
+		statsdRegistry.ServiceMirrorSkippedCounter().With("service", "test").Add(1)
+		statsdRegistry.ServiceHedgedRequestsCounter().With("service", "test").Add(1)
 	})
 }
 
@@ -73,6 +77,8 @@ func TestStatsDWithPrefix(t *testing.T) {
 		"testPrefix.entrypoint.request.duration:10000.000000|ms",
 		"testPrefix.entrypoint.connections.open:1.000000|g\n",
 		"testPrefix.service.server.up:1.000000|g\n",
+		"testPrefix.service.mirror.skipped.total:1.000000|c\n",
+		"testPrefix.service.hedged.requests.total:1.000000|c\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -87,5 +93,7 @@ func TestStatsDWithPrefix(t *testing.T) {
 		statsdRegistry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		statsdRegistry.EntryPointOpenConnsGauge().With("entrypoint", "test").Set(1)
 		statsdRegistry.ServiceServerUpGauge().With("service:test", "url", "http://127.0.0.1").Set(1)
+		statsdRegistry.ServiceMirrorSkippedCounter().With("service", "test").Add(1)
+		statsdRegistry.ServiceHedgedRequestsCounter().With("service", "test").Add(1)
 	})
 }
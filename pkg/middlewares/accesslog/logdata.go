@@ -70,6 +70,10 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// CacheStatus is the map key used for the status of the response in the cache (HIT, STALE, MISS or BYPASS).
+	CacheStatus = "CacheStatus"
+	// RequestID is the map key used for the ID of the request, set by the request ID middleware.
+	RequestID = "RequestID"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -111,6 +115,8 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[CacheStatus] = struct{}{}
+	allCoreKeys[RequestID] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
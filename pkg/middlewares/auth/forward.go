@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -22,20 +25,31 @@ const (
 	xForwardedURI     = "X-Forwarded-Uri"
 	xForwardedMethod  = "X-Forwarded-Method"
 	forwardedTypeName = "ForwardedAuthType"
+
+	// defaultMaxBodySize is the maximum size of the forwarded bodies when none is configured,
+	// as they are buffered in memory.
+	defaultMaxBodySize int64 = 1 << 20
 )
 
+var errBodyTooLarge = errors.New("request body too large")
+
 type forwardAuth struct {
 	address             string
 	authResponseHeaders []string
+	authRequestHeaders  []string
 	next                http.Handler
 	name                string
 	client              http.Client
 	trustForwardHeader  bool
+	forwardBody         bool
+	maxBodySize         int64
+	cache               *decisionCache
 }
 
 // NewForward creates a forward auth middleware.
 func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAuth, name string) (http.Handler, error) {
-	log.FromContext(middlewares.GetLoggerCtx(ctx, name, forwardedTypeName)).Debug("Creating middleware")
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, forwardedTypeName))
+	logger.Debug("Creating middleware")
 
 	fa := &forwardAuth{
 		address:             config.Address,
@@ -43,6 +57,23 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 		next:                next,
 		name:                name,
 		trustForwardHeader:  config.TrustForwardHeader,
+		forwardBody:         config.ForwardBody,
+		maxBodySize:         defaultMaxBodySize,
+	}
+
+	for _, header := range config.AuthRequestHeaders {
+		fa.authRequestHeaders = append(fa.authRequestHeaders, http.CanonicalHeaderKey(header))
+	}
+
+	if config.MaxBodySize != nil {
+		fa.maxBodySize = *config.MaxBodySize
+	}
+
+	// The decision depends on the body when it is forwarded, so it cannot be reused for another request.
+	if config.Cache != nil && config.ForwardBody {
+		logger.Warn("The authentication decisions are not cached, as the request body is forwarded")
+	} else if config.Cache != nil {
+		fa.cache = newDecisionCache(time.Duration(config.Cache.TTL), config.Cache.KeyHeaders)
 	}
 
 	// Ensure our request client does not follow redirects
@@ -74,7 +105,42 @@ func (fa *forwardAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
 func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), fa.name, forwardedTypeName))
 
-	forwardReq, err := http.NewRequest(http.MethodGet, fa.address, nil)
+	var cacheKey string
+	if fa.cache != nil {
+		cacheKey = fa.cache.key(req, fa.trustForwardHeader)
+		if header, ok := fa.cache.get(cacheKey); ok {
+			logger.Debug("Authentication decision found in cache")
+			fa.serveNext(rw, req, header)
+			return
+		}
+	}
+
+	var forwardBody io.Reader
+	if fa.forwardBody {
+		bodyBytes, err := readBody(req, fa.maxBodySize)
+		if errors.Is(err, errBodyTooLarge) {
+			logMessage := fmt.Sprintf("Request body is too large, maxBodySize: %d", fa.maxBodySize)
+			logger.Debug(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			rw.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			logMessage := fmt.Sprintf("Error reading request body. Cause: %s", err)
+			logger.Debug(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if bodyBytes != nil {
+			forwardBody = bytes.NewReader(bodyBytes)
+		}
+	}
+
+	forwardReq, err := http.NewRequest(http.MethodGet, fa.address, forwardBody)
 	tracing.LogRequest(tracing.GetSpan(req), forwardReq)
 	if err != nil {
 		logMessage := fmt.Sprintf("Error calling %s. Cause %s", fa.address, err)
@@ -89,7 +155,7 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	// forwardReq.
 	tracing.InjectRequestHeaders(req)
 
-	writeHeader(req, forwardReq, fa.trustForwardHeader)
+	writeHeader(req, forwardReq, fa.trustForwardHeader, fa.authRequestHeaders)
 
 	forwardResponse, forwardErr := fa.client.Do(forwardReq)
 	if forwardErr != nil {
@@ -146,11 +212,21 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if cacheKey != "" {
+		fa.cache.set(cacheKey, forwardResponse.Header)
+	}
+
+	fa.serveNext(rw, req, forwardResponse.Header)
+}
+
+// serveNext forwards the authenticated request to the next handler,
+// with the selected headers of the authentication response.
+func (fa *forwardAuth) serveNext(rw http.ResponseWriter, req *http.Request, authHeader http.Header) {
 	for _, headerName := range fa.authResponseHeaders {
 		headerKey := http.CanonicalHeaderKey(headerName)
 		req.Header.Del(headerKey)
-		if len(forwardResponse.Header[headerKey]) > 0 {
-			req.Header[headerKey] = append([]string(nil), forwardResponse.Header[headerKey]...)
+		if len(authHeader[headerKey]) > 0 {
+			req.Header[headerKey] = append([]string(nil), authHeader[headerKey]...)
 		}
 	}
 
@@ -158,10 +234,40 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	fa.next.ServeHTTP(rw, req)
 }
 
-func writeHeader(req *http.Request, forwardReq *http.Request, trustForwardHeader bool) {
+// readBody reads the body of the request, up to maxSize bytes if it is not negative,
+// and replaces it so that it can be read again.
+func readBody(req *http.Request, maxSize int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	var reader io.Reader = req.Body
+	if maxSize >= 0 {
+		reader = io.LimitReader(req.Body, maxSize+1)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxSize >= 0 && int64(len(body)) > maxSize {
+		return nil, errBodyTooLarge
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func writeHeader(req *http.Request, forwardReq *http.Request, trustForwardHeader bool, allowedHeaders []string) {
 	utils.CopyHeaders(forwardReq.Header, req.Header)
 	utils.RemoveHeaders(forwardReq.Header, forward.HopHeaders...)
 
+	if len(allowedHeaders) > 0 {
+		forwardReq.Header = filterHeaders(forwardReq.Header, allowedHeaders)
+	}
+
 	if clientIP, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
 		if trustForwardHeader {
 			if prior, ok := req.Header[forward.XForwardedFor]; ok {
@@ -171,13 +277,9 @@ func writeHeader(req *http.Request, forwardReq *http.Request, trustForwardHeader
 		forwardReq.Header.Set(forward.XForwardedFor, clientIP)
 	}
 
-	xMethod := req.Header.Get(xForwardedMethod)
-	switch {
-	case xMethod != "" && trustForwardHeader:
+	if xMethod := forwardedValue(req, xForwardedMethod, req.Method, trustForwardHeader); xMethod != "" {
 		forwardReq.Header.Set(xForwardedMethod, xMethod)
-	case req.Method != "":
-		forwardReq.Header.Set(xForwardedMethod, req.Method)
-	default:
+	} else {
 		forwardReq.Header.Del(xForwardedMethod)
 	}
 
@@ -195,23 +297,35 @@ func writeHeader(req *http.Request, forwardReq *http.Request, trustForwardHeader
 		forwardReq.Header.Set(forward.XForwardedPort, xfp)
 	}
 
-	xfh := req.Header.Get(forward.XForwardedHost)
-	switch {
-	case xfh != "" && trustForwardHeader:
+	if xfh := forwardedValue(req, forward.XForwardedHost, req.Host, trustForwardHeader); xfh != "" {
 		forwardReq.Header.Set(forward.XForwardedHost, xfh)
-	case req.Host != "":
-		forwardReq.Header.Set(forward.XForwardedHost, req.Host)
-	default:
+	} else {
 		forwardReq.Header.Del(forward.XForwardedHost)
 	}
 
-	xfURI := req.Header.Get(xForwardedURI)
-	switch {
-	case xfURI != "" && trustForwardHeader:
+	if xfURI := forwardedValue(req, xForwardedURI, req.URL.RequestURI(), trustForwardHeader); xfURI != "" {
 		forwardReq.Header.Set(xForwardedURI, xfURI)
-	case req.URL.RequestURI() != "":
-		forwardReq.Header.Set(xForwardedURI, req.URL.RequestURI())
-	default:
+	} else {
 		forwardReq.Header.Del(xForwardedURI)
 	}
 }
+
+// forwardedValue returns the value of the given forwarded header of the request if it is trusted and set,
+// and the given value of the request itself otherwise.
+func forwardedValue(req *http.Request, name, value string, trustForwardHeader bool) string {
+	if forwarded := req.Header.Get(name); forwarded != "" && trustForwardHeader {
+		return forwarded
+	}
+	return value
+}
+
+// filterHeaders returns the given headers, with their canonical names, which are in the header.
+func filterHeaders(header http.Header, names []string) http.Header {
+	filtered := make(http.Header)
+	for _, name := range names {
+		if values, ok := header[name]; ok {
+			filtered[name] = values
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vulcand/oxy/forward"
+)
+
+const (
+	defaultDecisionCacheTTL = time.Minute
+
+	// maxCachedDecisions is the maximum number of decisions kept in a cache.
+	maxCachedDecisions = 10000
+)
+
+var defaultDecisionCacheKeyHeaders = []string{"Authorization", "Cookie"}
+
+// decision is a cached successful authentication.
+type decision struct {
+	// header is the header of the authentication response.
+	header http.Header
+	expiry time.Time
+}
+
+// decisionCache caches the successful forward authentications,
+// for the values of the request headers holding the credentials, and for the method, host and URI of the request.
+type decisionCache struct {
+	ttl        time.Duration
+	keyHeaders []string
+	now        func() time.Time
+
+	mu        sync.Mutex
+	decisions map[string]decision
+}
+
+func newDecisionCache(ttl time.Duration, keyHeaders []string) *decisionCache {
+	if ttl <= 0 {
+		ttl = defaultDecisionCacheTTL
+	}
+
+	if len(keyHeaders) == 0 {
+		keyHeaders = defaultDecisionCacheKeyHeaders
+	}
+
+	canonicalHeaders := make([]string, len(keyHeaders))
+	for i, header := range keyHeaders {
+		canonicalHeaders[i] = http.CanonicalHeaderKey(header)
+	}
+
+	return &decisionCache{
+		ttl:        ttl,
+		keyHeaders: canonicalHeaders,
+		now:        time.Now,
+		decisions:  make(map[string]decision),
+	}
+}
+
+// key returns the key of the decision for the request,
+// or an empty string if the request has none of the key headers, and its decision must not be cached.
+// The method, host and URI are the ones sent to the authentication server,
+// so that a decision is never reused for another resource.
+func (c *decisionCache) key(req *http.Request, trustForwardHeader bool) string {
+	hash := sha256.New()
+
+	for _, value := range []string{
+		forwardedValue(req, xForwardedMethod, req.Method, trustForwardHeader),
+		forwardedValue(req, forward.XForwardedHost, req.Host, trustForwardHeader),
+		forwardedValue(req, xForwardedURI, req.URL.RequestURI(), trustForwardHeader),
+	} {
+		hash.Write([]byte(value))
+		hash.Write([]byte{'\n'})
+	}
+
+	var found bool
+	for _, name := range c.keyHeaders {
+		values := req.Header[name]
+		if len(values) > 0 {
+			found = true
+		}
+
+		hash.Write([]byte(name))
+		for _, value := range values {
+			hash.Write([]byte{0})
+			hash.Write([]byte(value))
+		}
+		hash.Write([]byte{'\n'})
+	}
+
+	if !found {
+		return ""
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// get returns the header of the authentication response cached for the key, if any and not expired.
+func (c *decisionCache) get(key string) (http.Header, bool) {
+	if key == "" {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d, ok := c.decisions[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !c.now().Before(d.expiry) {
+		delete(c.decisions, key)
+		return nil, false
+	}
+
+	return d.header, true
+}
+
+// set caches the header of the authentication response for the key.
+// The expired decisions are evicted when the cache is full, and the decision is not cached if it is still full.
+func (c *decisionCache) set(key string, header http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	if len(c.decisions) >= maxCachedDecisions {
+		for k, d := range c.decisions {
+			if !now.Before(d.expiry) {
+				delete(c.decisions, k)
+			}
+		}
+
+		if len(c.decisions) >= maxCachedDecisions {
+			return
+		}
+	}
+
+	c.decisions[key] = decision{header: header.Clone(), expiry: now.Add(c.ttl)}
+}
@@ -7,12 +7,16 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	tracingMiddleware "github.com/containous/traefik/v2/pkg/middlewares/tracing"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
@@ -245,6 +249,7 @@ func Test_writeHeader(t *testing.T) {
 		headers                   map[string]string
 		trustForwardHeader        bool
 		emptyHost                 bool
+		authRequestHeaders        []string
 		expectedHeaders           map[string]string
 		checkForUnexpectedHeaders bool
 	}{
@@ -368,6 +373,23 @@ func Test_writeHeader(t *testing.T) {
 			},
 			checkForUnexpectedHeaders: true,
 		},
+		{
+			name: "filter the request headers",
+			headers: map[string]string{
+				"X-CustomHeader": "CustomHeader",
+				"Authorization":  "Bearer token",
+				"Cookie":         "session=value",
+			},
+			authRequestHeaders: []string{"Authorization"},
+			expectedHeaders: map[string]string{
+				"Authorization":      "Bearer token",
+				"X-Forwarded-Proto":  "http",
+				"X-Forwarded-Host":   "foo.bar",
+				"X-Forwarded-Uri":    "/path?q=1",
+				"X-Forwarded-Method": "GET",
+			},
+			checkForUnexpectedHeaders: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -383,7 +405,7 @@ func Test_writeHeader(t *testing.T) {
 
 			forwardReq := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/path?q=1", nil)
 
-			writeHeader(req, forwardReq, test.trustForwardHeader)
+			writeHeader(req, forwardReq, test.trustForwardHeader, test.authRequestHeaders)
 
 			actualHeaders := forwardReq.Header
 			expectedHeaders := test.expectedHeaders
@@ -440,3 +462,190 @@ type mockBackend struct {
 func (b *mockBackend) Setup(componentName string) (opentracing.Tracer, io.Closer, error) {
 	return b.Tracer, ioutil.NopCloser(nil), nil
 }
+
+func TestForwardAuthForwardBody(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		maxBodySize    *int64
+		body           string
+		expectedStatus int
+	}{
+		{
+			desc:           "default limit",
+			body:           "request body",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "body over the default limit",
+			body:           strings.Repeat("a", int(defaultMaxBodySize)+1),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:           "no limit",
+			maxBodySize:    int64Ptr(-1),
+			body:           strings.Repeat("a", int(defaultMaxBodySize)+1),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "body within the limit",
+			maxBodySize:    int64Ptr(12),
+			body:           "request body",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "body too large",
+			maxBodySize:    int64Ptr(11),
+			body:           "request body",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:           "no body",
+			maxBodySize:    int64Ptr(0),
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.body, string(body))
+			}))
+			defer server.Close()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.body, string(body))
+			})
+
+			middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
+				Address:     server.URL,
+				ForwardBody: true,
+				MaxBodySize: test.maxBodySize,
+			}, "authTest")
+			require.NoError(t, err)
+
+			var body io.Reader
+			if test.body != "" {
+				body = strings.NewReader(test.body)
+			}
+
+			recorder := httptest.NewRecorder()
+			middleware.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "http://foo.bar", body))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestForwardAuthCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-Auth-User", "user")
+	}))
+	defer server.Close()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "user", r.Header.Get("X-Auth-User"))
+	})
+
+	middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
+		Address:             server.URL,
+		AuthResponseHeaders: []string{"X-Auth-User"},
+		Cache: &dynamic.ForwardAuthCache{
+			TTL:        types.Duration(time.Minute),
+			KeyHeaders: []string{"authorization"},
+		},
+	}, "authTest")
+	require.NoError(t, err)
+
+	cache := middleware.(*forwardAuth).cache
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	serveRequest := func(method, target, authorization string) int {
+		req := httptest.NewRequest(method, target, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		req.Header.Set("X-Auth-User", "spoofed")
+
+		recorder := httptest.NewRecorder()
+		middleware.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	serve := func(authorization string) int {
+		return serveRequest(http.MethodGet, "http://foo.bar", authorization)
+	}
+
+	assert.Equal(t, http.StatusOK, serve("Bearer valid"))
+	assert.Equal(t, http.StatusOK, serve("Bearer valid"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// The failed authentications are not cached.
+	assert.Equal(t, http.StatusForbidden, serve("Bearer invalid"))
+	assert.Equal(t, http.StatusForbidden, serve("Bearer invalid"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// The requests without any of the key headers are not cached.
+	assert.Equal(t, http.StatusForbidden, serve(""))
+	assert.Equal(t, http.StatusForbidden, serve(""))
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+
+	// The decisions are specific to the method, host and URI of the request.
+	assert.Equal(t, http.StatusOK, serveRequest(http.MethodDelete, "http://foo.bar", "Bearer valid"))
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+	assert.Equal(t, http.StatusOK, serveRequest(http.MethodGet, "http://foo.bar/admin", "Bearer valid"))
+	assert.Equal(t, int32(7), atomic.LoadInt32(&calls))
+	assert.Equal(t, http.StatusOK, serveRequest(http.MethodGet, "http://bar.foo", "Bearer valid"))
+	assert.Equal(t, int32(8), atomic.LoadInt32(&calls))
+	assert.Equal(t, http.StatusOK, serveRequest(http.MethodGet, "http://foo.bar/admin", "Bearer valid"))
+	assert.Equal(t, int32(8), atomic.LoadInt32(&calls))
+
+	// The decisions expire.
+	now = now.Add(time.Minute)
+	assert.Equal(t, http.StatusOK, serve("Bearer valid"))
+	assert.Equal(t, int32(9), atomic.LoadInt32(&calls))
+}
+
+func TestForwardAuthCacheWithBody(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	middleware, err := NewForward(context.Background(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), dynamic.ForwardAuth{
+		Address:     server.URL,
+		ForwardBody: true,
+		Cache:       &dynamic.ForwardAuthCache{},
+	}, "authTest")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "http://foo.bar", strings.NewReader("foo"))
+		req.Header.Set("Authorization", "Bearer valid")
+
+		recorder := httptest.NewRecorder()
+		middleware.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
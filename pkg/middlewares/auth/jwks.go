@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	defaultJWKSRefreshInterval = 15 * time.Minute
+
+	// jwksMinRefreshInterval is the minimum interval between two fetches of a key set,
+	// e.g. when the tokens are signed with an unknown key, or when the key set can't be fetched.
+	jwksMinRefreshInterval = 10 * time.Second
+
+	jwksFetchTimeout = 10 * time.Second
+	jwksMaxSize      = 1 << 20
+)
+
+// jwks is a JSON Web Key Set fetched from a URL, cached and refreshed when it gets too old.
+type jwks struct {
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+	now             func() time.Time
+
+	mu          sync.Mutex
+	keys        []jose.JSONWebKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	// refreshing is the fetch in progress, if any, shared by all the callers.
+	refreshing *jwksRefresh
+}
+
+// jwksRefresh is a fetch of the key set, whose done channel is closed once it is over.
+type jwksRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+func newJWKS(url string, refreshInterval time.Duration) *jwks {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultJWKSRefreshInterval
+	}
+
+	return &jwks{
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: jwksFetchTimeout},
+		now:             time.Now,
+	}
+}
+
+// getKeys returns the signature keys of the set with the given key ID, or all of them if kid is empty.
+// If the set is too old, the cached keys are returned while it is fetched again in the background.
+// If the set does not contain the key yet, it is fetched again, and the call waits for it.
+func (j *jwks) getKeys(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	now := j.now()
+
+	j.mu.Lock()
+	keys := filterKeys(j.keys, kid)
+	stale := j.fetchedAt.IsZero() || now.Sub(j.fetchedAt) >= j.refreshInterval
+	var refresh *jwksRefresh
+	if len(keys) == 0 || stale {
+		refresh = j.startRefresh(now)
+	}
+	j.mu.Unlock()
+
+	if len(keys) > 0 {
+		return keys, nil
+	}
+
+	if refresh != nil {
+		select {
+		case <-refresh.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if refresh.err != nil {
+			return nil, refresh.err
+		}
+
+		j.mu.Lock()
+		keys = filterKeys(j.keys, kid)
+		j.mu.Unlock()
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key found with ID %q", kid)
+	}
+
+	return keys, nil
+}
+
+// startRefresh fetches the key set in the background, unless a fetch is already in progress,
+// or it has been attempted too recently, in which case it returns nil.
+// The previous keys are kept if it fails.
+// It must be called with the lock held.
+func (j *jwks) startRefresh(now time.Time) *jwksRefresh {
+	if j.refreshing != nil {
+		return j.refreshing
+	}
+
+	if !j.attemptedAt.IsZero() && now.Sub(j.attemptedAt) < jwksMinRefreshInterval {
+		return nil
+	}
+	j.attemptedAt = now
+
+	refresh := &jwksRefresh{done: make(chan struct{})}
+	j.refreshing = refresh
+
+	go func() {
+		defer close(refresh.done)
+
+		// The fetch does not depend on the request which triggered it, as it is shared by all the requests.
+		ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+		defer cancel()
+
+		keys, err := j.fetch(ctx)
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+
+		j.refreshing = nil
+
+		if err != nil {
+			refresh.err = fmt.Errorf("unable to fetch the JSON Web Key Set from %s: %w", j.url, err)
+			log.WithoutContext().Debug(refresh.err)
+			return
+		}
+
+		j.keys = keys
+		j.fetchedAt = now
+	}()
+
+	return refresh
+}
+
+func (j *jwks) fetch(ctx context.Context) ([]jose.JSONWebKey, error) {
+	req, err := http.NewRequest(http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := j.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, jwksMaxSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, err
+	}
+
+	return set.Keys, nil
+}
+
+// filterKeys returns the signature keys with the given key ID, or all of them if kid is empty.
+func filterKeys(keys []jose.JSONWebKey, kid string) []jose.JSONWebKey {
+	var filtered []jose.JSONWebKey
+	for _, key := range keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		if kid == "" || key.KeyID == kid {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,331 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	jwtTypeName = "JWT"
+
+	bearerPrefix = "Bearer "
+)
+
+type jwtAuth struct {
+	next          http.Handler
+	name          string
+	verifier      *tokenVerifier
+	forwardClaims map[string]string
+	removeHeader  bool
+}
+
+// NewJWT creates a JWT middleware.
+func NewJWT(ctx context.Context, next http.Handler, config dynamic.JWT, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, jwtTypeName)).Debug("Creating middleware")
+
+	verifier, err := newTokenVerifier(config.Keys, config.JWKSURL, time.Duration(config.JWKSRefreshInterval))
+	if err != nil {
+		return nil, err
+	}
+	verifier.issuer = config.Issuer
+	verifier.audience = config.Audience
+	verifier.requiredClaims = config.RequiredClaims
+
+	forwardClaims := make(map[string]string, len(config.ForwardClaims))
+	for header, claim := range config.ForwardClaims {
+		forwardClaims[http.CanonicalHeaderKey(header)] = claim
+	}
+
+	return &jwtAuth{
+		next:          next,
+		name:          name,
+		verifier:      verifier,
+		forwardClaims: forwardClaims,
+		removeHeader:  config.RemoveHeader,
+	}, nil
+}
+
+func (j *jwtAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return j.name, tracing.SpanKindNoneEnum
+}
+
+func (j *jwtAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), j.name, jwtTypeName))
+
+	// The claim headers sent by the client must not reach the service.
+	for header := range j.forwardClaims {
+		req.Header.Del(header)
+	}
+
+	token, ok := bearerToken(req)
+	if !ok {
+		logger.Debug("Authentication failed: no bearer token")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := j.verifier.verify(req.Context(), token)
+	if err != nil {
+		logger.Debugf("Authentication failed: %v", err)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+		rw.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	logger.Debug("Authentication succeeded")
+
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		req.URL.User = url.User(sub)
+
+		if logData := accesslog.GetLogData(req); logData != nil {
+			logData.Core[accesslog.ClientUsername] = sub
+		}
+	}
+
+	for header, claim := range j.forwardClaims {
+		if value, ok := claims[claim]; ok {
+			req.Header.Set(header, claimValue(value))
+		}
+	}
+
+	if j.removeHeader {
+		logger.Debug("Removing authorization header")
+		req.Header.Del(authorizationHeader)
+	}
+
+	j.next.ServeHTTP(rw, req)
+}
+
+// tokenVerifier verifies the signature and the claims of JSON Web Tokens.
+type tokenVerifier struct {
+	keys           []jose.JSONWebKey
+	jwks           *jwks
+	issuer         string
+	audience       string
+	requiredClaims []string
+	now            func() time.Time
+}
+
+func newTokenVerifier(keys []string, jwksURL string, jwksRefreshInterval time.Duration) (*tokenVerifier, error) {
+	if len(keys) == 0 && jwksURL == "" {
+		return nil, errors.New("no keys or JWKS URL configured")
+	}
+
+	v := &tokenVerifier{now: time.Now}
+
+	for _, key := range keys {
+		publicKeys, err := loadPublicKeys(key)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = append(v.keys, publicKeys...)
+	}
+
+	if jwksURL != "" {
+		v.jwks = newJWKS(jwksURL, jwksRefreshInterval)
+	}
+
+	return v, nil
+}
+
+// verify checks the signature and the claims of the token, and returns its claims.
+func (v *tokenVerifier) verify(ctx context.Context, token string) (map[string]interface{}, error) {
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, err
+	}
+	if len(tok.Headers) != 1 {
+		return nil, errors.New("the token must have a single signature")
+	}
+	header := tok.Headers[0]
+
+	keys := v.keys
+	if v.jwks != nil {
+		jwksKeys, err := v.jwks.getKeys(ctx, header.KeyID)
+		if err != nil && len(keys) == 0 {
+			return nil, err
+		}
+		keys = append(keys[:len(keys):len(keys)], jwksKeys...)
+	}
+
+	var claims jwt.Claims
+	var raw map[string]interface{}
+
+	var verified bool
+	for _, key := range keys {
+		if !isAllowedAlgorithm(key, header.Algorithm) {
+			continue
+		}
+
+		if err := tok.Claims(key.Key, &claims, &raw); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("invalid signature")
+	}
+
+	expected := jwt.Expected{Issuer: v.issuer, Time: v.now()}
+	if v.audience != "" {
+		expected.Audience = jwt.Audience{v.audience}
+	}
+
+	// The expiration is only validated if the claim is present, but a token without it would be valid forever.
+	if claims.Expiry == nil {
+		return nil, errors.New("missing claim \"exp\"")
+	}
+
+	if err := claims.ValidateWithLeeway(expected, jwt.DefaultLeeway); err != nil {
+		return nil, err
+	}
+
+	for _, claim := range v.requiredClaims {
+		if _, ok := raw[claim]; !ok {
+			return nil, fmt.Errorf("missing claim %q", claim)
+		}
+	}
+
+	return raw, nil
+}
+
+// isAllowedAlgorithm reports whether a token signed with the given algorithm can be verified with the key:
+// the algorithm must be the one of the key if it defines one, or one matching the type of the key otherwise.
+func isAllowedAlgorithm(key jose.JSONWebKey, alg string) bool {
+	if key.Algorithm != "" {
+		return key.Algorithm == alg
+	}
+
+	switch publicKey := key.Key.(type) {
+	case *rsa.PublicKey:
+		switch jose.SignatureAlgorithm(alg) {
+		case jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512:
+			return true
+		}
+	case *ecdsa.PublicKey:
+		switch publicKey.Curve {
+		case elliptic.P256():
+			return alg == string(jose.ES256)
+		case elliptic.P384():
+			return alg == string(jose.ES384)
+		case elliptic.P521():
+			return alg == string(jose.ES512)
+		}
+	case ed25519.PublicKey:
+		return alg == string(jose.EdDSA)
+	}
+
+	return false
+}
+
+// loadPublicKeys returns the public keys of the PEM encoded keys or certificates,
+// given as content or as the path to the file containing them.
+func loadPublicKeys(key string) ([]jose.JSONWebKey, error) {
+	data := []byte(key)
+	if !strings.HasPrefix(strings.TrimSpace(key), "-----BEGIN") {
+		var err error
+		data, err = ioutil.ReadFile(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var keys []jose.JSONWebKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var publicKey interface{}
+		var err error
+
+		switch block.Type {
+		case "PUBLIC KEY":
+			publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				publicKey = cert.PublicKey
+			}
+		default:
+			return nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, jose.JSONWebKey{Key: publicKey})
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded public key or certificate found")
+	}
+
+	return keys, nil
+}
+
+// bearerToken returns the bearer token of the Authorization header of the request.
+func bearerToken(req *http.Request) (string, bool) {
+	value := req.Header.Get(authorizationHeader)
+	if len(value) <= len(bearerPrefix) || !strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(value[len(bearerPrefix):])
+	return token, token != ""
+}
+
+// claimValue returns the value of a claim as a header value.
+func claimValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			values = append(values, claimValue(elem))
+		}
+		return strings.Join(values, ",")
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
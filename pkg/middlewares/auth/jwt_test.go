@@ -0,0 +1,521 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	now := time.Now()
+	defaultClaims := map[string]interface{}{
+		"iss": "issuer",
+		"aud": "audience",
+		"sub": "user",
+		"exp": now.Add(time.Hour).Unix(),
+	}
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.JWT
+		authorization  string
+		expectedStatus int
+		expectedHeader http.Header
+	}{
+		{
+			desc:           "no token",
+			config:         dynamic.JWT{Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "not a bearer token",
+			config:         dynamic.JWT{Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}},
+			authorization:  "Basic dGVzdDp0ZXN0",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "malformed token",
+			config:         dynamic.JWT{Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}},
+			authorization:  "Bearer foo.bar.baz",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "RSA key",
+			config:         dynamic.JWT{Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "EC key",
+			config:         dynamic.JWT{Keys: []string{publicKeyPEM(t, &ecKey.PublicKey)}},
+			authorization:  "Bearer " + signToken(t, jose.ES256, ecKey, "", defaultClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "one of several keys",
+			config:         dynamic.JWT{Keys: []string{publicKeyPEM(t, &otherKey.PublicKey), publicKeyPEM(t, &rsaKey.PublicKey)}},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "wrong key",
+			config:         dynamic.JWT{Keys: []string{publicKeyPEM(t, &otherKey.PublicKey)}},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:   "HMAC signed with the public key",
+			config: dynamic.JWT{Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}},
+			authorization: "Bearer " + signToken(t, jose.HS256,
+				[]byte(publicKeyPEM(t, &rsaKey.PublicKey)), "", defaultClaims),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "valid issuer and audience",
+			config: dynamic.JWT{
+				Keys:     []string{publicKeyPEM(t, &rsaKey.PublicKey)},
+				Issuer:   "issuer",
+				Audience: "audience",
+			},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc: "wrong issuer",
+			config: dynamic.JWT{
+				Keys:   []string{publicKeyPEM(t, &rsaKey.PublicKey)},
+				Issuer: "other",
+			},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "wrong audience",
+			config: dynamic.JWT{
+				Keys:     []string{publicKeyPEM(t, &rsaKey.PublicKey)},
+				Audience: "other",
+			},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:   "expired token",
+			config: dynamic.JWT{Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}},
+			authorization: "Bearer " + signToken(t, jose.RS256, rsaKey, "", map[string]interface{}{
+				"exp": now.Add(-time.Hour).Unix(),
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:   "token without expiration",
+			config: dynamic.JWT{Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}},
+			authorization: "Bearer " + signToken(t, jose.RS256, rsaKey, "", map[string]interface{}{
+				"sub": "user",
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:   "token not valid yet",
+			config: dynamic.JWT{Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)}},
+			authorization: "Bearer " + signToken(t, jose.RS256, rsaKey, "", map[string]interface{}{
+				"nbf": now.Add(time.Hour).Unix(),
+				"exp": now.Add(2 * time.Hour).Unix(),
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "required claims",
+			config: dynamic.JWT{
+				Keys:           []string{publicKeyPEM(t, &rsaKey.PublicKey)},
+				RequiredClaims: []string{"sub", "exp"},
+			},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc: "missing required claim",
+			config: dynamic.JWT{
+				Keys:           []string{publicKeyPEM(t, &rsaKey.PublicKey)},
+				RequiredClaims: []string{"sub", "email"},
+			},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "forwarded claims",
+			config: dynamic.JWT{
+				Keys: []string{publicKeyPEM(t, &rsaKey.PublicKey)},
+				ForwardClaims: map[string]string{
+					"X-User":    "sub",
+					"X-Groups":  "groups",
+					"X-Admin":   "admin",
+					"X-Level":   "level",
+					"X-Address": "address",
+					"X-Missing": "missing",
+				},
+			},
+			authorization: "Bearer " + signToken(t, jose.RS256, rsaKey, "", map[string]interface{}{
+				"sub":     "user",
+				"groups":  []string{"dev", "ops"},
+				"admin":   true,
+				"level":   42,
+				"address": map[string]string{"country": "FR"},
+				"exp":     now.Add(time.Hour).Unix(),
+			}),
+			expectedStatus: http.StatusOK,
+			expectedHeader: http.Header{
+				"X-User":    {"user"},
+				"X-Groups":  {"dev,ops"},
+				"X-Admin":   {"true"},
+				"X-Level":   {"42"},
+				"X-Address": {`{"country":"FR"}`},
+			},
+		},
+		{
+			desc: "remove header",
+			config: dynamic.JWT{
+				Keys:         []string{publicKeyPEM(t, &rsaKey.PublicKey)},
+				RemoveHeader: true,
+			},
+			authorization:  "Bearer " + signToken(t, jose.RS256, rsaKey, "", defaultClaims),
+			expectedStatus: http.StatusOK,
+			expectedHeader: http.Header{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Empty(t, req.Header.Get("X-Missing"))
+
+				if test.expectedHeader != nil {
+					for name, values := range test.expectedHeader {
+						assert.Equal(t, values, req.Header[name])
+					}
+					if test.config.RemoveHeader {
+						assert.Empty(t, req.Header.Get(authorizationHeader))
+					}
+				}
+
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := NewJWT(context.Background(), next, test.config, "jwt")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			if test.authorization != "" {
+				req.Header.Set(authorizationHeader, test.authorization)
+			}
+			if test.config.ForwardClaims != nil {
+				// A header set by the client for a forwarded claim is always removed.
+				req.Header.Set("X-Missing", "spoofed")
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedStatus == http.StatusUnauthorized {
+				assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestJWTKeyFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	file, err := ioutil.TempFile("", "jwt")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(publicKeyPEM(t, &key.PublicKey))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewJWT(context.Background(), next, dynamic.JWT{Keys: []string{file.Name()}}, "jwt")
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Set(authorizationHeader, "Bearer "+signToken(t, jose.RS256, key, "", map[string]interface{}{
+		"sub": "user",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestJWTInvalidConfig(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := NewJWT(context.Background(), next, dynamic.JWT{}, "jwt")
+	assert.Error(t, err)
+
+	_, err = NewJWT(context.Background(), next, dynamic.JWT{Keys: []string{"-----BEGIN PUBLIC KEY-----\nfoo\n-----END PUBLIC KEY-----"}}, "jwt")
+	assert.Error(t, err)
+
+	_, err = NewJWT(context.Background(), next, dynamic.JWT{Keys: []string{"/does/not/exist.pem"}}, "jwt")
+	assert.Error(t, err)
+}
+
+func TestJWTWithJWKS(t *testing.T) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var rotated int32
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+
+		set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key1.PublicKey, KeyID: "key1", Algorithm: string(jose.RS256), Use: "sig"},
+		}}
+		if atomic.LoadInt32(&rotated) == 1 {
+			set.Keys = append(set.Keys, jose.JSONWebKey{Key: &key2.PublicKey, KeyID: "key2", Algorithm: string(jose.ES256), Use: "sig"})
+		}
+
+		require.NoError(t, json.NewEncoder(rw).Encode(set))
+	}))
+	defer server.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewJWT(context.Background(), next, dynamic.JWT{JWKSURL: server.URL}, "jwt")
+	require.NoError(t, err)
+
+	keys := handler.(*jwtAuth).verifier.jwks
+
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+
+	serve := func(token string) int {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+		req.Header.Set(authorizationHeader, "Bearer "+token)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	claims := map[string]interface{}{"sub": "user", "exp": now.Add(time.Hour).Unix()}
+
+	assert.Equal(t, http.StatusOK, serve(signToken(t, jose.RS256, key1, "key1", claims)))
+	assert.Equal(t, http.StatusOK, serve(signToken(t, jose.RS256, key1, "key1", claims)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// The key is not in the set, which was fetched too recently to be fetched again.
+	atomic.StoreInt32(&rotated, 1)
+	assert.Equal(t, http.StatusUnauthorized, serve(signToken(t, jose.ES256, key2, "key2", claims)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// An unknown key triggers a new fetch.
+	now = now.Add(jwksMinRefreshInterval)
+	assert.Equal(t, http.StatusOK, serve(signToken(t, jose.ES256, key2, "key2", claims)))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// The key algorithm must match the token one.
+	assert.Equal(t, http.StatusUnauthorized, serve(signToken(t, jose.RS512, key1, "key1", claims)))
+
+	// The set is fetched again in the background once too old, while the cached keys are used.
+	now = now.Add(defaultJWKSRefreshInterval)
+	assert.Equal(t, http.StatusOK, serve(signToken(t, jose.RS256, key1, "key1", claims)))
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&fetches) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	// The previous keys are kept when the set can't be fetched.
+	server.Close()
+	now = now.Add(defaultJWKSRefreshInterval)
+	assert.Equal(t, http.StatusOK, serve(signToken(t, jose.RS256, key1, "key1", claims)))
+}
+
+func TestJWKS_RefreshInBackground(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			<-release
+		}
+
+		set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "key1", Algorithm: string(jose.RS256), Use: "sig"},
+		}}
+		require.NoError(t, json.NewEncoder(rw).Encode(set))
+	}))
+	defer server.Close()
+
+	keys := newJWKS(server.URL, 0)
+
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+
+	// The first fetch is not canceled with the context of the request which triggered it.
+	ctx, cancel := context.WithCancel(context.Background())
+	found, err := keys.getKeys(ctx, "key1")
+	cancel()
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// The set is too old: the cached keys are returned without waiting for the fetch, which is only done once.
+	now = now.Add(defaultJWKSRefreshInterval)
+	for i := 0; i < 3; i++ {
+		found, err = keys.getKeys(context.Background(), "key1")
+		require.NoError(t, err)
+		assert.Len(t, found, 1)
+	}
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		keys.mu.Lock()
+		defer keys.mu.Unlock()
+		return keys.refreshing == nil && keys.fetchedAt.Equal(now)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// A request waiting for an unknown key stops waiting when its context is canceled.
+	now = now.Add(jwksMinRefreshInterval)
+	release = make(chan struct{})
+	defer close(release)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = keys.getKeys(ctx, "key2")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func Test_isAllowedAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		key      jose.JSONWebKey
+		alg      jose.SignatureAlgorithm
+		expected bool
+	}{
+		{
+			desc:     "key algorithm",
+			key:      jose.JSONWebKey{Key: &rsaKey.PublicKey, Algorithm: string(jose.RS256)},
+			alg:      jose.RS256,
+			expected: true,
+		},
+		{
+			desc: "other than the key algorithm",
+			key:  jose.JSONWebKey{Key: &rsaKey.PublicKey, Algorithm: string(jose.RS256)},
+			alg:  jose.PS256,
+		},
+		{
+			desc:     "RSA key",
+			key:      jose.JSONWebKey{Key: &rsaKey.PublicKey},
+			alg:      jose.PS384,
+			expected: true,
+		},
+		{
+			desc: "HMAC with an RSA key",
+			key:  jose.JSONWebKey{Key: &rsaKey.PublicKey},
+			alg:  jose.HS256,
+		},
+		{
+			desc:     "EC key",
+			key:      jose.JSONWebKey{Key: &ecKey.PublicKey},
+			alg:      jose.ES384,
+			expected: true,
+		},
+		{
+			desc: "EC key with another curve",
+			key:  jose.JSONWebKey{Key: &ecKey.PublicKey},
+			alg:  jose.ES256,
+		},
+		{
+			desc:     "Ed25519 key",
+			key:      jose.JSONWebKey{Key: edKey},
+			alg:      jose.EdDSA,
+			expected: true,
+		},
+		{
+			desc: "none",
+			key:  jose.JSONWebKey{Key: edKey},
+			alg:  "none",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, isAllowedAlgorithm(test.key, string(test.alg)))
+		})
+	}
+}
+
+func publicKeyPEM(t *testing.T, key interface{}) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func signToken(t *testing.T, alg jose.SignatureAlgorithm, key interface{}, kid string, claims map[string]interface{}) string {
+	t.Helper()
+
+	opts := (&jose.SignerOptions{}).WithType("JWT")
+	if kid != "" {
+		opts = opts.WithHeader("kid", kid)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, opts)
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
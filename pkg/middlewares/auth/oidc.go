@@ -0,0 +1,492 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	oidcTypeName = "OIDC"
+
+	defaultOIDCRedirectPath      = "/oauth2/callback"
+	defaultOIDCSessionCookieName = "_traefik_oidc"
+
+	// oidcStateLifetime is how long the users have to authenticate with the provider.
+	oidcStateLifetime = 10 * time.Minute
+
+	// defaultOIDCSessionLifetime is the lifetime of the sessions when the provider gives no expiration for the tokens.
+	defaultOIDCSessionLifetime = time.Hour
+)
+
+type oidcAuth struct {
+	next                  http.Handler
+	name                  string
+	provider              *oidcProvider
+	codec                 *cookieCodec
+	scopes                []string
+	redirectPath          string
+	logoutPath            string
+	postLogoutRedirectURL string
+	sessionCookie         string
+	stateCookie           string
+	forwardClaims         map[string]string
+	now                   func() time.Time
+}
+
+// NewOIDC creates an OpenID Connect authentication middleware.
+func NewOIDC(ctx context.Context, next http.Handler, config dynamic.OIDC, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, oidcTypeName)).Debug("Creating middleware")
+
+	if config.Issuer == "" || config.ClientID == "" {
+		return nil, errors.New("the issuer and the client ID must be defined")
+	}
+
+	codec, err := newCookieCodec(config.SessionSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []string{"openid"}
+	for _, scope := range config.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	redirectPath := config.RedirectPath
+	if redirectPath == "" {
+		redirectPath = defaultOIDCRedirectPath
+	}
+
+	sessionCookie := config.SessionCookieName
+	if sessionCookie == "" {
+		sessionCookie = defaultOIDCSessionCookieName
+	}
+
+	forwardClaims := make(map[string]string, len(config.ForwardClaims))
+	for header, claim := range config.ForwardClaims {
+		forwardClaims[http.CanonicalHeaderKey(header)] = claim
+	}
+
+	return &oidcAuth{
+		next:                  next,
+		name:                  name,
+		provider:              newOIDCProvider(config.Issuer, config.ClientID, config.ClientSecret),
+		codec:                 codec,
+		scopes:                scopes,
+		redirectPath:          redirectPath,
+		logoutPath:            config.LogoutPath,
+		postLogoutRedirectURL: config.PostLogoutRedirectURL,
+		sessionCookie:         sessionCookie,
+		stateCookie:           sessionCookie + "_state",
+		forwardClaims:         forwardClaims,
+		now:                   time.Now,
+	}, nil
+}
+
+func (o *oidcAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return o.name, tracing.SpanKindNoneEnum
+}
+
+func (o *oidcAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	// The claim headers sent by the client must not reach the service.
+	for header := range o.forwardClaims {
+		req.Header.Del(header)
+	}
+
+	switch {
+	case req.URL.Path == o.redirectPath:
+		o.handleCallback(rw, req)
+		return
+	case o.logoutPath != "" && req.URL.Path == o.logoutPath:
+		o.handleLogout(rw, req)
+		return
+	}
+
+	session, err := o.loadSession(rw, req)
+	if err != nil {
+		logger.Debugf("Authentication required: %v", err)
+		o.authenticate(rw, req)
+		return
+	}
+
+	logger.Debug("Authentication succeeded")
+
+	if session.Subject != "" {
+		req.URL.User = url.User(session.Subject)
+
+		if logData := accesslog.GetLogData(req); logData != nil {
+			logData.Core[accesslog.ClientUsername] = session.Subject
+		}
+	}
+
+	for header, value := range session.Headers {
+		if _, ok := o.forwardClaims[header]; ok {
+			req.Header.Set(header, value)
+		}
+	}
+
+	removeCookies(req, o.sessionCookie, o.stateCookie)
+
+	o.next.ServeHTTP(rw, req)
+}
+
+// loadSession returns the session of the request, refreshing it if it has expired.
+func (o *oidcAuth) loadSession(rw http.ResponseWriter, req *http.Request) (*oidcSession, error) {
+	cookie, err := req.Cookie(o.sessionCookie)
+	if err != nil {
+		return nil, errors.New("no session")
+	}
+
+	var session oidcSession
+	if err := o.codec.decode(o.sessionCookie, cookie.Value, &session); err != nil {
+		return nil, errors.New("invalid session")
+	}
+
+	if o.now().Unix() < session.Expiry {
+		return &session, nil
+	}
+
+	if session.RefreshToken == "" {
+		return nil, errors.New("session expired")
+	}
+
+	refreshed, err := o.refresh(req.Context(), &session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.setCookie(rw, req, o.sessionCookie, refreshed, 0); err != nil {
+		return nil, err
+	}
+
+	return refreshed, nil
+}
+
+// refresh renews the session with its refresh token.
+func (o *oidcAuth) refresh(ctx context.Context, session *oidcSession) (*oidcSession, error) {
+	metadata, verifier, err := o.provider.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := o.provider.requestToken(ctx, metadata.TokenEndpoint, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {session.RefreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshed := &oidcSession{
+		Subject:      session.Subject,
+		Headers:      session.Headers,
+		RefreshToken: session.RefreshToken,
+	}
+	if token.RefreshToken != "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+
+	var claims map[string]interface{}
+	if token.IDToken != "" {
+		claims, err = verifier.verify(ctx, token.IDToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if sub, _ := claims["sub"].(string); sub != session.Subject {
+			return nil, errors.New("the refreshed ID token is for another user")
+		}
+		refreshed.Headers = o.claimHeaders(claims)
+	}
+
+	refreshed.Expiry = o.sessionExpiry(token, claims)
+
+	return refreshed, nil
+}
+
+// authenticate redirects the user to the provider to authenticate.
+func (o *oidcAuth) authenticate(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	// The requests which can't be replayed after a redirection are rejected.
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	metadata, _, err := o.provider.discover(req.Context())
+	if err != nil {
+		logger.Error(err)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	state, err := newOIDCState(req.URL.RequestURI(), o.now().Add(oidcStateLifetime))
+	if err != nil {
+		logger.Errorf("Unable to create the authentication state: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := o.setCookie(rw, req, o.stateCookie, state, oidcStateLifetime); err != nil {
+		logger.Errorf("Unable to set the state cookie: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		logger.Errorf("Invalid authorization endpoint: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", o.provider.clientID)
+	query.Set("redirect_uri", o.redirectURL(req))
+	query.Set("scope", strings.Join(o.scopes, " "))
+	query.Set("state", state.State)
+	query.Set("nonce", state.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	logger.Debug("Redirecting to the provider for authentication")
+	http.Redirect(rw, req, authURL.String(), http.StatusFound)
+}
+
+// handleCallback handles the redirection of the user by the provider,
+// exchanging the authorization code for the tokens, and creating the session.
+func (o *oidcAuth) handleCallback(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	fail := func(status int, msg string, args ...interface{}) {
+		logger.Debugf(msg, args...)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+		http.Error(rw, http.StatusText(status), status)
+	}
+
+	cookie, err := req.Cookie(o.stateCookie)
+	if err != nil {
+		fail(http.StatusBadRequest, "Authentication failed: no state cookie")
+		return
+	}
+
+	var state oidcState
+	if err := o.codec.decode(o.stateCookie, cookie.Value, &state); err != nil || o.now().Unix() >= state.Expiry {
+		fail(http.StatusBadRequest, "Authentication failed: invalid state cookie")
+		return
+	}
+
+	query := req.URL.Query()
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state.State)) != 1 {
+		fail(http.StatusBadRequest, "Authentication failed: state mismatch")
+		return
+	}
+
+	if errCode := query.Get("error"); errCode != "" {
+		fail(http.StatusUnauthorized, "Authentication failed: %s %s", errCode, query.Get("error_description"))
+		return
+	}
+
+	ctx := req.Context()
+
+	metadata, verifier, err := o.provider.discover(ctx)
+	if err != nil {
+		fail(http.StatusInternalServerError, "Authentication failed: %v", err)
+		return
+	}
+
+	token, err := o.provider.requestToken(ctx, metadata.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {query.Get("code")},
+		"redirect_uri":  {o.redirectURL(req)},
+		"code_verifier": {state.CodeVerifier},
+	})
+	if err != nil {
+		fail(http.StatusUnauthorized, "Authentication failed: %v", err)
+		return
+	}
+
+	if token.IDToken == "" {
+		fail(http.StatusUnauthorized, "Authentication failed: no ID token")
+		return
+	}
+
+	claims, err := verifier.verify(ctx, token.IDToken)
+	if err != nil {
+		fail(http.StatusUnauthorized, "Authentication failed: invalid ID token: %v", err)
+		return
+	}
+
+	if nonce, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(nonce), []byte(state.Nonce)) != 1 {
+		fail(http.StatusUnauthorized, "Authentication failed: nonce mismatch")
+		return
+	}
+
+	sub, _ := claims["sub"].(string)
+	session := &oidcSession{
+		Subject:      sub,
+		Headers:      o.claimHeaders(claims),
+		Expiry:       o.sessionExpiry(token, claims),
+		RefreshToken: token.RefreshToken,
+	}
+
+	if err := o.setCookie(rw, req, o.sessionCookie, session, 0); err != nil {
+		logger.Errorf("Unable to set the session cookie: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	o.deleteCookie(rw, req, o.stateCookie)
+
+	logger.Debug("Authentication succeeded")
+	http.Redirect(rw, req, localRedirectURI(state.RedirectURI), http.StatusFound)
+}
+
+// handleLogout ends the session of the user, at the provider as well if it supports it.
+func (o *oidcAuth) handleLogout(rw http.ResponseWriter, req *http.Request) {
+	o.deleteCookie(rw, req, o.sessionCookie)
+
+	redirectURL := o.postLogoutRedirectURL
+	if redirectURL == "" {
+		redirectURL = "/"
+	}
+
+	metadata, _, err := o.provider.discover(req.Context())
+	if err == nil && metadata.EndSessionEndpoint != "" {
+		if endSessionURL, err := url.Parse(metadata.EndSessionEndpoint); err == nil {
+			query := endSessionURL.Query()
+			query.Set("client_id", o.provider.clientID)
+			if o.postLogoutRedirectURL != "" {
+				query.Set("post_logout_redirect_uri", o.postLogoutRedirectURL)
+			}
+			endSessionURL.RawQuery = query.Encode()
+
+			redirectURL = endSessionURL.String()
+		}
+	}
+
+	http.Redirect(rw, req, redirectURL, http.StatusFound)
+}
+
+// claimHeaders returns the values of the forwarded claims, by header name.
+func (o *oidcAuth) claimHeaders(claims map[string]interface{}) map[string]string {
+	headers := make(map[string]string)
+	for header, claim := range o.forwardClaims {
+		if value, ok := claims[claim]; ok {
+			headers[header] = claimValue(value)
+		}
+	}
+	return headers
+}
+
+// sessionExpiry returns the expiration time of a session created from the token response.
+func (o *oidcAuth) sessionExpiry(token *tokenResponse, claims map[string]interface{}) int64 {
+	now := o.now()
+
+	if token.ExpiresIn > 0 {
+		return now.Add(time.Duration(token.ExpiresIn) * time.Second).Unix()
+	}
+
+	if exp, ok := claims["exp"].(float64); ok {
+		return int64(exp)
+	}
+
+	return now.Add(defaultOIDCSessionLifetime).Unix()
+}
+
+func (o *oidcAuth) setCookie(rw http.ResponseWriter, req *http.Request, name string, value interface{}, maxAge time.Duration) error {
+	encoded, err := o.codec.encode(name, value)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     name,
+		Value:    encoded,
+		Path:     "/",
+		MaxAge:   int(maxAge / time.Second),
+		Secure:   scheme(req) == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+func (o *oidcAuth) deleteCookie(rw http.ResponseWriter, req *http.Request, name string) {
+	http.SetCookie(rw, &http.Cookie{
+		Name:     name,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   scheme(req) == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// redirectURL returns the URL the provider redirects the user to once authenticated.
+func (o *oidcAuth) redirectURL(req *http.Request) string {
+	return scheme(req) + "://" + req.Host + o.redirectPath
+}
+
+func scheme(req *http.Request) string {
+	if proto := req.Header.Get("X-Forwarded-Proto"); proto != "" {
+		return proto
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// newOIDCState creates the state of an authentication, with random state, nonce and PKCE code verifier values.
+func newOIDCState(redirectURI string, expiry time.Time) (*oidcState, error) {
+	values := make([]string, 3)
+	for i := range values {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		values[i] = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	return &oidcState{
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+		RedirectURI:  redirectURI,
+		Expiry:       expiry.Unix(),
+	}, nil
+}
+
+// localRedirectURI returns the request URI to redirect to, if it is a local one, or else the root path.
+func localRedirectURI(uri string) string {
+	if !strings.HasPrefix(uri, "/") || strings.HasPrefix(uri, "//") || strings.HasPrefix(uri, "/\\") {
+		return "/"
+	}
+	return uri
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	oidcRequestTimeout = 10 * time.Second
+
+	// oidcDiscoveryRetryInterval is the minimum interval between two attempts to fetch the provider configuration.
+	oidcDiscoveryRetryInterval = 10 * time.Second
+
+	oidcMaxResponseSize = 1 << 20
+)
+
+// providerMetadata is the OpenID provider configuration (OpenID Connect Discovery 1.0, section 3).
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint,omitempty"`
+}
+
+// tokenResponse is the response of the token endpoint (RFC 6749, section 5).
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// oidcProvider is an OpenID provider, whose configuration is discovered on first use.
+type oidcProvider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	client       *http.Client
+	now          func() time.Time
+
+	mu          sync.Mutex
+	metadata    *providerMetadata
+	verifier    *tokenVerifier
+	attemptedAt time.Time
+}
+
+func newOIDCProvider(issuer, clientID, clientSecret string) *oidcProvider {
+	return &oidcProvider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       &http.Client{Timeout: oidcRequestTimeout},
+		now:          time.Now,
+	}
+}
+
+// discover returns the provider configuration, and the verifier of the ID tokens it issues.
+func (p *oidcProvider) discover(ctx context.Context) (*providerMetadata, *tokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, p.verifier, nil
+	}
+
+	now := p.now()
+	if !p.attemptedAt.IsZero() && now.Sub(p.attemptedAt) < oidcDiscoveryRetryInterval {
+		return nil, nil, errors.New("the provider configuration is not available")
+	}
+	p.attemptedAt = now
+
+	metadata, err := p.fetchMetadata(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to discover the provider configuration: %w", err)
+	}
+
+	verifier, err := newTokenVerifier(nil, metadata.JWKSURI, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	verifier.issuer = metadata.Issuer
+	verifier.audience = p.clientID
+
+	p.metadata = metadata
+	p.verifier = verifier
+
+	return metadata, verifier, nil
+}
+
+func (p *oidcProvider) fetchMetadata(ctx context.Context) (*providerMetadata, error) {
+	req, err := http.NewRequest(http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata providerMetadata
+	if err := p.do(req.WithContext(ctx), &metadata); err != nil {
+		return nil, err
+	}
+
+	// The issuer must be the one the configuration is retrieved from (OpenID Connect Discovery 1.0, section 4.3).
+	if strings.TrimSuffix(metadata.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("the issuer %q does not match the configured one", metadata.Issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("missing endpoints in the provider configuration")
+	}
+
+	return &metadata, nil
+}
+
+// requestToken sends a token request to the token endpoint, authenticated with the client credentials.
+func (p *oidcProvider) requestToken(ctx context.Context, endpoint string, form url.Values) (*tokenResponse, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	var token tokenResponse
+	err = p.do(req.WithContext(ctx), &token)
+	if token.Error != "" {
+		return nil, fmt.Errorf("token request failed: %s %s", token.Error, token.ErrorDescription)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+
+	return &token, nil
+}
+
+// do sends the request, and decodes the JSON response into value.
+// The body of an error response is decoded as well, if possible.
+func (p *oidcProvider) do(req *http.Request, value interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, oidcMaxResponseSize))
+	if err != nil {
+		return err
+	}
+
+	if jsonErr := json.Unmarshal(body, value); resp.StatusCode == http.StatusOK && jsonErr != nil {
+		return jsonErr
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// oidcSession is the session of an authenticated user, stored in a cookie.
+type oidcSession struct {
+	Subject string `json:"sub,omitempty"`
+	// Headers are the forwarded claims, by header name.
+	Headers      map[string]string `json:"headers,omitempty"`
+	Expiry       int64             `json:"exp"`
+	RefreshToken string            `json:"rt,omitempty"`
+}
+
+// oidcState is the state of an authentication in progress, stored in a cookie until the user is redirected back.
+type oidcState struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"verifier"`
+	// RedirectURI is the request URI the user is redirected to once authenticated.
+	RedirectURI string `json:"uri"`
+	Expiry      int64  `json:"exp"`
+}
+
+// cookieCodec encrypts and authenticates the values stored in cookies.
+type cookieCodec struct {
+	aead cipher.AEAD
+}
+
+func newCookieCodec(secret string) (*cookieCodec, error) {
+	if len(secret) < 16 {
+		return nil, errors.New("the session secret must be at least 16 characters long")
+	}
+
+	key := sha256.Sum256([]byte(secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cookieCodec{aead: aead}, nil
+}
+
+// encode returns the encrypted value of the cookie with the given name.
+// The name is authenticated along the value, so that the value of a cookie can't be used for another one.
+func (c *cookieCodec) encode(name string, value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, data, []byte(name))), nil
+}
+
+// decode decrypts the value of the cookie with the given name into value.
+func (c *cookieCodec) decode(name, encoded string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	if len(data) < c.aead.NonceSize() {
+		return errors.New("invalid cookie value")
+	}
+
+	nonce := data[:c.aead.NonceSize()]
+	plaintext, err := c.aead.Open(nil, nonce, data[c.aead.NonceSize():], []byte(name))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(plaintext, value)
+}
+
+// removeCookies removes the cookies with the given names from the Cookie header of the request.
+func removeCookies(req *http.Request, names ...string) {
+	cookies := req.Cookies()
+	if len(cookies) == 0 {
+		return
+	}
+
+	var kept []string
+	for _, cookie := range cookies {
+		if !contains(names, cookie.Name) {
+			kept = append(kept, cookie.String())
+		}
+	}
+
+	if len(kept) == len(cookies) {
+		return
+	}
+
+	req.Header.Del("Cookie")
+	if len(kept) > 0 {
+		req.Header.Set("Cookie", strings.Join(kept, "; "))
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
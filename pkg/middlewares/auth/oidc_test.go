@@ -0,0 +1,427 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	testClientID      = "client"
+	testClientSecret  = "secret"
+	testSessionSecret = "0123456789abcdef"
+)
+
+// mockProvider is an in-process OpenID provider.
+type mockProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]url.Values
+	nonce  string
+	tokens int
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &mockProvider{t: t, key: key, codes: make(map[string]url.Values)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(providerMetadata{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize?prompt=login",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/jwks",
+			EndSessionEndpoint:    p.server.URL + "/logout",
+		})
+	})
+	mux.HandleFunc("/jwks", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "key", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", p.serveToken)
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// authorize simulates the authentication of the user, from the authorization request,
+// and returns the code to send back to the client.
+func (p *mockProvider) authorize(authURL string) string {
+	u, err := url.Parse(authURL)
+	require.NoError(p.t, err)
+
+	query := u.Query()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	code := "code" + query.Get("state")
+	p.codes[code] = query
+
+	return code
+}
+
+func (p *mockProvider) serveToken(rw http.ResponseWriter, req *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if user, password, ok := req.BasicAuth(); !ok || user != testClientID || password != testClientSecret {
+		rw.WriteHeader(http.StatusUnauthorized)
+		_, _ = rw.Write([]byte(`{"error":"invalid_client"}`))
+		return
+	}
+
+	claims := map[string]interface{}{
+		"iss":    p.server.URL,
+		"aud":    testClientID,
+		"sub":    "user",
+		"email":  "user@example.com",
+		"groups": []string{"dev", "ops"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}
+
+	switch req.FormValue("grant_type") {
+	case "authorization_code":
+		authReq, ok := p.codes[req.FormValue("code")]
+		if !ok {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		delete(p.codes, req.FormValue("code"))
+
+		challenge := sha256.Sum256([]byte(req.FormValue("code_verifier")))
+		if authReq.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(challenge[:]) ||
+			authReq.Get("redirect_uri") != req.FormValue("redirect_uri") {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		claims["nonce"] = authReq.Get("nonce")
+		if p.nonce != "" {
+			claims["nonce"] = p.nonce
+		}
+
+	case "refresh_token":
+		if req.FormValue("refresh_token") != "refresh" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		claims["email"] = "refreshed@example.com"
+
+	default:
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	p.tokens++
+
+	_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+		"access_token":  "access",
+		"id_token":      signToken(p.t, jose.RS256, p.key, "key", claims),
+		"refresh_token": "refresh",
+		"expires_in":    60,
+	})
+}
+
+func TestOIDC(t *testing.T) {
+	provider := newMockProvider(t)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, err := req.Cookie(defaultOIDCSessionCookieName)
+		assert.Error(t, err, "the session cookie should not be forwarded")
+
+		rw.Header().Set("X-User", req.Header.Get("X-User"))
+		rw.Header().Set("X-Email", req.Header.Get("X-Email"))
+		rw.Header().Set("X-Groups", req.Header.Get("X-Groups"))
+		rw.Header().Set("X-Cookie", req.Header.Get("Cookie"))
+	})
+
+	handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+		Issuer:        provider.server.URL,
+		ClientID:      testClientID,
+		ClientSecret:  testClientSecret,
+		Scopes:        []string{"openid", "email"},
+		LogoutPath:    "/logout",
+		SessionSecret: testSessionSecret,
+		ForwardClaims: map[string]string{
+			"X-User":   "sub",
+			"X-Email":  "email",
+			"X-Groups": "groups",
+		},
+	}, "oidc")
+	require.NoError(t, err)
+
+	now := time.Now()
+	handler.(*oidcAuth).now = func() time.Time { return now }
+
+	serve := func(method, target string, cookies ...*http.Cookie) *http.Response {
+		req := testhelpers.MustNewRequest(method, target, nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		req.Header.Set("X-User", "spoofed")
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Result()
+	}
+
+	// The requests which can't be redirected are rejected.
+	resp := serve(http.MethodPost, "http://app.localhost/dashboard")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// The user is redirected to the provider.
+	resp = serve(http.MethodGet, "http://app.localhost/dashboard?tab=1")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	authURL, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, provider.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+
+	query := authURL.Query()
+	assert.Equal(t, "login", query.Get("prompt"))
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, testClientID, query.Get("client_id"))
+	assert.Equal(t, "http://app.localhost/oauth2/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.NotEmpty(t, query.Get("state"))
+	assert.NotEmpty(t, query.Get("nonce"))
+
+	stateCookie := findCookie(resp, defaultOIDCSessionCookieName+"_state")
+	require.NotNil(t, stateCookie)
+	assert.True(t, stateCookie.HttpOnly)
+
+	// The provider redirects the user back, who is redirected to the requested page.
+	code := provider.authorize(authURL.String())
+	resp = serve(http.MethodGet, "http://app.localhost/oauth2/callback?code="+code+"&state="+query.Get("state"), stateCookie)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/dashboard?tab=1", resp.Header.Get("Location"))
+	assert.Equal(t, -1, findCookie(resp, stateCookie.Name).MaxAge)
+
+	sessionCookie := findCookie(resp, defaultOIDCSessionCookieName)
+	require.NotNil(t, sessionCookie)
+
+	// The session is valid, and the claims are forwarded.
+	resp = serve(http.MethodGet, "http://app.localhost/dashboard", sessionCookie, &http.Cookie{Name: "other", Value: "value"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "user", resp.Header.Get("X-User"))
+	assert.Equal(t, "user@example.com", resp.Header.Get("X-Email"))
+	assert.Equal(t, "dev,ops", resp.Header.Get("X-Groups"))
+	assert.Equal(t, "other=value", resp.Header.Get("X-Cookie"))
+	assert.Equal(t, 1, provider.tokens)
+
+	// The expired session is refreshed.
+	now = now.Add(2 * time.Minute)
+	resp = serve(http.MethodGet, "http://app.localhost/dashboard", sessionCookie)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "refreshed@example.com", resp.Header.Get("X-Email"))
+	assert.Equal(t, 2, provider.tokens)
+
+	refreshedCookie := findCookie(resp, defaultOIDCSessionCookieName)
+	require.NotNil(t, refreshedCookie)
+
+	resp = serve(http.MethodGet, "http://app.localhost/dashboard", refreshedCookie)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, provider.tokens)
+
+	// The logout ends the session at the provider.
+	resp = serve(http.MethodGet, "http://app.localhost/logout", refreshedCookie)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, provider.server.URL+"/logout?client_id="+testClientID, resp.Header.Get("Location"))
+	assert.Equal(t, -1, findCookie(resp, defaultOIDCSessionCookieName).MaxAge)
+}
+
+func TestOIDCCallbackFailures(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		nonce          string
+		code           string
+		state          string
+		noStateCookie  bool
+		expectedStatus int
+	}{
+		{
+			desc:           "no state cookie",
+			noStateCookie:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "state mismatch",
+			state:          "other",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "invalid code",
+			code:           "other",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "nonce mismatch",
+			nonce:          "other",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := newMockProvider(t)
+			provider.nonce = test.nonce
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+				Issuer:        provider.server.URL,
+				ClientID:      testClientID,
+				ClientSecret:  testClientSecret,
+				SessionSecret: testSessionSecret,
+			}, "oidc")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://app.localhost/", nil))
+			resp := recorder.Result()
+			require.Equal(t, http.StatusFound, resp.StatusCode)
+
+			code := provider.authorize(resp.Header.Get("Location"))
+			if test.code != "" {
+				code = test.code
+			}
+
+			authURL, err := url.Parse(resp.Header.Get("Location"))
+			require.NoError(t, err)
+
+			state := authURL.Query().Get("state")
+			if test.state != "" {
+				state = test.state
+			}
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://app.localhost/oauth2/callback?code="+code+"&state="+state, nil)
+			if !test.noStateCookie {
+				req.AddCookie(findCookie(resp, defaultOIDCSessionCookieName+"_state"))
+			}
+
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Nil(t, findCookie(recorder.Result(), defaultOIDCSessionCookieName))
+		})
+	}
+}
+
+func TestOIDCInvalidSession(t *testing.T) {
+	provider := newMockProvider(t)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+		Issuer:        provider.server.URL,
+		ClientID:      testClientID,
+		ClientSecret:  testClientSecret,
+		SessionSecret: testSessionSecret,
+	}, "oidc")
+	require.NoError(t, err)
+
+	otherCodec, err := newCookieCodec("another session secret")
+	require.NoError(t, err)
+
+	session := oidcSession{Subject: "user", Expiry: time.Now().Add(time.Hour).Unix()}
+
+	forged, err := otherCodec.encode(defaultOIDCSessionCookieName, session)
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://app.localhost/", nil)
+	req.AddCookie(&http.Cookie{Name: defaultOIDCSessionCookieName, Value: forged})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusFound, recorder.Code)
+
+	// An expired session without refresh token requires a new authentication.
+	session.Expiry = time.Now().Add(-time.Minute).Unix()
+	expired, err := handler.(*oidcAuth).codec.encode(defaultOIDCSessionCookieName, session)
+	require.NoError(t, err)
+
+	req = testhelpers.MustNewRequest(http.MethodGet, "http://app.localhost/", nil)
+	req.AddCookie(&http.Cookie{Name: defaultOIDCSessionCookieName, Value: expired})
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusFound, recorder.Code)
+}
+
+func TestOIDCInvalidConfig(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := NewOIDC(context.Background(), next, dynamic.OIDC{ClientID: testClientID, SessionSecret: testSessionSecret}, "oidc")
+	assert.Error(t, err)
+
+	_, err = NewOIDC(context.Background(), next, dynamic.OIDC{Issuer: "http://localhost", ClientID: testClientID, SessionSecret: "short"}, "oidc")
+	assert.Error(t, err)
+}
+
+func TestCookieCodec(t *testing.T) {
+	codec, err := newCookieCodec(testSessionSecret)
+	require.NoError(t, err)
+
+	encoded, err := codec.encode("name", oidcSession{Subject: "user"})
+	require.NoError(t, err)
+
+	var session oidcSession
+	require.NoError(t, codec.decode("name", encoded, &session))
+	assert.Equal(t, "user", session.Subject)
+
+	// The value of a cookie can't be used for another one.
+	assert.Error(t, codec.decode("other", encoded, &session))
+	assert.Error(t, codec.decode("name", encoded[:len(encoded)-2], &session))
+	assert.Error(t, codec.decode("name", "", &session))
+}
+
+func TestLocalRedirectURI(t *testing.T) {
+	assert.Equal(t, "/dashboard?tab=1", localRedirectURI("/dashboard?tab=1"))
+	assert.Equal(t, "/", localRedirectURI("//example.com/"))
+	assert.Equal(t, "/", localRedirectURI("/\\example.com/"))
+	assert.Equal(t, "/", localRedirectURI("http://example.com/"))
+}
+
+func findCookie(resp *http.Response, name string) *http.Cookie {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
@@ -0,0 +1,418 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/vulcand/oxy/utils"
+)
+
+const (
+	typeName = "Cache"
+
+	defaultMaxSize = 64 * 1024 * 1024
+
+	// statusHeader is the response header reporting the cache status.
+	statusHeader = "X-Cache"
+)
+
+// Cache statuses.
+const (
+	statusHit    = "HIT"
+	statusStale  = "STALE"
+	statusMiss   = "MISS"
+	statusBypass = "BYPASS"
+)
+
+// cache is a middleware caching the responses in memory, as a shared cache (RFC 7234).
+type cache struct {
+	next                 http.Handler
+	name                 string
+	logger               log.Logger
+	defaultTTL           time.Duration
+	staleWhileRevalidate time.Duration
+	store                *store
+	now                  func() time.Time
+
+	mu       sync.Mutex
+	inFlight map[string]chan struct{}
+}
+
+// New creates a cache middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Cache, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if config.MaxSize < 0 {
+		return nil, fmt.Errorf("invalid maximum size: %d", config.MaxSize)
+	}
+	if config.DefaultTTL < 0 || config.StaleWhileRevalidate < 0 {
+		return nil, fmt.Errorf("invalid durations: defaultTTL %s, staleWhileRevalidate %s",
+			time.Duration(config.DefaultTTL), time.Duration(config.StaleWhileRevalidate))
+	}
+
+	maxSize := config.MaxSize
+	if maxSize == 0 {
+		maxSize = defaultMaxSize
+	}
+
+	return &cache{
+		next:                 next,
+		name:                 name,
+		logger:               logger,
+		defaultTTL:           time.Duration(config.DefaultTTL),
+		staleWhileRevalidate: time.Duration(config.StaleWhileRevalidate),
+		store:                newStore(maxSize),
+		now:                  time.Now,
+		inFlight:             make(map[string]chan struct{}),
+	}, nil
+}
+
+func (c *cache) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *cache) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	key := cacheKey(req)
+
+	if req.Method != http.MethodGet {
+		c.next.ServeHTTP(rw, req)
+
+		// The requests with an unsafe method invalidate the stored responses (RFC 7234 section 4.4).
+		if !isSafe(req.Method) {
+			c.store.delete(key)
+		}
+		return
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	if reqCC.has("no-store") {
+		setStatus(rw, req, statusBypass)
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The client asking for a validated response gets a fresh one from the server.
+	if maxAge, ok := reqCC.duration("max-age"); reqCC.has("no-cache") || ok && maxAge == 0 {
+		c.fetch(rw, req, key, reqCC)
+		return
+	}
+
+	if c.serveStored(rw, req, key) {
+		return
+	}
+
+	// The concurrent requests for the same resource wait for the first one to get the response,
+	// which is served to them if it is stored.
+	wait, done := c.lock(key)
+	if wait == nil {
+		defer done()
+		c.fetch(rw, req, key, reqCC)
+		return
+	}
+
+	select {
+	case <-wait:
+	case <-req.Context().Done():
+		return
+	}
+
+	if c.serveStored(rw, req, key) {
+		return
+	}
+	c.fetch(rw, req, key, reqCC)
+}
+
+// serveStored serves the response stored for the request, if any, and reports whether it did.
+// A stale response is revalidated in the background.
+func (c *cache) serveStored(rw http.ResponseWriter, req *http.Request, key string) bool {
+	now := c.now()
+
+	e := c.store.get(key, req.Header, now)
+	if e == nil {
+		return false
+	}
+
+	status := statusHit
+	if !now.Before(e.freshUntil) {
+		status = statusStale
+		c.revalidate(req, key)
+	}
+
+	// The headers already set by the outer middlewares are kept, as they belong to the current request.
+	header := rw.Header()
+	for name, values := range e.header {
+		if _, ok := header[name]; ok {
+			continue
+		}
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set("Age", strconv.FormatInt(int64((e.age+now.Sub(e.storedAt))/time.Second), 10))
+	setStatus(rw, req, status)
+
+	rw.WriteHeader(e.status)
+	if _, err := rw.Write(e.body); err != nil {
+		log.FromContext(req.Context()).Debugf("Error while writing the cached response: %v", err)
+	}
+
+	return true
+}
+
+// fetch forwards the request to the next handler, and stores its response if possible.
+func (c *cache) fetch(rw http.ResponseWriter, req *http.Request, key string, reqCC cacheControl) {
+	setStatus(rw, req, statusMiss)
+
+	recorder := newRecorder(rw, c.store.maxSize)
+	c.next.ServeHTTP(recorder, req)
+
+	if recorder.overflow {
+		return
+	}
+
+	code, header := recorder.response()
+	c.storeResponse(req, key, reqCC, code, header, recorder.body.Bytes())
+}
+
+// revalidate fetches in the background the response to the request, to replace the stale one stored.
+func (c *cache) revalidate(req *http.Request, key string) {
+	_, done := c.lock(key)
+	if done == nil {
+		// The response is already being fetched.
+		return
+	}
+
+	// The background request must outlive the client request, and not write its access log data.
+	outReq := req.Clone(context.Background())
+
+	go func() {
+		defer done()
+		defer func() {
+			if err := recover(); err != nil {
+				c.logger.Errorf("Panic while revalidating %s: %v", key, err)
+			}
+		}()
+
+		recorder := newRecorder(nil, c.store.maxSize)
+		c.next.ServeHTTP(recorder, outReq)
+
+		if recorder.overflow {
+			return
+		}
+
+		code, header := recorder.response()
+		c.storeResponse(outReq, key, parseCacheControl(outReq.Header), code, header, recorder.body.Bytes())
+	}()
+}
+
+func (c *cache) storeResponse(req *http.Request, key string, reqCC cacheControl, code int, header http.Header, body []byte) {
+	cc := parseCacheControl(header)
+	if !storable(req, reqCC, code, header, cc) {
+		return
+	}
+
+	now := c.now()
+
+	lifetime, ok := freshness(header, cc, now, c.defaultTTL)
+	if !ok {
+		return
+	}
+
+	staleWhileRevalidate := c.staleWhileRevalidate
+	if d, ok := cc.duration("stale-while-revalidate"); ok {
+		staleWhileRevalidate = d
+	}
+	if cc.has("must-revalidate") || cc.has("proxy-revalidate") {
+		staleWhileRevalidate = 0
+	}
+
+	initialAge := age(header)
+	freshUntil := now.Add(lifetime - initialAge)
+	staleUntil := freshUntil.Add(staleWhileRevalidate)
+	if !staleUntil.After(now) {
+		return
+	}
+
+	vary := parseVary(header)
+
+	c.store.set(&entry{
+		key:        key,
+		vary:       vary,
+		varyValues: varyValues(vary, req.Header),
+		status:     code,
+		header:     header,
+		body:       body,
+		storedAt:   now,
+		age:        initialAge,
+		freshUntil: freshUntil,
+		staleUntil: staleUntil,
+	})
+}
+
+// lock marks the response for the given key as being fetched, and returns the function to call once it is done.
+// If it is already being fetched, it returns a channel closed once it is done instead.
+func (c *cache) lock(key string) (<-chan struct{}, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if wait, ok := c.inFlight[key]; ok {
+		return wait, nil
+	}
+
+	wait := make(chan struct{})
+	c.inFlight[key] = wait
+
+	return nil, func() {
+		c.mu.Lock()
+		delete(c.inFlight, key)
+		c.mu.Unlock()
+
+		close(wait)
+	}
+}
+
+// setStatus reports the cache status in the response headers and in the access log.
+func setStatus(rw http.ResponseWriter, req *http.Request, status string) {
+	rw.Header().Set(statusHeader, status)
+
+	if table := accesslog.GetLogData(req); table != nil {
+		table.Core[accesslog.CacheStatus] = status
+	}
+}
+
+// cacheKey returns the key identifying the resource requested.
+func cacheKey(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + req.Host + req.URL.RequestURI()
+}
+
+// isSafe reports whether the given method is safe, as defined by RFC 7231.
+func isSafe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// recorder records the response written to it, up to maxSize bytes of body,
+// and forwards it to the underlying response writer, if any.
+type recorder struct {
+	rw      http.ResponseWriter
+	maxSize int64
+
+	header http.Header
+	// initial are the headers set before calling the next handler, by the outer middlewares.
+	initial     http.Header
+	code        int
+	wroteHeader bool
+	// stored are the headers of the response, as they were when written.
+	stored   http.Header
+	body     bytes.Buffer
+	overflow bool
+}
+
+func newRecorder(rw http.ResponseWriter, maxSize int64) *recorder {
+	header := make(http.Header)
+	if rw != nil {
+		header = rw.Header()
+	}
+
+	initial := make(http.Header)
+	utils.CopyHeaders(initial, header)
+
+	return &recorder{rw: rw, maxSize: maxSize, header: header, initial: initial, code: http.StatusOK}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.wroteHeader = true
+	r.code = code
+	r.stored = r.storedHeader()
+
+	if r.rw != nil {
+		r.rw.WriteHeader(code)
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.overflow {
+		if int64(r.body.Len()+len(b)) > r.maxSize {
+			r.overflow = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	if r.rw == nil {
+		return len(b), nil
+	}
+	return r.rw.Write(b)
+}
+
+// Flush sends any buffered data to the client.
+func (r *recorder) Flush() {
+	if f, ok := r.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// response returns the status code and headers of the recorded response.
+func (r *recorder) response() (int, http.Header) {
+	if !r.wroteHeader {
+		return r.code, r.storedHeader()
+	}
+	return r.code, r.stored
+}
+
+// storedHeader returns a copy of the headers added or changed by the next handler, without the cache status.
+// The headers set by the outer middlewares are specific to the request, and must not be replayed to the other clients.
+func (r *recorder) storedHeader() http.Header {
+	header := make(http.Header)
+	for name, values := range r.header {
+		if equalValues(values, r.initial[name]) {
+			continue
+		}
+		header[name] = append([]string(nil), values...)
+	}
+	header.Del(statusHeader)
+	return header
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,438 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	ptypes "github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           dynamic.Cache
+		method           string
+		requestHeaders   map[string]string
+		responseHeaders  map[string]string
+		responseCode     int
+		expectedStatuses []string
+		expectedCalls    int32
+	}{
+		{
+			desc:             "max-age",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			expectedStatuses: []string{statusMiss, statusHit, statusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "s-maxage",
+			responseHeaders:  map[string]string{"Cache-Control": "s-maxage=60, max-age=0"},
+			expectedStatuses: []string{statusMiss, statusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "expires",
+			responseHeaders:  map[string]string{"Expires": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)},
+			expectedStatuses: []string{statusMiss, statusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "expired",
+			responseHeaders:  map[string]string{"Expires": time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)},
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "no freshness information",
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "no freshness information with a default TTL",
+			config:           dynamic.Cache{DefaultTTL: ptypes.Duration(time.Minute)},
+			expectedStatuses: []string{statusMiss, statusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "no-store response",
+			responseHeaders:  map[string]string{"Cache-Control": "no-store, max-age=60"},
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "private response",
+			responseHeaders:  map[string]string{"Cache-Control": "private, max-age=60"},
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "response setting a cookie",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60", "Set-Cookie": "foo=bar"},
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "vary all",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60", "Vary": "*"},
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "not cacheable status code",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			responseCode:     http.StatusInternalServerError,
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "cacheable error status code",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			responseCode:     http.StatusNotFound,
+			expectedStatuses: []string{statusMiss, statusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "no-store request",
+			requestHeaders:   map[string]string{"Cache-Control": "no-store"},
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			expectedStatuses: []string{statusBypass, statusBypass},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "no-cache request",
+			requestHeaders:   map[string]string{"Cache-Control": "no-cache"},
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "authorized request",
+			requestHeaders:   map[string]string{"Authorization": "Basic Zm9vOmJhcg=="},
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "authorized request with public response",
+			requestHeaders:   map[string]string{"Authorization": "Basic Zm9vOmJhcg=="},
+			responseHeaders:  map[string]string{"Cache-Control": "public, max-age=60"},
+			expectedStatuses: []string{statusMiss, statusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "response larger than the cache",
+			config:           dynamic.Cache{MaxSize: 8},
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "HEAD request",
+			method:           http.MethodHead,
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			expectedStatuses: []string{"", ""},
+			expectedCalls:    2,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				for name, value := range test.responseHeaders {
+					rw.Header().Set(name, value)
+				}
+				if test.responseCode != 0 {
+					rw.WriteHeader(test.responseCode)
+				}
+				_, _ = rw.Write([]byte("response body"))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "cache")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			for i, expectedStatus := range test.expectedStatuses {
+				req := httptest.NewRequest(method, "http://localhost/foo?bar=baz", nil)
+				for name, value := range test.requestHeaders {
+					req.Header.Set(name, value)
+				}
+
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+
+				assert.Equal(t, expectedStatus, recorder.Header().Get(statusHeader), "request %d", i)
+				if method == http.MethodGet {
+					assert.Equal(t, "response body", recorder.Body.String(), "request %d", i)
+				}
+			}
+
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestCacheExpiration(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		call := atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=10")
+		rw.Header().Set("Age", "2")
+		_, _ = rw.Write([]byte(strconv.Itoa(int(call))))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+	handler.(*cache).now = clock.Now
+
+	assertResponse(t, handler, statusMiss, "1")
+
+	clock.Add(5 * time.Second)
+	recorder := assertResponse(t, handler, statusHit, "1")
+	assert.Equal(t, "7", recorder.Header().Get("Age"))
+
+	// The response was already 2 seconds old when received.
+	clock.Add(3 * time.Second)
+	assertResponse(t, handler, statusMiss, "2")
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		call := atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=10, stale-while-revalidate=20")
+		_, _ = rw.Write([]byte(strconv.Itoa(int(call))))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+	handler.(*cache).now = clock.Now
+
+	assertResponse(t, handler, statusMiss, "1")
+
+	// The stale response is served, and revalidated in the background.
+	clock.Add(15 * time.Second)
+	assertResponse(t, handler, statusStale, "1")
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return handler.(*cache).store.get("http://localhost/foo", http.Header{}, clock.Now()).freshUntil.After(clock.Now())
+	}, time.Second, 10*time.Millisecond)
+
+	assertResponse(t, handler, statusHit, "2")
+
+	// The response can't be served after the stale-while-revalidate window.
+	clock.Add(31 * time.Second)
+	assertResponse(t, handler, statusMiss, "3")
+}
+
+func TestCacheVary(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Vary", "Accept-Language")
+		_, _ = rw.Write([]byte(req.Header.Get("Accept-Language")))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	serve := func(language string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+		req.Header.Set("Accept-Language", language)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		assert.Equal(t, language, recorder.Body.String())
+
+		return recorder
+	}
+
+	assert.Equal(t, statusMiss, serve("en").Header().Get(statusHeader))
+	assert.Equal(t, statusMiss, serve("fr").Header().Get(statusHeader))
+	assert.Equal(t, statusHit, serve("en").Header().Get(statusHeader))
+	assert.Equal(t, statusHit, serve("fr").Header().Get(statusHeader))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCacheOuterHeaders(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("X-Backend", "foo")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	cacheHandler, err := New(context.Background(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	// The outer middleware sets a header specific to each request.
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Request-Id", req.Header.Get("X-Request-Id"))
+		cacheHandler.ServeHTTP(rw, req)
+	})
+
+	serve := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+		req.Header.Set("X-Request-Id", id)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	first := serve("1")
+	assert.Equal(t, statusMiss, first.Header().Get(statusHeader))
+	assert.Equal(t, "1", first.Header().Get("X-Request-Id"))
+
+	second := serve("2")
+	assert.Equal(t, statusHit, second.Header().Get(statusHeader))
+	assert.Equal(t, "2", second.Header().Get("X-Request-Id"))
+	assert.Equal(t, "foo", second.Header().Get("X-Backend"))
+	assert.Equal(t, "foo", second.Body.String())
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		call := atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte(strconv.Itoa(int(call))))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	assertResponse(t, handler, statusMiss, "1")
+	assertResponse(t, handler, statusHit, "1")
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://localhost/foo", nil))
+
+	assertResponse(t, handler, statusMiss, "3")
+}
+
+func TestCacheCoalescing(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("response body"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	statuses := make(chan string, 5)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+			statuses <- recorder.Header().Get(statusHeader)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	wg.Wait()
+	close(statuses)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	var misses, hits int
+	for status := range statuses {
+		switch status {
+		case statusMiss:
+			misses++
+		case statusHit:
+			hits++
+		}
+	}
+	assert.Equal(t, 1, misses)
+	assert.Equal(t, 4, hits)
+}
+
+func TestCacheAccessLog(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	for _, expected := range []string{statusMiss, statusHit} {
+		logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+		req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, expected, logData.Core[accesslog.CacheStatus])
+	}
+}
+
+func assertResponse(t *testing.T, handler http.Handler, expectedStatus, expectedBody string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	assert.Equal(t, expectedStatus, recorder.Header().Get(statusHeader))
+	assert.Equal(t, expectedBody, recorder.Body.String())
+
+	return recorder
+}
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
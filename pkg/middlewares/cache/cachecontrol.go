@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControl holds the directives of a Cache-Control header, with their values (empty if they have none).
+type cacheControl map[string]string
+
+func parseCacheControl(header http.Header) cacheControl {
+	cc := make(cacheControl)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.TrimSpace(directive)
+			if directive == "" {
+				continue
+			}
+
+			var arg string
+			if i := strings.Index(directive, "="); i >= 0 {
+				directive, arg = strings.TrimSpace(directive[:i]), strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+			}
+			cc[strings.ToLower(directive)] = arg
+		}
+	}
+	return cc
+}
+
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// duration returns the value of the given directive, as a number of seconds,
+// and whether the directive is set with a valid value.
+func (cc cacheControl) duration(directive string) (time.Duration, bool) {
+	arg, ok := cc[directive]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+// cacheableStatus reports whether responses with the given status code are cacheable by default, as defined by RFC 7231.
+// Partial responses are left out, as they are not reassembled.
+func cacheableStatus(code int) bool {
+	switch code {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusNoContent,
+		http.StatusMultipleChoices, http.StatusMovedPermanently, http.StatusPermanentRedirect,
+		http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusGone, http.StatusRequestURITooLong, http.StatusNotImplemented:
+		return true
+	default:
+		return false
+	}
+}
+
+// storable reports whether a shared cache can store the response to the request, as defined by RFC 7234.
+func storable(req *http.Request, reqCC cacheControl, code int, header http.Header, cc cacheControl) bool {
+	if reqCC.has("no-store") || cc.has("no-store") || cc.has("no-cache") || cc.has("private") {
+		return false
+	}
+
+	if !cacheableStatus(code) {
+		return false
+	}
+
+	// The responses setting cookies are specific to a client.
+	if header.Get("Set-Cookie") != "" {
+		return false
+	}
+
+	for _, name := range parseVary(header) {
+		if name == "*" {
+			return false
+		}
+	}
+
+	if req.Header.Get("Authorization") != "" {
+		return cc.has("public") || cc.has("s-maxage") || cc.has("must-revalidate")
+	}
+
+	return true
+}
+
+// freshness returns how long the response stays fresh after it has been received at now,
+// and whether it has freshness information, explicit or from the defaultTTL.
+func freshness(header http.Header, cc cacheControl, now time.Time, defaultTTL time.Duration) (time.Duration, bool) {
+	if lifetime, ok := cc.duration("s-maxage"); ok {
+		return lifetime, true
+	}
+
+	if lifetime, ok := cc.duration("max-age"); ok {
+		return lifetime, true
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			// An invalid date represents a time in the past.
+			return 0, true
+		}
+
+		date, err := http.ParseTime(header.Get("Date"))
+		if err != nil {
+			date = now
+		}
+
+		return expiresAt.Sub(date), true
+	}
+
+	if defaultTTL > 0 {
+		return defaultTTL, true
+	}
+
+	return 0, false
+}
+
+// age returns the age of the response when it was received, from its Age header.
+func age(header http.Header) time.Duration {
+	seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entry is a response stored in the cache.
+type entry struct {
+	// key is the key of the request the response is for.
+	key string
+	// vary are the names of the request headers listed in the Vary header of the response,
+	// and varyValues the values they had in the request.
+	vary       []string
+	varyValues string
+
+	status int
+	header http.Header
+	body   []byte
+
+	// storedAt is when the response was stored, and age its age at that time (from the Age header).
+	storedAt time.Time
+	age      time.Duration
+	// freshUntil is when the response becomes stale, and staleUntil when it can't be served anymore.
+	freshUntil time.Time
+	staleUntil time.Time
+}
+
+// size returns an estimate of the memory used by the entry.
+func (e *entry) size() int64 {
+	size := len(e.key) + len(e.varyValues) + len(e.body)
+	for name, values := range e.header {
+		size += len(name)
+		for _, value := range values {
+			size += len(value)
+		}
+	}
+	return int64(size)
+}
+
+// store is an in-memory LRU store of responses, bounded by size.
+type store struct {
+	maxSize int64
+
+	mu       sync.Mutex
+	size     int64
+	lru      *list.List
+	variants map[string][]*list.Element
+}
+
+func newStore(maxSize int64) *store {
+	return &store{
+		maxSize:  maxSize,
+		lru:      list.New(),
+		variants: make(map[string][]*list.Element),
+	}
+}
+
+// get returns the entry stored for the given key, matching the headers of the request, if any and still servable at now.
+func (s *store) get(key string, header http.Header, now time.Time) *entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, elem := range s.variants[key] {
+		e := elem.Value.(*entry)
+		if e.varyValues != varyValues(e.vary, header) {
+			continue
+		}
+
+		if !now.Before(e.staleUntil) {
+			s.remove(elem)
+			return nil
+		}
+
+		s.lru.MoveToFront(elem)
+		return e
+	}
+
+	return nil
+}
+
+// set stores the entry, replacing the one stored for the same key and headers, if any,
+// and evicts the least recently used entries while the store is too large.
+func (s *store) set(e *entry) {
+	size := e.size()
+	if size > s.maxSize {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, elem := range s.variants[e.key] {
+		if elem.Value.(*entry).varyValues == e.varyValues {
+			s.remove(elem)
+			break
+		}
+	}
+
+	s.variants[e.key] = append(s.variants[e.key], s.lru.PushFront(e))
+	s.size += size
+
+	for s.size > s.maxSize {
+		s.remove(s.lru.Back())
+	}
+}
+
+// delete removes all the entries stored for the given key.
+func (s *store) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, elem := range s.variants[key] {
+		s.lru.Remove(elem)
+		s.size -= elem.Value.(*entry).size()
+	}
+	delete(s.variants, key)
+}
+
+func (s *store) remove(elem *list.Element) {
+	e := s.lru.Remove(elem).(*entry)
+	s.size -= e.size()
+
+	variants := s.variants[e.key]
+	for i, v := range variants {
+		if v == elem {
+			variants = append(variants[:i], variants[i+1:]...)
+			break
+		}
+	}
+
+	if len(variants) == 0 {
+		delete(s.variants, e.key)
+		return
+	}
+	s.variants[e.key] = variants
+}
+
+// parseVary returns the canonical names of the request headers listed in the Vary header.
+func parseVary(header http.Header) []string {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+// varyValues returns the values of the given request headers, as a string identifying a variant of a response.
+func varyValues(names []string, header http.Header) string {
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = strings.Join(header.Values(name), ",")
+	}
+	return strings.Join(values, "\n")
+}
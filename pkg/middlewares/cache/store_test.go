@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreEviction(t *testing.T) {
+	now := time.Now()
+	newEntry := func(key string) *entry {
+		return &entry{key: key, body: make([]byte, 10), staleUntil: now.Add(time.Minute)}
+	}
+
+	s := newStore(36)
+
+	s.set(newEntry("a"))
+	s.set(newEntry("b"))
+	s.set(newEntry("c"))
+	assert.Equal(t, int64(33), s.size)
+
+	// a becomes the most recently used entry.
+	require.NotNil(t, s.get("a", http.Header{}, now))
+
+	s.set(newEntry("d"))
+	assert.Equal(t, int64(33), s.size)
+	assert.NotNil(t, s.get("a", http.Header{}, now))
+	assert.Nil(t, s.get("b", http.Header{}, now))
+	assert.NotNil(t, s.get("c", http.Header{}, now))
+	assert.NotNil(t, s.get("d", http.Header{}, now))
+
+	// An entry larger than the store is not stored.
+	s.set(&entry{key: "e", body: make([]byte, 40)})
+	assert.Nil(t, s.get("e", http.Header{}, now))
+	assert.Equal(t, int64(33), s.size)
+}
+
+func TestStoreVariants(t *testing.T) {
+	now := time.Now()
+	vary := []string{"Accept-Encoding"}
+
+	s := newStore(1024)
+	s.set(&entry{key: "a", vary: vary, varyValues: "gzip", body: []byte("gzip"), staleUntil: now.Add(time.Minute)})
+	s.set(&entry{key: "a", vary: vary, varyValues: "", body: []byte("identity"), staleUntil: now.Add(time.Minute)})
+	s.set(&entry{key: "a", vary: vary, varyValues: "gzip", body: []byte("gzip2"), staleUntil: now.Add(time.Minute)})
+
+	e := s.get("a", http.Header{"Accept-Encoding": {"gzip"}}, now)
+	require.NotNil(t, e)
+	assert.Equal(t, "gzip2", string(e.body))
+
+	e = s.get("a", http.Header{}, now)
+	require.NotNil(t, e)
+	assert.Equal(t, "identity", string(e.body))
+
+	assert.Nil(t, s.get("a", http.Header{"Accept-Encoding": {"br"}}, now))
+
+	// The expired entries are removed.
+	assert.Nil(t, s.get("a", http.Header{}, now.Add(2*time.Minute)))
+	assert.Len(t, s.variants["a"], 1)
+
+	s.delete("a")
+	assert.Empty(t, s.variants)
+	assert.Equal(t, int64(0), s.size)
+}
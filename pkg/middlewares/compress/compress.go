@@ -1,12 +1,15 @@
 package compress
 
 import (
-	"compress/gzip"
 	"context"
+	"errors"
+	"fmt"
 	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/NYTimes/gziphandler"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
@@ -16,19 +19,33 @@ import (
 
 const (
 	typeName = "Compress"
+
+	// defaultMinSize is the default minimum size of a response body, in bytes, for it to be compressed.
+	defaultMinSize = 1400
 )
 
+// defaultEncodings is the list of the supported encodings, by order of preference.
+var defaultEncodings = []string{zstdName, brotliName, gzipName}
+
 // Compress is a middleware that allows to compress the response.
 type compress struct {
-	next     http.Handler
-	name     string
-	excludes []string
+	next      http.Handler
+	name      string
+	excludes  []string
+	includes  []string
+	minSize   int
+	encodings []string
+	pools     map[string]*sync.Pool
 }
 
 // New creates a new compress middleware.
 func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
+	if len(conf.ExcludedContentTypes) > 0 && len(conf.IncludedContentTypes) > 0 {
+		return nil, errors.New("excludedContentTypes and includedContentTypes options are mutually exclusive")
+	}
+
 	excludes := []string{"application/grpc"}
 	for _, v := range conf.ExcludedContentTypes {
 		mediaType, _, err := mime.ParseMediaType(v)
@@ -39,7 +56,52 @@ func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name str
 		excludes = append(excludes, mediaType)
 	}
 
-	return &compress{next: next, name: name, excludes: excludes}, nil
+	var includes []string
+	for _, v := range conf.IncludedContentTypes {
+		mediaType, _, err := mime.ParseMediaType(v)
+		if err != nil {
+			return nil, err
+		}
+
+		includes = append(includes, mediaType)
+	}
+
+	if conf.MinResponseBodyBytes < 0 {
+		return nil, fmt.Errorf("minResponseBodyBytes must be positive: %d", conf.MinResponseBodyBytes)
+	}
+
+	minSize := defaultMinSize
+	if conf.MinResponseBodyBytes > 0 {
+		minSize = conf.MinResponseBodyBytes
+	}
+
+	encodings := defaultEncodings
+	if len(conf.Encodings) > 0 {
+		encodings = nil
+		for _, encoding := range conf.Encodings {
+			encodings = append(encodings, strings.ToLower(strings.TrimSpace(encoding)))
+		}
+	}
+
+	pools := make(map[string]*sync.Pool)
+	for _, encoding := range encodings {
+		pool, err := newCompressorPool(encoding, conf)
+		if err != nil {
+			return nil, err
+		}
+
+		pools[encoding] = pool
+	}
+
+	return &compress{
+		next:      next,
+		name:      name,
+		excludes:  excludes,
+		includes:  includes,
+		minSize:   minSize,
+		encodings: encodings,
+		pools:     pools,
+	}, nil
 }
 
 func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -50,25 +112,128 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	if contains(c.excludes, mediaType) {
 		c.next.ServeHTTP(rw, req)
-	} else {
-		ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
-		gzipHandler(ctx, c.next).ServeHTTP(rw, req)
+		return
 	}
+
+	rw.Header().Add("Vary", "Accept-Encoding")
+
+	encoding := c.negotiate(req.Header.Get("Accept-Encoding"))
+	if encoding == "" {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	crw := &responseWriter{
+		rw:       rw,
+		encoding: encoding,
+		pool:     c.pools[encoding],
+		minSize:  c.minSize,
+		accepts:  c.acceptsContentType,
+	}
+
+	defer func() {
+		if err := crw.close(); err != nil {
+			log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).Error(err)
+		}
+	}()
+
+	c.next.ServeHTTP(crw, req)
 }
 
 func (c *compress) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return c.name, tracing.SpanKindNoneEnum
 }
 
-func gzipHandler(ctx context.Context, h http.Handler) http.Handler {
-	wrapper, err := gziphandler.GzipHandlerWithOpts(
-		gziphandler.CompressionLevel(gzip.DefaultCompression),
-		gziphandler.MinSize(gziphandler.DefaultMinSize))
+// negotiate returns the encoding, among the configured ones, with the highest quality in the given Accept-Encoding header.
+// When several encodings have the same quality, the first one in the configured order wins.
+// It returns an empty string if none of the encodings is acceptable.
+func (c *compress) negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, quality, ok := parseCoding(part)
+		if !ok {
+			continue
+		}
+
+		if coding == "*" {
+			wildcard = quality
+			continue
+		}
+
+		qualities[coding] = quality
+	}
+
+	var best string
+	var bestQuality float64
+	for _, encoding := range c.encodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			if wildcard < 0 {
+				continue
+			}
+			quality = wildcard
+		}
+
+		if quality > bestQuality {
+			best = encoding
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+// acceptsContentType reports whether a response with the given Content-Type header can be compressed.
+func (c *compress) acceptsContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
-		log.FromContext(ctx).Error(err)
+		return len(c.includes) == 0
+	}
+
+	if len(c.includes) > 0 {
+		return contains(c.includes, mediaType)
+	}
+
+	return !contains(c.excludes, mediaType)
+}
+
+// parseCoding parses a content coding of an Accept-Encoding header, with its optional quality value.
+func parseCoding(value string) (string, float64, bool) {
+	parts := strings.Split(value, ";")
+
+	coding := strings.ToLower(strings.TrimSpace(parts[0]))
+	if coding == "" {
+		return "", 0, false
+	}
+
+	if coding == "x-gzip" {
+		coding = gzipName
+	}
+
+	quality := 1.0
+	for _, param := range parts[1:] {
+		key, val := param, ""
+		if i := strings.Index(param, "="); i >= 0 {
+			key, val = param[:i], param[i+1:]
+		}
+
+		if !strings.EqualFold(strings.TrimSpace(key), "q") {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil || q < 0 || q > 1 {
+			return "", 0, false
+		}
+		quality = q
 	}
 
-	return wrapper(h)
+	return coding, quality, true
 }
 
 func contains(values []string, val string) bool {
@@ -1,15 +1,21 @@
 package compress
 
 import (
+	"compress/gzip"
 	"context"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/NYTimes/gziphandler"
+	"github.com/andybalholm/brotli"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,7 +38,8 @@ func TestShouldCompressWhenNoContentEncodingHeader(t *testing.T) {
 		_, err := rw.Write(baseBody)
 		assert.NoError(t, err)
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -58,7 +65,8 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -79,7 +87,8 @@ func TestShouldNotCompressWhenNoAcceptEncodingHeader(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -178,7 +187,9 @@ func TestIntegrationShouldNotCompress(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
-			compress := &compress{next: test.handler}
+			compress, err := New(context.Background(), test.handler, dynamic.Compress{}, "testing")
+			require.NoError(t, err)
+
 			ts := httptest.NewServer(compress)
 			defer ts.Close()
 
@@ -211,7 +222,8 @@ func TestShouldWriteHeaderWhenFlush(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	require.NoError(t, err)
 	ts := httptest.NewServer(handler)
 	defer ts.Close()
 
@@ -260,7 +272,9 @@ func TestIntegrationShouldCompress(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
-			compress := &compress{next: test.handler}
+			compress, err := New(context.Background(), test.handler, dynamic.Compress{}, "testing")
+			require.NoError(t, err)
+
 			ts := httptest.NewServer(compress)
 			defer ts.Close()
 
@@ -284,6 +298,291 @@ func TestIntegrationShouldCompress(t *testing.T) {
 	}
 }
 
+func TestNegotiateEncoding(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		encodings      []string
+		acceptEncoding string
+		expected       string
+	}{
+		{
+			desc:           "no Accept-Encoding header",
+			acceptEncoding: "",
+			expected:       "",
+		},
+		{
+			desc:           "gzip only",
+			acceptEncoding: "gzip",
+			expected:       gzipName,
+		},
+		{
+			desc:           "x-gzip",
+			acceptEncoding: "x-gzip",
+			expected:       gzipName,
+		},
+		{
+			desc:           "same quality, server preference",
+			acceptEncoding: "gzip, deflate, br, zstd",
+			expected:       zstdName,
+		},
+		{
+			desc:           "highest quality wins",
+			acceptEncoding: "gzip;q=1.0, br;q=0.8, zstd;q=0.5",
+			expected:       gzipName,
+		},
+		{
+			desc:           "refused encoding",
+			acceptEncoding: "br;q=0, gzip;q=0.1",
+			expected:       gzipName,
+		},
+		{
+			desc:           "wildcard",
+			acceptEncoding: "*",
+			expected:       zstdName,
+		},
+		{
+			desc:           "wildcard with refused encoding",
+			acceptEncoding: "zstd;q=0, *;q=0.5",
+			expected:       brotliName,
+		},
+		{
+			desc:           "unsupported encodings",
+			acceptEncoding: "deflate, identity",
+			expected:       "",
+		},
+		{
+			desc:           "invalid quality",
+			acceptEncoding: "zstd;q=foo, br;q=2, gzip",
+			expected:       gzipName,
+		},
+		{
+			desc:           "configured encodings",
+			encodings:      []string{"gzip", "br"},
+			acceptEncoding: "zstd, br, gzip",
+			expected:       gzipName,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := New(context.Background(), http.NotFoundHandler(), dynamic.Compress{Encodings: test.encodings}, "testing")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, handler.(*compress).negotiate(test.acceptEncoding))
+		})
+	}
+}
+
+func TestShouldCompressWithEncoding(t *testing.T) {
+	baseBody := generateBytes(gziphandler.DefaultMinSize)
+
+	testCases := []struct {
+		desc     string
+		encoding string
+		decode   func(r io.Reader) ([]byte, error)
+	}{
+		{
+			desc:     "gzip",
+			encoding: gzipName,
+			decode: func(r io.Reader) ([]byte, error) {
+				reader, err := gzip.NewReader(r)
+				if err != nil {
+					return nil, err
+				}
+				return ioutil.ReadAll(reader)
+			},
+		},
+		{
+			desc:     "brotli",
+			encoding: brotliName,
+			decode: func(r io.Reader) ([]byte, error) {
+				return ioutil.ReadAll(brotli.NewReader(r))
+			},
+		},
+		{
+			desc:     "zstd",
+			encoding: zstdName,
+			decode: func(r io.Reader) ([]byte, error) {
+				reader, err := zstd.NewReader(r)
+				if err != nil {
+					return nil, err
+				}
+				defer reader.Close()
+				return ioutil.ReadAll(reader)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Content-Length", strconv.Itoa(len(baseBody)))
+				_, err := rw.Write(baseBody)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Add(acceptEncodingHeader, test.encoding)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.encoding, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+			assert.Empty(t, rw.Header().Get("Content-Length"))
+
+			body, err := test.decode(rw.Body)
+			require.NoError(t, err)
+			assert.Equal(t, baseBody, body)
+		})
+	}
+}
+
+func TestShouldCompressAccordingToResponse(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		conf                dynamic.Compress
+		respContentType     string
+		bodySize            int
+		expectedCompression bool
+	}{
+		{
+			desc:                "smaller than the default minimum size",
+			conf:                dynamic.Compress{},
+			bodySize:            gziphandler.DefaultMinSize - 1,
+			expectedCompression: false,
+		},
+		{
+			desc:                "larger than the configured minimum size",
+			conf:                dynamic.Compress{MinResponseBodyBytes: 100},
+			bodySize:            100,
+			expectedCompression: true,
+		},
+		{
+			desc:                "smaller than the configured minimum size",
+			conf:                dynamic.Compress{MinResponseBodyBytes: 2000},
+			bodySize:            1999,
+			expectedCompression: false,
+		},
+		{
+			desc:                "excluded response content type",
+			conf:                dynamic.Compress{ExcludedContentTypes: []string{"image/png"}},
+			respContentType:     "image/png",
+			bodySize:            gziphandler.DefaultMinSize,
+			expectedCompression: false,
+		},
+		{
+			desc:                "included response content type",
+			conf:                dynamic.Compress{IncludedContentTypes: []string{"application/json", "text/html"}},
+			respContentType:     "text/html; charset=utf-8",
+			bodySize:            gziphandler.DefaultMinSize,
+			expectedCompression: true,
+		},
+		{
+			desc:                "not included response content type",
+			conf:                dynamic.Compress{IncludedContentTypes: []string{"application/json"}},
+			respContentType:     "text/html; charset=utf-8",
+			bodySize:            gziphandler.DefaultMinSize,
+			expectedCompression: false,
+		},
+		{
+			desc:                "sniffed response content type",
+			conf:                dynamic.Compress{IncludedContentTypes: []string{"text/plain"}},
+			bodySize:            gziphandler.DefaultMinSize,
+			expectedCompression: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			baseBody := []byte(strings.Repeat("a", test.bodySize))
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if test.respContentType != "" {
+					rw.Header().Set(contentTypeHeader, test.respContentType)
+				}
+				_, err := rw.Write(baseBody)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, test.conf, "testing")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Add(acceptEncodingHeader, gzipValue)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			if test.expectedCompression {
+				assert.Equal(t, gzipValue, rw.Header().Get(contentEncodingHeader))
+				assert.NotEqual(t, baseBody, rw.Body.Bytes())
+				return
+			}
+
+			assert.Empty(t, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, baseBody, rw.Body.Bytes())
+		})
+	}
+}
+
+func TestNewWithInvalidConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc string
+		conf dynamic.Compress
+	}{
+		{
+			desc: "both excluded and included content types",
+			conf: dynamic.Compress{
+				ExcludedContentTypes: []string{"text/event-stream"},
+				IncludedContentTypes: []string{"text/html"},
+			},
+		},
+		{
+			desc: "negative minimum size",
+			conf: dynamic.Compress{MinResponseBodyBytes: -1},
+		},
+		{
+			desc: "unsupported encoding",
+			conf: dynamic.Compress{Encodings: []string{"deflate"}},
+		},
+		{
+			desc: "invalid gzip level",
+			conf: dynamic.Compress{GzipLevel: 10},
+		},
+		{
+			desc: "invalid brotli level",
+			conf: dynamic.Compress{BrotliLevel: 12},
+		},
+		{
+			desc: "invalid zstd level",
+			conf: dynamic.Compress{ZstdLevel: 23},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.conf, "testing")
+			assert.Error(t, err)
+		})
+	}
+}
+
 func generateBytes(len int) []byte {
 	var value []byte
 	for i := 0; i < len; i++ {
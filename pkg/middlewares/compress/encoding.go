@@ -0,0 +1,89 @@
+package compress
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	gzipName   = "gzip"
+	brotliName = "br"
+	zstdName   = "zstd"
+)
+
+const defaultZstdLevel = 3
+
+// compressor is a compression writer which can be reused through Reset.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// newCompressorPool creates a pool of compressors for the given encoding, using the level of the configuration.
+func newCompressorPool(encoding string, conf dynamic.Compress) (*sync.Pool, error) {
+	switch encoding {
+	case gzipName:
+		level := gzip.DefaultCompression
+		if conf.GzipLevel != 0 {
+			level = conf.GzipLevel
+		}
+
+		if level != gzip.DefaultCompression && (level < gzip.BestSpeed || level > gzip.BestCompression) {
+			return nil, fmt.Errorf("invalid gzip compression level: %d", conf.GzipLevel)
+		}
+
+		return &sync.Pool{New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(nil, level)
+			return w
+		}}, nil
+
+	case brotliName:
+		level := brotli.DefaultCompression
+		if conf.BrotliLevel != 0 {
+			level = conf.BrotliLevel
+		}
+
+		if level < 1 || level > brotli.BestCompression {
+			return nil, fmt.Errorf("invalid brotli compression level: %d", conf.BrotliLevel)
+		}
+
+		return &sync.Pool{New: func() interface{} {
+			return brotli.NewWriterLevel(nil, level)
+		}}, nil
+
+	case zstdName:
+		level := defaultZstdLevel
+		if conf.ZstdLevel != 0 {
+			level = conf.ZstdLevel
+		}
+
+		if level < 1 || level > 22 {
+			return nil, fmt.Errorf("invalid zstd compression level: %d", conf.ZstdLevel)
+		}
+
+		// A single goroutine per encoder, as responses are compressed concurrently.
+		options := []zstd.EOption{
+			zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)),
+			zstd.WithEncoderConcurrency(1),
+		}
+
+		if _, err := zstd.NewWriter(nil, options...); err != nil {
+			return nil, err
+		}
+
+		return &sync.Pool{New: func() interface{} {
+			w, _ := zstd.NewWriter(nil, options...)
+			return w
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %q", encoding)
+	}
+}
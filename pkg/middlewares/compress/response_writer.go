@@ -0,0 +1,187 @@
+package compress
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// responseWriter buffers the beginning of the response body,
+// until it knows whether the response should be compressed or not.
+type responseWriter struct {
+	rw       http.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+	accepts  func(contentType string) bool
+
+	statusCode int
+	buf        []byte
+	decided    bool
+	hijacked   bool
+	compressor compressor
+}
+
+func (r *responseWriter) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseWriter) WriteHeader(statusCode int) {
+	if r.decided || r.statusCode != 0 {
+		return
+	}
+
+	r.statusCode = statusCode
+
+	// The response is already encoded, there is no need to wait for the body.
+	if r.Header().Get("Content-Encoding") != "" {
+		r.decided = true
+		r.writeHeader()
+	}
+}
+
+func (r *responseWriter) Write(p []byte) (int, error) {
+	if !r.decided {
+		if r.statusCode == 0 {
+			r.statusCode = http.StatusOK
+		}
+
+		if r.Header().Get("Content-Encoding") != "" {
+			if err := r.startPlain(); err != nil {
+				return 0, err
+			}
+		} else {
+			r.buf = append(r.buf, p...)
+			if len(r.buf) < r.minSize {
+				return len(p), nil
+			}
+
+			return len(p), r.decide()
+		}
+	}
+
+	if r.compressor != nil {
+		return r.compressor.Write(p)
+	}
+
+	return r.rw.Write(p)
+}
+
+// Flush sends the buffered data to the client.
+// A response which is flushed before reaching the minimum size is compressed anyway,
+// as it is most likely a streamed response.
+func (r *responseWriter) Flush() {
+	if !r.decided {
+		if err := r.decide(); err != nil {
+			return
+		}
+	}
+
+	if r.compressor != nil {
+		if err := r.compressor.Flush(); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+	}
+
+	r.hijacked = true
+
+	return hijacker.Hijack()
+}
+
+// decide starts either a compressed or a plain response, according to the response headers.
+func (r *responseWriter) decide() error {
+	header := r.Header()
+
+	if header.Get("Content-Type") == "" && len(r.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(r.buf))
+	}
+
+	switch {
+	case header.Get("Content-Encoding") != "",
+		header.Get("Content-Range") != "",
+		r.statusCode == http.StatusNoContent,
+		r.statusCode == http.StatusNotModified,
+		!r.accepts(header.Get("Content-Type")):
+		return r.startPlain()
+	default:
+		return r.startCompressed()
+	}
+}
+
+func (r *responseWriter) startPlain() error {
+	r.decided = true
+
+	r.writeHeader()
+
+	if len(r.buf) == 0 {
+		return nil
+	}
+
+	_, err := r.rw.Write(r.buf)
+	r.buf = nil
+
+	return err
+}
+
+func (r *responseWriter) startCompressed() error {
+	r.decided = true
+
+	header := r.Header()
+	header.Set("Content-Encoding", r.encoding)
+	header.Del("Content-Length")
+	header.Del("Accept-Ranges")
+
+	r.writeHeader()
+
+	r.compressor = r.pool.Get().(compressor)
+	r.compressor.Reset(r.rw)
+
+	if len(r.buf) == 0 {
+		return nil
+	}
+
+	_, err := r.compressor.Write(r.buf)
+	r.buf = nil
+
+	return err
+}
+
+func (r *responseWriter) writeHeader() {
+	if r.statusCode != 0 {
+		r.rw.WriteHeader(r.statusCode)
+	}
+}
+
+// close sends the remaining buffered data, and terminates the compressed stream, if any.
+func (r *responseWriter) close() error {
+	if r.hijacked {
+		return nil
+	}
+
+	if !r.decided {
+		return r.startPlain()
+	}
+
+	if r.compressor == nil {
+		return nil
+	}
+
+	err := r.compressor.Close()
+	r.pool.Put(r.compressor)
+	r.compressor = nil
+
+	return err
+}
@@ -1,6 +1,7 @@
 package emptybackendhandler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/containous/traefik/v2/pkg/healthcheck"
@@ -31,3 +32,12 @@ func (e *emptyBackend) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		e.next.ServeHTTP(rw, req)
 	}
 }
+
+// RegisterStatusUpdater registers fn with the load-balancer, if it propagates its status.
+func (e *emptyBackend) RegisterStatusUpdater(fn func(up bool)) error {
+	statusUpdater, ok := e.next.(healthcheck.StatusUpdater)
+	if !ok {
+		return errors.New("the load-balancer does not propagate its status")
+	}
+	return statusUpdater.RegisterStatusUpdater(fn)
+}
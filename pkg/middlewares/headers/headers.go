@@ -4,7 +4,9 @@ package headers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -39,6 +41,12 @@ func New(ctx context.Context, next http.Handler, config dynamic.Headers, name st
 		return nil, errors.New("headers configuration not valid")
 	}
 
+	for _, expr := range config.AccessControlAllowOriginListRegex {
+		if _, err := regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("invalid accessControlAllowOriginListRegex %q: %w", expr, err)
+		}
+	}
+
 	var handler http.Handler
 	nextHandler := next
 
@@ -50,7 +58,9 @@ func New(ctx context.Context, next http.Handler, config dynamic.Headers, name st
 
 	if hasCustomHeaders || hasCorsHeaders {
 		logger.Debug("Setting up customHeaders/Cors from %v", config)
-		handler = NewHeader(nextHandler, config)
+		header := NewHeader(nextHandler, config)
+		header.name = name
+		handler = header
 	}
 
 	return &headers{
@@ -112,10 +122,12 @@ func (s secureHeader) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 // A single headerOptions struct can be provided to configure which features should be enabled,
 // and the ability to override a few of the default values.
 type Header struct {
-	next             http.Handler
-	hasCustomHeaders bool
-	hasCorsHeaders   bool
-	headers          *dynamic.Headers
+	next               http.Handler
+	name               string
+	hasCustomHeaders   bool
+	hasCorsHeaders     bool
+	headers            *dynamic.Headers
+	allowOriginRegexes []*regexp.Regexp
 }
 
 // NewHeader constructs a new header instance from supplied frontend header struct.
@@ -123,11 +135,20 @@ func NewHeader(next http.Handler, headers dynamic.Headers) *Header {
 	hasCustomHeaders := headers.HasCustomHeadersDefined()
 	hasCorsHeaders := headers.HasCorsHeadersDefined()
 
+	// Invalid expressions are reported when the middleware is created.
+	var allowOriginRegexes []*regexp.Regexp
+	for _, expr := range headers.AccessControlAllowOriginListRegex {
+		if regex, err := regexp.Compile(expr); err == nil {
+			allowOriginRegexes = append(allowOriginRegexes, regex)
+		}
+	}
+
 	return &Header{
-		next:             next,
-		headers:          &headers,
-		hasCustomHeaders: hasCustomHeaders,
-		hasCorsHeaders:   hasCorsHeaders,
+		next:               next,
+		headers:            &headers,
+		hasCustomHeaders:   hasCustomHeaders,
+		hasCorsHeaders:     hasCorsHeaders,
+		allowOriginRegexes: allowOriginRegexes,
 	}
 }
 
@@ -165,8 +186,7 @@ func (s *Header) modifyCustomRequestHeaders(req *http.Request) {
 // One notable example of a header that can only be modified later on is "Vary",
 // And this is set in the post-response response modifier method
 func (s *Header) preRequestModifyCorsResponseHeaders(rw http.ResponseWriter, req *http.Request) {
-	originHeader := req.Header.Get("Origin")
-	allowOrigin := s.getAllowOrigin(originHeader)
+	allowOrigin := s.getAllowOrigin(req)
 
 	if allowOrigin != "" {
 		rw.Header().Set("Access-Control-Allow-Origin", allowOrigin)
@@ -194,7 +214,7 @@ func (s *Header) PostRequestModifyResponseHeaders(res *http.Response) error {
 			res.Header.Set(header, value)
 		}
 	}
-	if !s.headers.AddVaryHeader {
+	if !s.headers.AddVaryHeader && !s.hasAllowOriginList() {
 		return nil
 	}
 
@@ -227,6 +247,8 @@ func (s *Header) processCorsHeaders(rw http.ResponseWriter, req *http.Request) b
 		// If the request is an OPTIONS request with an Access-Control-Request-Method header,
 		// and Origin headers, then it is a CORS preflight request,
 		// and we need to build a custom response: https://www.w3.org/TR/cors/#preflight-request
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.name, typeName)).Debug("Answering CORS preflight request")
+
 		if s.headers.AccessControlAllowCredentials {
 			rw.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
@@ -241,12 +263,16 @@ func (s *Header) processCorsHeaders(rw http.ResponseWriter, req *http.Request) b
 			rw.Header().Set("Access-Control-Allow-Methods", allowMethods)
 		}
 
-		allowOrigin := s.getAllowOrigin(originHeader)
+		allowOrigin := s.getAllowOrigin(req)
 
 		if allowOrigin != "" {
 			rw.Header().Set("Access-Control-Allow-Origin", allowOrigin)
 		}
 
+		if s.headers.AddVaryHeader || s.hasAllowOriginList() {
+			rw.Header().Add("Vary", "Origin")
+		}
+
 		rw.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(s.headers.AccessControlMaxAge)))
 		return true
 	}
@@ -255,16 +281,76 @@ func (s *Header) processCorsHeaders(rw http.ResponseWriter, req *http.Request) b
 	return false
 }
 
-func (s *Header) getAllowOrigin(header string) string {
+// getAllowOrigin returns the value of the Access-Control-Allow-Origin header for the given request,
+// or an empty string if its origin is not allowed.
+func (s *Header) getAllowOrigin(req *http.Request) string {
+	origin := req.Header.Get("Origin")
+
 	switch s.headers.AccessControlAllowOrigin {
 	case "origin-list-or-null":
-		if len(header) == 0 {
+		if len(origin) == 0 {
 			return "null"
 		}
-		return header
+		return origin
 	case "*":
 		return "*"
-	default:
+	}
+
+	if origin == "" || !s.hasAllowOriginList() {
 		return ""
 	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.name, typeName))
+
+	for _, allowed := range s.headers.AccessControlAllowOriginList {
+		if !matchOrigin(allowed, origin) {
+			continue
+		}
+
+		logger.Debugf("Origin %q matched the allowed origin %q", origin, allowed)
+
+		if allowed == "*" {
+			return "*"
+		}
+		return origin
+	}
+
+	for _, regex := range s.allowOriginRegexes {
+		if regex.MatchString(origin) {
+			logger.Debugf("Origin %q matched the allowed origin regex %q", origin, regex.String())
+			return origin
+		}
+	}
+
+	logger.Debugf("Origin %q is not allowed", origin)
+
+	return ""
+}
+
+func (s *Header) hasAllowOriginList() bool {
+	return len(s.headers.AccessControlAllowOriginList) > 0 || len(s.allowOriginRegexes) > 0
+}
+
+// matchOrigin reports whether the origin matches the allowed one,
+// which can be "*", or contain a wildcard in place of its subdomains, e.g. "https://*.example.com".
+func matchOrigin(allowed, origin string) bool {
+	if allowed == "*" || strings.EqualFold(allowed, origin) {
+		return true
+	}
+
+	i := strings.Index(allowed, "*.")
+	if i < 0 {
+		return false
+	}
+
+	prefix, suffix := allowed[:i], allowed[i+1:]
+	if len(origin) <= len(prefix)+len(suffix) ||
+		!strings.EqualFold(origin[:len(prefix)], prefix) ||
+		!strings.EqualFold(origin[len(origin)-len(suffix):], suffix) {
+		return false
+	}
+
+	subdomain := origin[len(prefix) : len(origin)-len(suffix)]
+
+	return !strings.ContainsAny(subdomain, "/:@")
 }
@@ -294,6 +294,41 @@ func TestCORSPreflights(t *testing.T) {
 				"Access-Control-Allow-Headers": {"origin,X-Forwarded-For"},
 			},
 		},
+		{
+			desc: "Origin List Preflight",
+			header: NewHeader(emptyHandler, dynamic.Headers{
+				AccessControlAllowMethods:    []string{"GET", "OPTIONS", "PUT"},
+				AccessControlAllowOriginList: []string{"https://foo.bar.org", "https://*.example.com"},
+				AccessControlMaxAge:          600,
+			}),
+			requestHeaders: map[string][]string{
+				"Access-Control-Request-Method": {"GET", "OPTIONS"},
+				"Origin":                        {"https://api.example.com"},
+			},
+			expected: map[string][]string{
+				"Access-Control-Allow-Origin":  {"https://api.example.com"},
+				"Access-Control-Max-Age":       {"600"},
+				"Access-Control-Allow-Methods": {"GET,OPTIONS,PUT"},
+				"Vary":                         {"Origin"},
+			},
+		},
+		{
+			desc: "Not Allowed Origin Preflight",
+			header: NewHeader(emptyHandler, dynamic.Headers{
+				AccessControlAllowMethods:         []string{"GET", "OPTIONS", "PUT"},
+				AccessControlAllowOriginListRegex: []string{`^https://([a-z]+\.)?bar\.org$`},
+				AccessControlMaxAge:               600,
+			}),
+			requestHeaders: map[string][]string{
+				"Access-Control-Request-Method": {"GET", "OPTIONS"},
+				"Origin":                        {"https://foo.bar.com"},
+			},
+			expected: map[string][]string{
+				"Access-Control-Max-Age":       {"600"},
+				"Access-Control-Allow-Methods": {"GET,OPTIONS,PUT"},
+				"Vary":                         {"Origin"},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -469,6 +504,71 @@ func TestCORSResponses(t *testing.T) {
 				"Vary":                        {"Origin"},
 			},
 		},
+		{
+			desc: "Origin List Request",
+			header: NewHeader(emptyHandler, dynamic.Headers{
+				AccessControlAllowOriginList: []string{"https://foo.bar.org", "https://bar.foo.org"},
+			}),
+			requestHeaders: map[string][]string{
+				"Origin": {"https://bar.foo.org"},
+			},
+			expected: map[string][]string{
+				"Access-Control-Allow-Origin": {"https://bar.foo.org"},
+				"Vary":                        {"Origin"},
+			},
+		},
+		{
+			desc: "Wildcard Origin List Request",
+			header: NewHeader(emptyHandler, dynamic.Headers{
+				AccessControlAllowOriginList: []string{"https://foo.bar.org", "*"},
+			}),
+			requestHeaders: map[string][]string{
+				"Origin": {"https://bar.foo.org"},
+			},
+			expected: map[string][]string{
+				"Access-Control-Allow-Origin": {"*"},
+				"Vary":                        {"Origin"},
+			},
+		},
+		{
+			desc: "Wildcard Subdomain Origin List Request",
+			header: NewHeader(nonEmptyHandler, dynamic.Headers{
+				AccessControlAllowOriginList: []string{"https://*.bar.org"},
+			}),
+			requestHeaders: map[string][]string{
+				"Origin": {"https://foo.bar.org"},
+			},
+			expected: map[string][]string{
+				"Access-Control-Allow-Origin": {"https://foo.bar.org"},
+				"Vary":                        {"Testing,Origin"},
+			},
+		},
+		{
+			desc: "Origin Regex Request",
+			header: NewHeader(emptyHandler, dynamic.Headers{
+				AccessControlAllowOriginListRegex: []string{`^https://([a-z]+\.)?bar\.org$`},
+			}),
+			requestHeaders: map[string][]string{
+				"Origin": {"https://foo.bar.org"},
+			},
+			expected: map[string][]string{
+				"Access-Control-Allow-Origin": {"https://foo.bar.org"},
+				"Vary":                        {"Origin"},
+			},
+		},
+		{
+			desc: "Not Allowed Origin Request",
+			header: NewHeader(emptyHandler, dynamic.Headers{
+				AccessControlAllowOriginList:      []string{"https://*.bar.org"},
+				AccessControlAllowOriginListRegex: []string{`^https://foo\.bar\.com$`},
+			}),
+			requestHeaders: map[string][]string{
+				"Origin": {"https://bar.org"},
+			},
+			expected: map[string][]string{
+				"Vary": {"Origin"},
+			},
+		},
 		{
 			desc: "Test Simple CustomRequestHeaders Not Hijacked by CORS",
 			header: NewHeader(emptyHandler, dynamic.Headers{
@@ -497,6 +597,41 @@ func TestCORSResponses(t *testing.T) {
 	}
 }
 
+func TestMatchOrigin(t *testing.T) {
+	testCases := []struct {
+		allowed  string
+		origin   string
+		expected bool
+	}{
+		{allowed: "*", origin: "https://foo.bar.org", expected: true},
+		{allowed: "https://foo.bar.org", origin: "https://foo.bar.org", expected: true},
+		{allowed: "https://foo.bar.org", origin: "HTTPS://FOO.BAR.ORG", expected: true},
+		{allowed: "https://foo.bar.org", origin: "http://foo.bar.org", expected: false},
+		{allowed: "https://*.bar.org", origin: "https://foo.bar.org", expected: true},
+		{allowed: "https://*.bar.org", origin: "https://foo.foo.bar.org", expected: true},
+		{allowed: "https://*.bar.org", origin: "https://bar.org", expected: false},
+		{allowed: "https://*.bar.org", origin: "https://.bar.org", expected: false},
+		{allowed: "https://*.bar.org", origin: "https://foo.bar.org:8443", expected: false},
+		{allowed: "https://*.bar.org", origin: "https://foo.com/.bar.org", expected: false},
+		{allowed: "https://*.bar.org", origin: "https://user@foo.bar.org", expected: false},
+		{allowed: "https://*.bar.org:8443", origin: "https://foo.bar.org:8443", expected: true},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.allowed+" "+test.origin, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, matchOrigin(test.allowed, test.origin))
+		})
+	}
+}
+
+func TestNewWithInvalidOriginRegex(t *testing.T) {
+	_, err := New(context.Background(), nil, dynamic.Headers{AccessControlAllowOriginListRegex: []string{"("}}, "test")
+	assert.Error(t, err)
+}
+
 func TestCustomResponseHeaders(t *testing.T) {
 	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
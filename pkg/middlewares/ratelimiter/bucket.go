@@ -0,0 +1,75 @@
+package ratelimiter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// reservation is the outcome of the reservation of a token in a bucket.
+type reservation struct {
+	// delay is the duration to wait before the token is available.
+	// If it is greater than the maximum delay of the rate limiter, the token has not been taken.
+	delay time.Duration
+	// remaining is the number of tokens left in the bucket.
+	remaining int64
+	// reset is the duration after which the bucket is full again.
+	reset time.Duration
+}
+
+// tokenBucket is a bucket of tokens, refilled at a constant rate up to its burst.
+// It implements the same algorithm as the reserveScript used to keep the buckets in Redis.
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(burst int64, now time.Time) *tokenBucket {
+	return &tokenBucket{tokens: float64(burst), last: now}
+}
+
+// reserve takes a token from the bucket, if it is available within maxDelay.
+// rate is the number of tokens added to the bucket per second, and must be positive.
+func (b *tokenBucket) reserve(now time.Time, rate float64, burst int64, maxDelay time.Duration) reservation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tokens := b.tokens
+	last := b.last
+	if now.After(last) {
+		tokens = math.Min(float64(burst), tokens+now.Sub(last).Seconds()*rate)
+		last = now
+	}
+
+	tokens--
+
+	var delay time.Duration
+	if tokens < 0 {
+		delay = time.Duration(-tokens / rate * float64(time.Second))
+	}
+
+	if delay <= maxDelay {
+		b.tokens = tokens
+		b.last = last
+	} else {
+		tokens++
+	}
+
+	return newReservation(delay, tokens, rate, burst)
+}
+
+// newReservation returns the reservation for the given delay,
+// and the given number of tokens left in a bucket.
+func newReservation(delay time.Duration, tokens, rate float64, burst int64) reservation {
+	remaining := int64(math.Floor(tokens))
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return reservation{
+		delay:     delay,
+		remaining: remaining,
+		reset:     time.Duration((float64(burst) - tokens) / rate * float64(time.Second)),
+	}
+}
@@ -0,0 +1,30 @@
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(2, now)
+
+	// 1 token/s, with a burst of 2, and a maximum delay of 500ms.
+	reserve := func(at time.Duration) reservation {
+		return bucket.reserve(now.Add(at), 1, 2, 500*time.Millisecond)
+	}
+
+	assert.Equal(t, reservation{remaining: 1, reset: time.Second}, reserve(0))
+	assert.Equal(t, reservation{remaining: 0, reset: 2 * time.Second}, reserve(0))
+
+	// The bucket is empty, and the next token is available in more than the maximum delay, so it is not taken.
+	assert.Equal(t, reservation{delay: time.Second, remaining: 0, reset: 2 * time.Second}, reserve(0))
+
+	// The next token is available within the maximum delay.
+	assert.Equal(t, reservation{delay: 500 * time.Millisecond, remaining: 0, reset: 2500 * time.Millisecond}, reserve(500*time.Millisecond))
+
+	// The bucket is never refilled beyond its burst.
+	assert.Equal(t, reservation{remaining: 1, reset: time.Second}, reserve(time.Minute))
+}
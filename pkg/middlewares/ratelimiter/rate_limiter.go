@@ -3,8 +3,11 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
@@ -14,7 +17,6 @@ import (
 	"github.com/mailgun/ttlmap"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/vulcand/oxy/utils"
-	"golang.org/x/time/rate"
 )
 
 const (
@@ -22,23 +24,76 @@ const (
 	maxSources = 65536
 )
 
+type serviceBuilder interface {
+	BuildHTTP(ctx context.Context, serviceName string, responseModifier func(*http.Response) error) (http.Handler, error)
+}
+
+// limiter holds the token buckets of a rate limiter.
+type limiter interface {
+	// reserve takes a token from the bucket of the given source.
+	// If the token is not available within the maximum delay of the rate limiter, it is not taken.
+	reserve(ctx context.Context, source string) (reservation, error)
+}
+
 // rateLimiter implements rate limiting and traffic shaping with a set of token buckets;
 // one for each traffic source. The same parameters are applied to all the buckets.
 type rateLimiter struct {
 	name  string
-	rate  rate.Limit // reqs/s
+	rate  float64 // reqs/s
 	burst int64
 	// maxDelay is the maximum duration we're willing to wait for a bucket reservation to become effective, in nanoseconds.
 	// For now it is somewhat arbitrarily set to 1/(2*rate).
 	maxDelay      time.Duration
 	sourceMatcher utils.SourceExtractor
 	next          http.Handler
+	// rejectHandler, if not nil, handles the requests rejected by the rate limiter.
+	rejectHandler http.Handler
+
+	limiter limiter
+}
+
+// localLimiter keeps the token buckets in memory.
+type localLimiter struct {
+	rate     float64 // reqs/s
+	burst    int64
+	maxDelay time.Duration
 
 	buckets *ttlmap.TtlMap // actual buckets, keyed by source.
 }
 
+func newLocalLimiter(rtl float64, burst int64, maxDelay time.Duration) (*localLimiter, error) {
+	buckets, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localLimiter{
+		rate:     rtl,
+		burst:    burst,
+		maxDelay: maxDelay,
+		buckets:  buckets,
+	}, nil
+}
+
+func (l *localLimiter) reserve(_ context.Context, source string) (reservation, error) {
+	now := time.Now()
+
+	var bucket *tokenBucket
+	if rlSource, exists := l.buckets.Get(source); exists {
+		bucket = rlSource.(*tokenBucket)
+	} else {
+		bucket = newTokenBucket(l.burst, now)
+		if err := l.buckets.Set(source, bucket, int(l.maxDelay)*10+1); err != nil {
+			return reservation{}, fmt.Errorf("could not insert bucket: %w", err)
+		}
+	}
+
+	return bucket.reserve(now, l.rate, l.burst, l.maxDelay), nil
+}
+
 // New returns a rate limiter middleware.
-func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name string) (http.Handler, error) {
+// The Redis clients are used to share the token buckets, when a Redis server is configured.
+func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, serviceBuilder serviceBuilder, redisClients *RedisClients, name string) (http.Handler, error) {
 	ctxLog := log.With(ctx, log.Str(log.MiddlewareName, name), log.Str(log.MiddlewareType, typeName))
 	log.FromContext(ctxLog).Debug("Creating middleware")
 
@@ -55,11 +110,6 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		return nil, err
 	}
 
-	buckets, err := ttlmap.NewConcurrent(maxSources)
-	if err != nil {
-		return nil, err
-	}
-
 	burst := config.Burst
 	if burst < 1 {
 		burst = 1
@@ -86,14 +136,43 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		}
 	}
 
+	var lim limiter
+	lim, err = newLocalLimiter(rtl, burst, maxDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	// When Average is 0, there is no rate limiting, hence nothing to share.
+	if config.Redis != nil && config.Average > 0 {
+		if redisClients == nil {
+			return nil, errors.New("no Redis clients available")
+		}
+
+		client, err := redisClients.get(config.Redis)
+		if err != nil {
+			return nil, err
+		}
+
+		lim = newRedisLimiter(name, client, rtl, burst, maxDelay, lim)
+	}
+
+	var rejectHandler http.Handler
+	if config.Service != "" {
+		rejectHandler, err = serviceBuilder.BuildHTTP(ctx, config.Service, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &rateLimiter{
 		name:          name,
-		rate:          rate.Limit(rtl),
+		rate:          rtl,
 		burst:         burst,
 		maxDelay:      maxDelay,
 		next:          next,
+		rejectHandler: rejectHandler,
 		sourceMatcher: sourceMatcher,
-		buckets:       buckets,
+		limiter:       lim,
 	}, nil
 }
 
@@ -116,41 +195,77 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		logger.Infof("ignoring token bucket amount > 1: %d", amount)
 	}
 
-	var bucket *rate.Limiter
-	if rlSource, exists := rl.buckets.Get(source); exists {
-		bucket = rlSource.(*rate.Limiter)
-	} else {
-		bucket = rate.NewLimiter(rl.rate, int(rl.burst))
-		if err := rl.buckets.Set(source, bucket, int(rl.maxDelay)*10+1); err != nil {
-			logger.Errorf("could not insert bucket: %v", err)
-			http.Error(w, "could not insert bucket", http.StatusInternalServerError)
-			return
-		}
+	// No rate limiting.
+	if rl.rate == 0 {
+		rl.next.ServeHTTP(w, r)
+		return
 	}
 
-	res := bucket.Reserve()
-	if !res.OK() {
-		http.Error(w, "No bursty traffic allowed", http.StatusTooManyRequests)
+	res, err := rl.limiter.reserve(ctx, source)
+	if err != nil {
+		logger.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	delay := res.Delay()
-	if delay > rl.maxDelay {
-		res.Cancel()
-		rl.serveDelayError(ctx, w, r, delay)
+	w.Header().Set("RateLimit-Limit", strconv.FormatInt(rl.burst, 10))
+	w.Header().Set("RateLimit-Remaining", strconv.FormatInt(res.remaining, 10))
+	w.Header().Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(res.reset), 10))
+
+	if res.delay > rl.maxDelay {
+		rl.serveDelayError(ctx, w, r, res.delay)
 		return
 	}
 
-	time.Sleep(delay)
+	time.Sleep(res.delay)
 	rl.next.ServeHTTP(w, r)
 }
 
 func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWriter, r *http.Request, delay time.Duration) {
-	w.Header().Set("Retry-After", fmt.Sprintf("%.0f", delay.Seconds()))
+	w.Header().Set("Retry-After", strconv.FormatInt(ceilSeconds(delay), 10))
 	w.Header().Set("X-Retry-In", delay.String())
+
+	if rl.rejectHandler != nil {
+		rl.rejectHandler.ServeHTTP(&rejectResponseWriter{ResponseWriter: w}, r)
+		return
+	}
+
 	w.WriteHeader(http.StatusTooManyRequests)
 
 	if _, err := w.Write([]byte(http.StatusText(http.StatusTooManyRequests))); err != nil {
 		log.FromContext(ctx).Errorf("could not serve 429: %v", err)
 	}
 }
+
+// ceilSeconds returns the given duration in seconds, rounded up.
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// rejectResponseWriter is the response writer given to the service handling the rejected requests.
+// It enforces the 429 status code, whatever the status code of the service response.
+type rejectResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *rejectResponseWriter) WriteHeader(_ int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(http.StatusTooManyRequests)
+}
+
+func (w *rejectResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *rejectResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -57,7 +57,7 @@ func TestNewRateLimiter(t *testing.T) {
 
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
-			h, err := New(context.Background(), next, test.config, "rate-limiter")
+			h, err := New(context.Background(), next, test.config, nil, nil, "rate-limiter")
 			require.NoError(t, err)
 
 			rtl, _ := h.(*rateLimiter)
@@ -202,7 +202,7 @@ func TestRateLimit(t *testing.T) {
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				reqCount++
 			})
-			h, err := New(context.Background(), next, test.config, "rate-limiter")
+			h, err := New(context.Background(), next, test.config, nil, nil, "rate-limiter")
 			require.NoError(t, err)
 
 			loadPeriod := time.Duration(1e9 / test.incomingLoad)
@@ -272,3 +272,102 @@ func TestRateLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimit_Headers(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	config := dynamic.RateLimit{
+		Average: 1,
+		Period:  types.Duration(10 * time.Second),
+		Burst:   2,
+	}
+
+	h, err := New(context.Background(), next, config, nil, nil, "rate-limiter")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		expectedStatus    int
+		expectedRemaining string
+		expectedReset     string
+		expectedRetry     string
+	}{
+		{
+			expectedStatus:    http.StatusOK,
+			expectedRemaining: "1",
+			expectedReset:     "10",
+		},
+		{
+			expectedStatus:    http.StatusOK,
+			expectedRemaining: "0",
+			expectedReset:     "20",
+		},
+		{
+			expectedStatus:    http.StatusTooManyRequests,
+			expectedRemaining: "0",
+			expectedReset:     "20",
+			expectedRetry:     "10",
+		},
+	}
+
+	for _, test := range testCases {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		assert.Equal(t, test.expectedStatus, rw.Code)
+		assert.Equal(t, "2", rw.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, test.expectedRemaining, rw.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, test.expectedReset, rw.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, test.expectedRetry, rw.Header().Get("Retry-After"))
+	}
+}
+
+type mockServiceBuilder struct {
+	handler http.Handler
+}
+
+func (m mockServiceBuilder) BuildHTTP(_ context.Context, _ string, _ func(*http.Response) error) (http.Handler, error) {
+	return m.handler, nil
+}
+
+func TestRateLimit_Service(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("next"))
+	})
+
+	rejectHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"slow down"}`))
+	})
+
+	config := dynamic.RateLimit{
+		Average: 1,
+		Period:  types.Duration(10 * time.Second),
+		Burst:   1,
+		Service: "rejected",
+	}
+
+	h, err := New(context.Background(), next, config, mockServiceBuilder{handler: rejectHandler}, nil, "rate-limiter")
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "next", rw.Body.String())
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, `{"error":"slow down"}`, rw.Body.String())
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	assert.Equal(t, "10", rw.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rw.Header().Get("RateLimit-Remaining"))
+}
@@ -0,0 +1,234 @@
+package ratelimiter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"gopkg.in/redis.v5"
+)
+
+const (
+	redisKeyPrefix      = "traefik:ratelimiter:"
+	defaultRedisTimeout = 500 * time.Millisecond
+	// redisRetryInterval is the duration during which the local token buckets are used,
+	// after the Redis server failed to answer.
+	redisRetryInterval = 5 * time.Second
+)
+
+// reserveScript implements, for the bucket stored at KEYS[1], the same token bucket algorithm as tokenBucket.
+// It takes a token if one is available within the maximum delay,
+// and returns the delay (in microseconds) and the number of tokens left in the bucket.
+// ARGV holds the rate (in tokens/s), the burst, the current time and the maximum delay (in microseconds),
+// and the TTL of the bucket (in milliseconds).
+// The current time is given by the caller, so the clocks of the Traefik instances sharing the buckets should be synchronized.
+var reserveScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local max_delay = tonumber(ARGV[4])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(bucket[1])
+local last = bucket[2]
+
+if tokens == nil or last == nil then
+	tokens = burst
+	last = ARGV[3]
+end
+
+if now > tonumber(last) then
+	tokens = math.min(burst, tokens + (now - tonumber(last)) * rate / 1000000)
+	last = ARGV[3]
+end
+
+tokens = tokens - 1
+
+local delay = 0
+if tokens < 0 then
+	delay = math.ceil(-tokens * 1000000 / rate)
+end
+
+if delay <= max_delay then
+	redis.call("HMSET", KEYS[1], "tokens", string.format("%.6f", tokens), "last", last)
+	redis.call("PEXPIRE", KEYS[1], ARGV[5])
+else
+	tokens = tokens + 1
+end
+
+return {delay, string.format("%.6f", tokens)}
+`)
+
+// RedisClients holds the Redis clients used by the rate limiters, keyed by their configuration,
+// so that the connections are shared by the rate limiters and kept across configuration reloads.
+type RedisClients struct {
+	mu      sync.Mutex
+	clients map[string]*redisClient
+}
+
+// redisClient is a Redis client, with whether it is used by the configuration being built.
+type redisClient struct {
+	client *redis.Client
+	used   bool
+}
+
+// NewRedisClients creates a new RedisClients.
+func NewRedisClients() *RedisClients {
+	return &RedisClients{clients: make(map[string]*redisClient)}
+}
+
+// redisLimiter keeps the token buckets in a Redis server,
+// and falls back to local buckets when the server cannot be reached.
+type redisLimiter struct {
+	name     string
+	client   *redis.Client
+	rate     float64 // reqs/s
+	burst    int64
+	maxDelay time.Duration
+	ttl      time.Duration
+
+	fallback limiter
+	// unavailableUntil is the time, in Unix nanoseconds, until which the fallback is used.
+	unavailableUntil int64
+}
+
+func newRedisLimiter(name string, client *redis.Client, rtl float64, burst int64, maxDelay time.Duration, fallback limiter) *redisLimiter {
+	// The bucket is full again, and can therefore be forgotten, after burst/rate seconds.
+	ttl := time.Duration(float64(burst)/rtl*float64(time.Second)) + maxDelay
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	return &redisLimiter{
+		name:     name,
+		client:   client,
+		rate:     rtl,
+		burst:    burst,
+		maxDelay: maxDelay,
+		ttl:      ttl,
+		fallback: fallback,
+	}
+}
+
+func (r *redisLimiter) reserve(ctx context.Context, source string) (reservation, error) {
+	if time.Now().UnixNano() < atomic.LoadInt64(&r.unavailableUntil) {
+		return r.fallback.reserve(ctx, source)
+	}
+
+	res, err := reserveScript.Run(r.client, []string{redisKeyPrefix + r.name + ":" + source},
+		strconv.FormatFloat(r.rate, 'f', -1, 64),
+		r.burst,
+		time.Now().UnixNano()/int64(time.Microsecond),
+		r.maxDelay.Microseconds(),
+		r.ttl.Milliseconds(),
+	).Result()
+	if err != nil {
+		log.FromContext(ctx).Errorf("Unable to reach the Redis server, using local rate limiting for %s: %v", redisRetryInterval, err)
+		atomic.StoreInt64(&r.unavailableUntil, time.Now().Add(redisRetryInterval).UnixNano())
+		return r.fallback.reserve(ctx, source)
+	}
+
+	delay, tokens, err := parseReserveReply(res)
+	if err != nil {
+		return reservation{}, err
+	}
+
+	return newReservation(time.Duration(delay)*time.Microsecond, tokens, r.rate, r.burst), nil
+}
+
+func parseReserveReply(res interface{}) (int64, float64, error) {
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, 0, fmt.Errorf("unexpected reply from the Redis server: %v", res)
+	}
+
+	delay, ok := values[0].(int64)
+	if !ok {
+		return 0, 0, fmt.Errorf("unexpected delay in the reply from the Redis server: %v", values[0])
+	}
+
+	rawTokens, ok := values[1].(string)
+	if !ok {
+		return 0, 0, fmt.Errorf("unexpected tokens in the reply from the Redis server: %v", values[1])
+	}
+
+	tokens, err := strconv.ParseFloat(rawTokens, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected tokens in the reply from the Redis server: %w", err)
+	}
+
+	return delay, tokens, nil
+}
+
+// get returns the Redis client for the given configuration, and marks it as used by the configuration being built.
+func (c *RedisClients) get(config *dynamic.RateLimitRedis) (*redis.Client, error) {
+	if len(config.Endpoints) != 1 {
+		return nil, errors.New("exactly one Redis endpoint must be defined")
+	}
+
+	rawKey, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	key := string(rawKey)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if shared, ok := c.clients[key]; ok {
+		shared.used = true
+		return shared.client, nil
+	}
+
+	tlsConfig, err := config.TLS.CreateTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the Redis TLS configuration: %w", err)
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		timeout = defaultRedisTimeout
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         config.Endpoints[0],
+		Password:     config.Password,
+		DB:           config.DB,
+		TLSConfig:    tlsConfig,
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	})
+
+	c.clients[key] = &redisClient{client: client, used: true}
+
+	return client, nil
+}
+
+// CloseUnused closes the Redis clients which have not been used since the previous call,
+// i.e. which are not used by the configuration built in between.
+// It is meant to be called once this configuration is applied.
+func (c *RedisClients) CloseUnused() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, shared := range c.clients {
+		if shared.used {
+			shared.used = false
+			continue
+		}
+
+		delete(c.clients, key)
+
+		if err := shared.client.Close(); err != nil {
+			log.WithoutContext().Debugf("Error while closing the Redis client: %v", err)
+		}
+	}
+}
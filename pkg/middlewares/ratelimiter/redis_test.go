@@ -0,0 +1,184 @@
+package ratelimiter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit_Redis(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+
+	config := dynamic.RateLimit{
+		Average: 1,
+		Period:  types.Duration(time.Minute),
+		Burst:   3,
+		Redis: &dynamic.RateLimitRedis{
+			Endpoints: []string{server.Addr()},
+		},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	// Two rate limiters with the same name, and their own Redis clients, stand for the same middleware in two Traefik instances.
+	h1, err := New(context.Background(), next, config, nil, NewRedisClients(), "rate-limiter")
+	require.NoError(t, err)
+	h2, err := New(context.Background(), next, config, nil, NewRedisClients(), "rate-limiter")
+	require.NoError(t, err)
+
+	var codes []int
+	var remaining []string
+	for _, h := range []http.Handler{h1, h2, h1, h2, h1} {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		codes = append(codes, rw.Code)
+		remaining = append(remaining, rw.Header().Get("RateLimit-Remaining"))
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}, codes)
+	assert.Equal(t, []string{"2", "1", "0", "0", "0"}, remaining)
+
+	// Another source has its own bucket.
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+
+	rw := httptest.NewRecorder()
+	h2.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	assert.True(t, server.Exists(redisKeyPrefix+"rate-limiter:10.0.0.1"))
+	assert.True(t, server.Exists(redisKeyPrefix+"rate-limiter:10.0.0.2"))
+}
+
+func TestRateLimit_RedisFallback(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+
+	config := dynamic.RateLimit{
+		Average: 1,
+		Period:  types.Duration(time.Minute),
+		Burst:   2,
+		Redis: &dynamic.RateLimitRedis{
+			Endpoints: []string{server.Addr()},
+			Timeout:   types.Duration(100 * time.Millisecond),
+		},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	h, err := New(context.Background(), next, config, nil, NewRedisClients(), "rate-limiter-fallback")
+	require.NoError(t, err)
+
+	// The Redis server is unreachable, so the local buckets are used.
+	server.Close()
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		codes = append(codes, rw.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestNewRateLimiter_RedisEndpoints(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		endpoints   []string
+		expectError bool
+	}{
+		{
+			desc:        "no endpoint",
+			expectError: true,
+		},
+		{
+			desc:      "one endpoint",
+			endpoints: []string{"127.0.0.1:6379"},
+		},
+		{
+			desc:        "several endpoints",
+			endpoints:   []string{"127.0.0.1:6379", "127.0.0.1:6380"},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.RateLimit{
+				Average: 100,
+				Burst:   1,
+				Redis: &dynamic.RateLimitRedis{
+					Endpoints: test.endpoints,
+				},
+			}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+			_, err := New(context.Background(), next, config, nil, NewRedisClients(), "rate-limiter")
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRedisClients_CloseUnused(t *testing.T) {
+	configA := &dynamic.RateLimitRedis{Endpoints: []string{"127.0.0.1:1"}}
+	configB := &dynamic.RateLimitRedis{Endpoints: []string{"127.0.0.1:2"}}
+
+	redisClients := NewRedisClients()
+
+	// First configuration.
+	clientA, err := redisClients.get(configA)
+	require.NoError(t, err)
+	clientA2, err := redisClients.get(configA)
+	require.NoError(t, err)
+	assert.Same(t, clientA, clientA2)
+
+	redisClients.CloseUnused()
+	assert.Len(t, redisClients.clients, 1)
+
+	// Second configuration, still using configA.
+	clientA3, err := redisClients.get(configA)
+	require.NoError(t, err)
+	assert.Same(t, clientA, clientA3)
+
+	clientB, err := redisClients.get(configB)
+	require.NoError(t, err)
+
+	redisClients.CloseUnused()
+	assert.Len(t, redisClients.clients, 2)
+
+	// Third configuration, not using configA anymore.
+	_, err = redisClients.get(configB)
+	require.NoError(t, err)
+
+	redisClients.CloseUnused()
+	assert.Len(t, redisClients.clients, 1)
+
+	assert.EqualError(t, clientA.Ping().Err(), "redis: client is closed")
+	assert.NotEqual(t, "redis: client is closed", clientB.Ping().Err().Error())
+}
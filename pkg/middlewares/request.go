@@ -0,0 +1,18 @@
+package middlewares
+
+import "net/http"
+
+// IsIdempotent reports whether the given method is idempotent, as defined by RFC 7231.
+func IsIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasBody reports whether the given request has a body, which could not be sent again.
+func HasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0
+}
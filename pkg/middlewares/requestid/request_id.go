@@ -0,0 +1,135 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/ip"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "RequestID"
+
+	defaultHeaderName = "X-Request-ID"
+
+	// maxIDLength is the maximum length of a request ID received from a trusted IP.
+	maxIDLength = 200
+
+	spanTagRequestID = "request.id"
+)
+
+// requestID is a middleware which identifies each request with an ID,
+// so that the logs and traces of Traefik and of the services can be correlated.
+type requestID struct {
+	next       http.Handler
+	name       string
+	headerName string
+	trustedIPs *ip.Checker
+}
+
+// New creates a new request ID middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RequestID, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	headerName := defaultHeaderName
+	if config.HeaderName != "" {
+		headerName = http.CanonicalHeaderKey(config.HeaderName)
+	}
+
+	var trustedIPs *ip.Checker
+	if len(config.TrustedIPs) > 0 {
+		checker, err := ip.NewChecker(config.TrustedIPs)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse trusted IPs %s: %w", config.TrustedIPs, err)
+		}
+		trustedIPs = checker
+	}
+
+	return &requestID{
+		next:       next,
+		name:       name,
+		headerName: headerName,
+		trustedIPs: trustedIPs,
+	}, nil
+}
+
+func (r *requestID) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *requestID) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName))
+
+	id := req.Header.Get(r.headerName)
+	if id != "" && !r.isTrusted(req) {
+		logger.Debugf("Replacing the request ID %q sent by the untrusted address %s", id, req.RemoteAddr)
+		id = ""
+	}
+
+	if id == "" {
+		var err error
+		id, err = generateID()
+		if err != nil {
+			logMessage := fmt.Sprintf("Error generating the request ID: %v", err)
+			logger.Error(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	req.Header.Set(r.headerName, id)
+	rw.Header().Set(r.headerName, id)
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.RequestID] = id
+	}
+
+	if span := tracing.GetSpan(req); span != nil {
+		span.SetTag(spanTagRequestID, id)
+	}
+
+	r.next.ServeHTTP(rw, req)
+}
+
+// isTrusted reports whether the request ID of the request can be kept.
+func (r *requestID) isTrusted(req *http.Request) bool {
+	if r.trustedIPs == nil || r.trustedIPs.IsAuthorized(req.RemoteAddr) != nil {
+		return false
+	}
+
+	id := req.Header.Get(r.headerName)
+	if len(id) > maxIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generateID returns a random (version 4) UUID.
+func generateID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,160 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.RequestID
+		remoteAddr    string
+		requestHeader http.Header
+		expectedID    string
+	}{
+		{
+			desc:       "generated ID",
+			remoteAddr: "10.0.0.1:1234",
+		},
+		{
+			desc:          "untrusted ID",
+			remoteAddr:    "10.0.0.1:1234",
+			requestHeader: http.Header{"X-Request-Id": {"foo"}},
+		},
+		{
+			desc: "ID from an untrusted IP",
+			config: dynamic.RequestID{
+				TrustedIPs: []string{"192.168.0.0/16"},
+			},
+			remoteAddr:    "10.0.0.1:1234",
+			requestHeader: http.Header{"X-Request-Id": {"foo"}},
+		},
+		{
+			desc: "ID from a trusted IP",
+			config: dynamic.RequestID{
+				TrustedIPs: []string{"192.168.0.0/16"},
+			},
+			remoteAddr:    "192.168.1.1:1234",
+			requestHeader: http.Header{"X-Request-Id": {"foo"}},
+			expectedID:    "foo",
+		},
+		{
+			desc: "invalid ID from a trusted IP",
+			config: dynamic.RequestID{
+				TrustedIPs: []string{"192.168.0.0/16"},
+			},
+			remoteAddr:    "192.168.1.1:1234",
+			requestHeader: http.Header{"X-Request-Id": {"foo\nbar"}},
+		},
+		{
+			desc: "too long ID from a trusted IP",
+			config: dynamic.RequestID{
+				TrustedIPs: []string{"192.168.0.0/16"},
+			},
+			remoteAddr:    "192.168.1.1:1234",
+			requestHeader: http.Header{"X-Request-Id": {strings.Repeat("a", maxIDLength+1)}},
+		},
+		{
+			desc: "custom header name",
+			config: dynamic.RequestID{
+				HeaderName: "x-correlation-id",
+				TrustedIPs: []string{"192.168.1.1"},
+			},
+			remoteAddr:    "192.168.1.1:1234",
+			requestHeader: http.Header{"X-Correlation-Id": {"foo"}},
+			expectedID:    "foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			headerName := test.config.HeaderName
+			if headerName == "" {
+				headerName = defaultHeaderName
+			}
+
+			var forwardedID string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwardedID = req.Header.Get(headerName)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "requestid")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.RemoteAddr = test.remoteAddr
+			for name, values := range test.requestHeader {
+				req.Header[name] = values
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			if test.expectedID != "" {
+				assert.Equal(t, test.expectedID, forwardedID)
+			} else {
+				assert.Regexp(t, uuidRegexp, forwardedID)
+			}
+			assert.Equal(t, forwardedID, rw.Header().Get(headerName))
+		})
+	}
+}
+
+func TestRequestIDUnique(t *testing.T) {
+	handler, err := New(context.Background(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), dynamic.RequestID{}, "requestid")
+	require.NoError(t, err)
+
+	ids := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+		ids[rw.Header().Get(defaultHeaderName)] = struct{}{}
+	}
+
+	assert.Len(t, ids, 100)
+}
+
+func TestRequestIDCorrelation(t *testing.T) {
+	handler, err := New(context.Background(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), dynamic.RequestID{}, "requestid")
+	require.NoError(t, err)
+
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("test")
+	logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+	req = req.WithContext(opentracing.ContextWithSpan(req.Context(), span))
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	id := rw.Header().Get(defaultHeaderName)
+	require.NotEmpty(t, id)
+
+	assert.Equal(t, id, logData.Core[accesslog.RequestID])
+	assert.Equal(t, id, span.(*mocktracer.MockSpan).Tag(spanTagRequestID))
+}
+
+func TestNewWithInvalidTrustedIPs(t *testing.T) {
+	_, err := New(context.Background(), nil, dynamic.RequestID{TrustedIPs: []string{"foo"}}, "requestid")
+	assert.Error(t, err)
+}
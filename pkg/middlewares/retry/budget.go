@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// budgetWindow is the number of seconds over which the requests and retries are counted.
+const budgetWindow = 10
+
+// budget limits the retries to a percentage of the requests received over the last budgetWindow seconds.
+type budget struct {
+	percent    int
+	minRetries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets [budgetWindow]budgetBucket
+}
+
+// budgetBucket holds the counts of one second of the window.
+type budgetBucket struct {
+	second   int64
+	requests int
+	retries  int
+}
+
+func newBudget(percent, minRetries int) *budget {
+	return &budget{
+		percent:    percent,
+		minRetries: minRetries,
+		now:        time.Now,
+	}
+}
+
+// addRequest records a new request.
+func (b *budget) addRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bucket().requests++
+}
+
+// addRetry records a new retry.
+func (b *budget) addRetry() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bucket().retries++
+}
+
+// canRetry reports whether one more retry would stay within the budget.
+func (b *budget) canRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now().Unix()
+
+	var requests, retries int
+	for _, bucket := range b.buckets {
+		if now-bucket.second < budgetWindow {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+
+	return retries < b.minRetries || retries*100 < requests*b.percent
+}
+
+// bucket returns the bucket of the current second, reset if it was holding the counts of a previous window.
+func (b *budget) bucket() *budgetBucket {
+	now := b.now().Unix()
+
+	bucket := &b.buckets[now%budgetWindow]
+	if bucket.second != now {
+		*bucket = budgetBucket{second: now}
+	}
+
+	return bucket
+}
@@ -3,16 +3,20 @@ package retry
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptrace"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
 	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/opentracing/opentracing-go/ext"
 )
 
@@ -36,10 +40,14 @@ type Listeners []Listener
 
 // retry is a middleware that retries requests.
 type retry struct {
-	attempts int
-	next     http.Handler
-	listener Listener
-	name     string
+	attempts        int
+	initialInterval time.Duration
+	statusCodes     types.HTTPCodeRanges
+	perTryTimeout   time.Duration
+	budget          *budget // can be nil
+	next            http.Handler
+	listener        Listener
+	name            string
 }
 
 // New returns a new retry middleware.
@@ -50,11 +58,37 @@ func New(ctx context.Context, next http.Handler, config dynamic.Retry, listener
 		return nil, fmt.Errorf("incorrect (or empty) value for attempt (%d)", config.Attempts)
 	}
 
+	if config.InitialInterval < 0 {
+		return nil, fmt.Errorf("incorrect value for initial interval (%s)", time.Duration(config.InitialInterval))
+	}
+
+	if config.PerTryTimeout < 0 {
+		return nil, fmt.Errorf("incorrect value for per-try timeout (%s)", time.Duration(config.PerTryTimeout))
+	}
+
+	statusCodes, err := types.NewHTTPCodeRanges(config.Status)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect value for status: %w", err)
+	}
+
+	var retryBudget *budget
+	if config.Budget != nil {
+		if config.Budget.Percent < 0 || config.Budget.Percent > 100 {
+			return nil, fmt.Errorf("incorrect value for budget percent (%d), must be between 0 and 100", config.Budget.Percent)
+		}
+
+		retryBudget = newBudget(config.Budget.Percent, config.Budget.MinRetries)
+	}
+
 	return &retry{
-		attempts: config.Attempts,
-		next:     next,
-		listener: listener,
-		name:     name,
+		attempts:        config.Attempts,
+		initialInterval: time.Duration(config.InitialInterval),
+		statusCodes:     statusCodes,
+		perTryTimeout:   time.Duration(config.PerTryTimeout),
+		budget:          retryBudget,
+		next:            next,
+		listener:        listener,
+		name:            name,
 	}, nil
 }
 
@@ -63,6 +97,10 @@ func (r *retry) GetTracingInformation() (string, ext.SpanKindEnum) {
 }
 
 func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// Only the requests which can be safely sent again once the server has received them
+	// are retried on the response status, or when the per-try timeout is exceeded.
+	replayable := middlewares.IsIdempotent(req.Method) && !middlewares.HasBody(req)
+
 	// if we might make multiple attempts, swap the body for an ioutil.NopCloser
 	// cf https://github.com/containous/traefik/issues/1008
 	if r.attempts > 1 {
@@ -71,10 +109,34 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		req.Body = ioutil.NopCloser(body)
 	}
 
+	if r.budget != nil {
+		r.budget.addRequest()
+	}
+
+	var backOff backoff.BackOff
+
 	attempts := 1
 	for {
-		shouldRetry := attempts < r.attempts
-		retryResponseWriter := newResponseWriter(rw, shouldRetry)
+		shouldRetry := attempts < r.attempts && (r.budget == nil || r.budget.canRetry())
+
+		attemptCtx, cancel := req.Context(), func() {}
+		if r.perTryTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(req.Context(), r.perTryTimeout)
+		}
+
+		var retryableStatus func(code int) bool
+		if shouldRetry && replayable {
+			retryableStatus = func(code int) bool {
+				if r.statusCodes.Contains(code) {
+					return true
+				}
+
+				// The attempt timed out, but not the request itself.
+				return code == http.StatusGatewayTimeout && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && req.Context().Err() == nil
+			}
+		}
+
+		retryResponseWriter := newResponseWriter(rw, shouldRetry, retryableStatus)
 
 		// Disable retries when the backend already received request data
 		trace := &httptrace.ClientTrace{
@@ -85,9 +147,10 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 				retryResponseWriter.DisableRetries()
 			},
 		}
-		newCtx := httptrace.WithClientTrace(req.Context(), trace)
+		newCtx := httptrace.WithClientTrace(attemptCtx, trace)
 
 		r.next.ServeHTTP(retryResponseWriter, req.WithContext(newCtx))
+		cancel()
 
 		if !retryResponseWriter.ShouldRetry() {
 			break
@@ -95,6 +158,22 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 		attempts++
 
+		if r.budget != nil {
+			r.budget.addRetry()
+		}
+
+		if r.initialInterval > 0 {
+			if backOff == nil {
+				backOff = r.newBackOff()
+			}
+
+			if !wait(req.Context(), backOff.NextBackOff()) {
+				log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName)).
+					Debugf("Request canceled while waiting for attempt %d: %v", attempts, req.URL)
+				return
+			}
+		}
+
 		log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName)).
 			Debugf("New attempt %d for request: %v", attempts, req.URL)
 
@@ -102,6 +181,31 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// newBackOff returns the exponential backoff between the attempts of a request.
+func (r *retry) newBackOff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = r.initialInterval
+	b.Multiplier = 2
+	// The number of attempts is limited, and so is the elapsed time.
+	b.MaxElapsedTime = 0
+	b.Reset()
+
+	return b
+}
+
+// wait waits for the given duration, and reports whether it could, i.e. whether the context was not done before.
+func wait(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // Retried exists to implement the Listener interface. It calls Retried on each of its slice entries.
 func (l Listeners) Retried(req *http.Request, attempt int) {
 	for _, listener := range l {
@@ -116,11 +220,14 @@ type responseWriter interface {
 	DisableRetries()
 }
 
-func newResponseWriter(rw http.ResponseWriter, shouldRetry bool) responseWriter {
+// newResponseWriter returns a responseWriter discarding the response as long as the request should be retried.
+// retryableStatus, if not nil, reports whether a response status received from the server should trigger a retry.
+func newResponseWriter(rw http.ResponseWriter, shouldRetry bool, retryableStatus func(code int) bool) responseWriter {
 	responseWriter := &responseWriterWithoutCloseNotify{
-		responseWriter: rw,
-		headers:        make(http.Header),
-		shouldRetry:    shouldRetry,
+		responseWriter:  rw,
+		headers:         make(http.Header),
+		shouldRetry:     shouldRetry,
+		retryableStatus: retryableStatus,
 	}
 	if _, ok := rw.(http.CloseNotifier); ok {
 		return &responseWriterWithCloseNotify{
@@ -131,14 +238,16 @@ func newResponseWriter(rw http.ResponseWriter, shouldRetry bool) responseWriter
 }
 
 type responseWriterWithoutCloseNotify struct {
-	responseWriter http.ResponseWriter
-	headers        http.Header
-	shouldRetry    bool
-	written        bool
+	responseWriter  http.ResponseWriter
+	headers         http.Header
+	shouldRetry     bool
+	retryableStatus func(code int) bool
+	statusRetry     bool
+	written         bool
 }
 
 func (r *responseWriterWithoutCloseNotify) ShouldRetry() bool {
-	return r.shouldRetry
+	return r.shouldRetry || r.statusRetry
 }
 
 func (r *responseWriterWithoutCloseNotify) DisableRetries() {
@@ -167,6 +276,12 @@ func (r *responseWriterWithoutCloseNotify) WriteHeader(code int) {
 		// the backend server and so we can be sure that the 503 was produced
 		// inside Traefik already and we don't have to retry in this cases.
 		r.DisableRetries()
+		r.retryableStatus = nil
+	}
+
+	if !r.shouldRetry && r.retryableStatus != nil && r.retryableStatus(code) {
+		// The server answered, but with a status to retry: the response is discarded.
+		r.statusRetry = true
 	}
 
 	if r.ShouldRetry() {
@@ -195,6 +310,10 @@ func (r *responseWriterWithoutCloseNotify) Hijack() (net.Conn, *bufio.ReadWriter
 }
 
 func (r *responseWriterWithoutCloseNotify) Flush() {
+	if r.ShouldRetry() {
+		return
+	}
+
 	if flusher, ok := r.responseWriter.(http.Flusher); ok {
 		flusher.Flush()
 	}
@@ -3,16 +3,20 @@ package retry
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/middlewares/emptybackendhandler"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -313,3 +317,240 @@ func TestRetryWebsocket(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryOnStatus(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.Retry
+		method             string
+		body               string
+		statusSequence     []int
+		wantRetryAttempts  int
+		wantResponseStatus int
+	}{
+		{
+			desc:               "retry until success",
+			config:             dynamic.Retry{Attempts: 3, Status: []string{"502-503"}},
+			method:             http.MethodGet,
+			statusSequence:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantRetryAttempts:  2,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "status not to retry",
+			config:             dynamic.Retry{Attempts: 3, Status: []string{"502-503"}},
+			method:             http.MethodGet,
+			statusSequence:     []int{http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:               "max attempts exhausted delivers the last response",
+			config:             dynamic.Retry{Attempts: 2, Status: []string{"503"}},
+			method:             http.MethodGet,
+			statusSequence:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			wantRetryAttempts:  1,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "non idempotent method",
+			config:             dynamic.Retry{Attempts: 3, Status: []string{"503"}},
+			method:             http.MethodPost,
+			statusSequence:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "request with a body",
+			config:             dynamic.Retry{Attempts: 3, Status: []string{"503"}},
+			method:             http.MethodPut,
+			body:               "foo",
+			statusSequence:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int32
+			backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				n := atomic.AddInt32(&requests, 1)
+				rw.Header().Set("X-Attempt", strconv.Itoa(int(n)))
+				rw.WriteHeader(test.statusSequence[n-1])
+				_, _ = rw.Write([]byte(strconv.Itoa(int(n))))
+			}))
+			defer backendServer.Close()
+
+			retryListener := &countingRetryListener{}
+			retry, err := New(context.Background(), newForwarder(t, backendServer.URL), test.config, retryListener, "traefikTest")
+			require.NoError(t, err)
+
+			var body io.Reader
+			if test.body != "" {
+				body = strings.NewReader(test.body)
+			}
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, "http://localhost:3000/ok", body)
+
+			retry.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.wantResponseStatus, recorder.Code)
+			assert.Equal(t, test.wantRetryAttempts, retryListener.timesCalled)
+
+			// Only the response of the last attempt is sent.
+			attempt := strconv.Itoa(test.wantRetryAttempts + 1)
+			assert.Equal(t, attempt, recorder.Header().Get("X-Attempt"))
+			assert.Equal(t, attempt, recorder.Body.String())
+		})
+	}
+}
+
+func TestRetryPerTryTimeout(t *testing.T) {
+	var requests int32
+	backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backendServer.Close()
+
+	config := dynamic.Retry{Attempts: 2, PerTryTimeout: types.Duration(50 * time.Millisecond)}
+
+	retryListener := &countingRetryListener{}
+	retry, err := New(context.Background(), newForwarder(t, backendServer.URL), config, retryListener, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 1, retryListener.timesCalled)
+}
+
+func TestRetryBackOff(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer backendServer.Close()
+
+	config := dynamic.Retry{Attempts: 3, Status: []string{"503"}, InitialInterval: types.Duration(50 * time.Millisecond)}
+
+	retryListener := &countingRetryListener{}
+	retry, err := New(context.Background(), newForwarder(t, backendServer.URL), config, retryListener, "traefikTest")
+	require.NoError(t, err)
+
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, 2, retryListener.timesCalled)
+	// The first wait is at least 25ms, and the second one at least 50ms, with the jitter.
+	assert.True(t, time.Since(start) >= 75*time.Millisecond)
+}
+
+func TestRetryBudget(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer backendServer.Close()
+
+	config := dynamic.Retry{Attempts: 3, Status: []string{"503"}, Budget: &dynamic.RetryBudget{Percent: 50, MinRetries: 1}}
+
+	retryListener := &countingRetryListener{}
+	retry, err := New(context.Background(), newForwarder(t, backendServer.URL), config, retryListener, "traefikTest")
+	require.NoError(t, err)
+
+	// The first retry is allowed by minRetries, the second one would exceed the budget (2 retries for 1 request).
+	retry.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+	assert.Equal(t, 1, retryListener.timesCalled)
+
+	// 1 retry for 2 requests, one more retry would exceed the budget.
+	retry.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+	assert.Equal(t, 1, retryListener.timesCalled)
+
+	// 1 retry for 3 requests, one more retry is allowed.
+	retry.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+	assert.Equal(t, 2, retryListener.timesCalled)
+}
+
+func TestBudgetWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	b := newBudget(10, 0)
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		b.addRequest()
+	}
+	assert.True(t, b.canRetry())
+
+	b.addRetry()
+	assert.False(t, b.canRetry())
+
+	// The counts are kept for the duration of the window.
+	now = now.Add((budgetWindow - 1) * time.Second)
+	assert.False(t, b.canRetry())
+
+	// Then they are forgotten, and without any request no retry is allowed.
+	now = now.Add(time.Second)
+	assert.False(t, b.canRetry())
+
+	b.addRequest()
+	assert.True(t, b.canRetry())
+}
+
+func TestNewRetryInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.Retry
+	}{
+		{
+			desc:   "no attempts",
+			config: dynamic.Retry{},
+		},
+		{
+			desc:   "invalid status",
+			config: dynamic.Retry{Attempts: 2, Status: []string{"foo"}},
+		},
+		{
+			desc:   "negative initial interval",
+			config: dynamic.Retry{Attempts: 2, InitialInterval: types.Duration(-time.Second)},
+		},
+		{
+			desc:   "budget percent out of range",
+			config: dynamic.Retry{Attempts: 2, Budget: &dynamic.RetryBudget{Percent: 101}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, &countingRetryListener{}, "traefikTest")
+			assert.Error(t, err)
+		})
+	}
+}
+
+// newForwarder returns a handler forwarding the requests to the given server.
+func newForwarder(t *testing.T, serverURL string) http.Handler {
+	t.Helper()
+
+	forwarder, err := forward.New()
+	require.NoError(t, err)
+
+	loadBalancer, err := roundrobin.New(forwarder)
+	require.NoError(t, err)
+
+	require.NoError(t, loadBalancer.UpsertServer(testhelpers.MustParseURL(serverURL)))
+
+	return loadBalancer
+}
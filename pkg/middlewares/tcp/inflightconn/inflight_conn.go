@@ -0,0 +1,89 @@
+package inflightconn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tcp"
+)
+
+const typeName = "InFlightConnTCP"
+
+// inFlightConn limits the number of connections being served concurrently, per source IP.
+type inFlightConn struct {
+	name           string
+	next           tcp.Handler
+	maxConnections int64
+
+	mu          sync.Mutex
+	connections map[string]int64 // current number of connections by remote IP.
+}
+
+// New creates a max connections middleware.
+// The connections are identified and grouped by remote IP.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPInFlightConn, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if config.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be greater than 0, got %d", config.Amount)
+	}
+
+	return &inFlightConn{
+		name:           name,
+		next:           next,
+		connections:    make(map[string]int64),
+		maxConnections: config.Amount,
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (i *inFlightConn) ServeTCP(conn tcp.WriteCloser) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(context.Background(), i.name, typeName))
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Errorf("Cannot parse IP from remote addr: %v", err)
+		_ = conn.Close()
+		return
+	}
+
+	if err = i.increment(ip); err != nil {
+		logger.Debugf("Connection from %s rejected: %v", ip, err)
+		_ = conn.Close()
+		return
+	}
+	defer i.decrement(ip)
+
+	i.next.ServeTCP(conn)
+}
+
+// increment increases the counter for the number of connections with the given IP,
+// if it is not over the limit yet.
+func (i *inFlightConn) increment(ip string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.connections[ip] >= i.maxConnections {
+		return fmt.Errorf("max number of connections reached for %s", ip)
+	}
+
+	i.connections[ip]++
+	return nil
+}
+
+// decrement decreases the counter for the number of connections with the given IP.
+func (i *inFlightConn) decrement(ip string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.connections[ip]--
+	if i.connections[ip] <= 0 {
+		delete(i.connections, ip)
+	}
+}
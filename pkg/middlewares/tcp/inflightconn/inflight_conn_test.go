@@ -0,0 +1,92 @@
+package inflightconn
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightConn_ServeTCP(t *testing.T) {
+	proceedCh := make(chan bool)
+	waitCh := make(chan bool)
+	finishCh := make(chan bool)
+
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		proceedCh <- true
+
+		if fc, ok := conn.(*fakeConn); ok && fc.wait {
+			<-waitCh
+		}
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPInFlightConn{Amount: 1}, "foo")
+	require.NoError(t, err)
+
+	// The first connection should succeed and wait.
+	go func() {
+		middleware.ServeTCP(&fakeConn{addr: "127.0.0.1:9000", wait: true})
+		finishCh <- true
+	}()
+	requireMessage(t, proceedCh)
+
+	// The second connection from the same IP should be closed as we are over the limit.
+	closeCh := make(chan bool)
+	go middleware.ServeTCP(&fakeConn{addr: "127.0.0.1:9001", closeCh: closeCh})
+	requireMessage(t, closeCh)
+
+	// The connection from another IP should succeed.
+	go middleware.ServeTCP(&fakeConn{addr: "127.0.0.2:9000"})
+	requireMessage(t, proceedCh)
+
+	// Once the first connection is closed, next connection will succeed.
+	close(waitCh)
+	requireMessage(t, finishCh)
+
+	go middleware.ServeTCP(&fakeConn{addr: "127.0.0.1:9000"})
+	requireMessage(t, proceedCh)
+}
+
+func TestNew(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+	_, err := New(context.Background(), next, dynamic.TCPInFlightConn{}, "foo")
+	assert.Error(t, err)
+}
+
+type fakeConn struct {
+	net.Conn
+	addr    string
+	wait    bool
+	closeCh chan bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", c.addr)
+	return addr
+}
+
+func (c *fakeConn) Close() error {
+	if c.closeCh != nil {
+		c.closeCh <- true
+	}
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	return nil
+}
+
+func requireMessage(t *testing.T, c chan bool) {
+	t.Helper()
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for message")
+	}
+}
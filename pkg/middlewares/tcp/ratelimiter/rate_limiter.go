@@ -0,0 +1,107 @@
+// Package ratelimiter implements a TCP middleware limiting the rate of new connections, with a token bucket per source IP.
+package ratelimiter
+
+import (
+	"context"
+	"math"
+	"net"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/mailgun/ttlmap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName   = "RateLimiterTCP"
+	maxSources = 65536
+)
+
+// rateLimiter limits the rate of new connections with a set of token buckets;
+// one for each source IP. The same parameters are applied to all the buckets.
+type rateLimiter struct {
+	name  string
+	rate  rate.Limit // conns/s
+	burst int
+	// ttl is the number of seconds after which an unused bucket is full again, and can be forgotten.
+	ttl  int
+	next tcp.Handler
+
+	buckets *ttlmap.TtlMap // actual buckets, keyed by source IP.
+}
+
+// New returns a TCP rate limiter middleware.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPRateLimit, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if config.Average <= 0 {
+		logger.Debug("No rate limiting, as the average is not positive")
+		return next, nil
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	period := time.Duration(config.Period)
+	if period <= 0 {
+		period = time.Second
+	}
+
+	buckets, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, err
+	}
+
+	rtl := float64(config.Average*int64(time.Second)) / float64(period)
+
+	logger.Debugf("Rate limiting to %f connections/s with a burst of %d", rtl, burst)
+
+	return &rateLimiter{
+		name:    name,
+		rate:    rate.Limit(rtl),
+		burst:   int(burst),
+		ttl:     int(math.Ceil(float64(burst)/rtl)) + 1,
+		next:    next,
+		buckets: buckets,
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection, or closes it if the rate of connections of its source IP is over the limit.
+func (rl *rateLimiter) ServeTCP(conn tcp.WriteCloser) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(context.Background(), rl.name, typeName))
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Errorf("Cannot parse IP from remote addr: %v", err)
+		_ = conn.Close()
+		return
+	}
+
+	var bucket *rate.Limiter
+	if rlSource, exists := rl.buckets.Get(ip); exists {
+		bucket = rlSource.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(rl.rate, rl.burst)
+	}
+
+	// Refreshes the TTL of the bucket.
+	if err := rl.buckets.Set(ip, bucket, rl.ttl); err != nil {
+		logger.Errorf("Could not insert/update bucket: %v", err)
+		_ = conn.Close()
+		return
+	}
+
+	if !bucket.Allow() {
+		logger.Debugf("Connection from %s rejected: rate limit exceeded", ip)
+		_ = conn.Close()
+		return
+	}
+
+	rl.next.ServeTCP(conn)
+}
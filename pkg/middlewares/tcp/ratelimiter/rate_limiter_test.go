@@ -0,0 +1,103 @@
+package ratelimiter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.TCPRateLimit
+		conns    []string
+		expected map[string]int
+	}{
+		{
+			desc:   "no rate limiting",
+			config: dynamic.TCPRateLimit{},
+			conns:  []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.0.1:1002"},
+			expected: map[string]int{
+				"10.0.0.1": 3,
+			},
+		},
+		{
+			desc:   "burst",
+			config: dynamic.TCPRateLimit{Average: 1, Period: types.Duration(time.Hour), Burst: 2},
+			conns:  []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.0.1:1002"},
+			expected: map[string]int{
+				"10.0.0.1": 2,
+			},
+		},
+		{
+			desc:   "rate limited by source IP",
+			config: dynamic.TCPRateLimit{Average: 1, Period: types.Duration(time.Hour)},
+			conns:  []string{"10.0.0.1:1000", "10.0.0.2:1000", "10.0.0.1:1001", "10.0.0.2:1001"},
+			expected: map[string]int{
+				"10.0.0.1": 1,
+				"10.0.0.2": 1,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			served := make(map[string]int)
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+				require.NoError(t, err)
+				served[host]++
+			})
+
+			rateLimiter, err := New(context.Background(), next, test.config, "rateLimiter")
+			require.NoError(t, err)
+
+			var closed int
+			for _, addr := range test.conns {
+				conn := &fakeConn{addr: addr}
+				rateLimiter.ServeTCP(conn)
+				if conn.closed {
+					closed++
+				}
+			}
+
+			assert.Equal(t, test.expected, served)
+
+			var total int
+			for _, count := range served {
+				total += count
+			}
+			assert.Equal(t, len(test.conns)-total, closed)
+		})
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+	addr   string
+	closed bool
+}
+
+func (f *fakeConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", f.addr)
+	return addr
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeConn) CloseWrite() error {
+	return nil
+}
@@ -220,13 +220,15 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 	if configuration == nil {
 		configuration = &dynamic.Configuration{
 			HTTP: &dynamic.HTTPConfiguration{
-				Routers:     make(map[string]*dynamic.Router),
-				Middlewares: make(map[string]*dynamic.Middleware),
-				Services:    make(map[string]*dynamic.Service),
+				Routers:           make(map[string]*dynamic.Router),
+				Middlewares:       make(map[string]*dynamic.Middleware),
+				Services:          make(map[string]*dynamic.Service),
+				ServersTransports: make(map[string]*dynamic.ServersTransport),
 			},
 			TCP: &dynamic.TCPConfiguration{
-				Routers:  make(map[string]*dynamic.TCPRouter),
-				Services: make(map[string]*dynamic.TCPService),
+				Routers:     make(map[string]*dynamic.TCPRouter),
+				Middlewares: make(map[string]*dynamic.TCPMiddleware),
+				Services:    make(map[string]*dynamic.TCPService),
 			},
 			TLS: &dynamic.TLSConfiguration{
 				Stores:  make(map[string]tls.Store),
@@ -289,6 +291,14 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 			}
 		}
 
+		for name, conf := range c.HTTP.ServersTransports {
+			if _, exists := configuration.HTTP.ServersTransports[name]; exists {
+				logger.WithField(log.ServersTransportName, name).Warn("HTTP servers transport already configured, skipping")
+			} else {
+				configuration.HTTP.ServersTransports[name] = conf
+			}
+		}
+
 		for name, conf := range c.TCP.Routers {
 			if _, exists := configuration.TCP.Routers[name]; exists {
 				logger.WithField(log.RouterName, name).Warn("TCP router already configured, skipping")
@@ -297,6 +307,14 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 			}
 		}
 
+		for name, conf := range c.TCP.Middlewares {
+			if _, exists := configuration.TCP.Middlewares[name]; exists {
+				logger.WithField(log.MiddlewareName, name).Warn("TCP middleware already configured, skipping")
+			} else {
+				configuration.TCP.Middlewares[name] = conf
+			}
+		}
+
 		for name, conf := range c.TCP.Services {
 			if _, exists := configuration.TCP.Services[name]; exists {
 				logger.WithField(log.ServiceName, name).Warn("TCP service already configured, skipping")
@@ -418,8 +436,9 @@ func (p *Provider) decodeConfiguration(filePath string, content string) (*dynami
 			Services:    make(map[string]*dynamic.Service),
 		},
 		TCP: &dynamic.TCPConfiguration{
-			Routers:  make(map[string]*dynamic.TCPRouter),
-			Services: make(map[string]*dynamic.TCPService),
+			Routers:     make(map[string]*dynamic.TCPRouter),
+			Middlewares: make(map[string]*dynamic.TCPMiddleware),
+			Services:    make(map[string]*dynamic.TCPService),
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Stores:  make(map[string]tls.Store),
@@ -17,13 +17,14 @@ import (
 )
 
 type ProvideTestCase struct {
-	desc                  string
-	directoryPaths        []string
-	filePath              string
-	expectedNumRouter     int
-	expectedNumService    int
-	expectedNumTLSConf    int
-	expectedNumTLSOptions int
+	desc                         string
+	directoryPaths               []string
+	filePath                     string
+	expectedNumRouter            int
+	expectedNumService           int
+	expectedNumTLSConf           int
+	expectedNumTLSOptions        int
+	expectedNumServersTransports int
 }
 
 func TestTLSContent(t *testing.T) {
@@ -98,6 +99,7 @@ func TestProvideWithoutWatch(t *testing.T) {
 				require.NotNil(t, conf.Configuration.TLS)
 				assert.Len(t, conf.Configuration.TLS.Certificates, test.expectedNumTLSConf)
 				assert.Len(t, conf.Configuration.TLS.Options, test.expectedNumTLSOptions)
+				assert.Len(t, conf.Configuration.HTTP.ServersTransports, test.expectedNumServersTransports)
 			case <-timeout:
 				t.Errorf("timeout while waiting for config")
 			}
@@ -222,6 +224,24 @@ func getTestCases() []ProvideTestCase {
 			expectedNumTLSConf:    4,
 			expectedNumTLSOptions: 1,
 		},
+		{
+			desc: "directory with servers transports",
+			directoryPaths: []string{
+				"./fixtures/toml/dir02_file01.toml",
+				"./fixtures/toml/dir02_file02.toml",
+			},
+			expectedNumService:           1,
+			expectedNumServersTransports: 2,
+		},
+		{
+			desc: "directory with servers transports yaml",
+			directoryPaths: []string{
+				"./fixtures/yaml/dir02_file01.yml",
+				"./fixtures/yaml/dir02_file02.yml",
+			},
+			expectedNumService:           1,
+			expectedNumServersTransports: 2,
+		},
 		{
 			desc: "template in directory",
 			directoryPaths: []string{
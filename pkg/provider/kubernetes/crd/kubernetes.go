@@ -198,6 +198,12 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			continue
 		}
 
+		oidc, err := createOIDCMiddleware(client, middleware.Namespace, middleware.Spec.OIDC)
+		if err != nil {
+			log.FromContext(ctxMid).Errorf("Error while reading OIDC middleware: %v", err)
+			continue
+		}
+
 		errorPage, errorPageService, err := createErrorPageMiddleware(client, middleware.Namespace, middleware.Spec.Errors)
 		if err != nil {
 			log.FromContext(ctxMid).Errorf("Error while reading error page middleware: %v", err)
@@ -232,6 +238,20 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			Compress:          middleware.Spec.Compress,
 			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
 			Retry:             middleware.Spec.Retry,
+			Cache:             middleware.Spec.Cache,
+			JWT:               middleware.Spec.JWT,
+			OIDC:              oidc,
+			RequestID:         middleware.Spec.RequestID,
+		}
+	}
+
+	for _, middlewareTCP := range client.GetMiddlewareTCPs() {
+		id := provider.Normalize(makeID(middlewareTCP.Namespace, middlewareTCP.Name))
+
+		conf.TCP.Middlewares[id] = &dynamic.TCPMiddleware{
+			IPWhiteList:  middlewareTCP.Spec.IPWhiteList,
+			InFlightConn: middlewareTCP.Spec.InFlightConn,
+			RateLimit:    middlewareTCP.Spec.RateLimit,
 		}
 	}
 
@@ -278,6 +298,10 @@ func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *v1alp
 		Address:             auth.Address,
 		TrustForwardHeader:  auth.TrustForwardHeader,
 		AuthResponseHeaders: auth.AuthResponseHeaders,
+		AuthRequestHeaders:  auth.AuthRequestHeaders,
+		ForwardBody:         auth.ForwardBody,
+		MaxBodySize:         auth.MaxBodySize,
+		Cache:               auth.Cache,
 	}
 
 	if auth.TLS == nil {
@@ -435,6 +459,45 @@ func loadAuthCredentials(namespace, secretName string, k8sClient Client) ([]stri
 	return credentials, nil
 }
 
+func createOIDCMiddleware(client Client, namespace string, oidc *v1alpha1.OIDC) (*dynamic.OIDC, error) {
+	if oidc == nil {
+		return nil, nil
+	}
+
+	if oidc.Secret == "" {
+		return nil, fmt.Errorf("OIDC secret must be set")
+	}
+
+	secret, ok, err := client.GetSecret(namespace, oidc.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %v", namespace, oidc.Secret, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, oidc.Secret)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, oidc.Secret)
+	}
+
+	sessionSecret, ok := secret.Data["sessionSecret"]
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' does not contain a sessionSecret", namespace, oidc.Secret)
+	}
+
+	return &dynamic.OIDC{
+		Issuer:                oidc.Issuer,
+		ClientID:              oidc.ClientID,
+		ClientSecret:          string(secret.Data["clientSecret"]),
+		Scopes:                oidc.Scopes,
+		RedirectPath:          oidc.RedirectPath,
+		LogoutPath:            oidc.LogoutPath,
+		PostLogoutRedirectURL: oidc.PostLogoutRedirectURL,
+		SessionSecret:         string(sessionSecret),
+		SessionCookieName:     oidc.SessionCookieName,
+		ForwardClaims:         oidc.ForwardClaims,
+	}, nil
+}
+
 func createChainMiddleware(ctx context.Context, namespace string, chain *v1alpha1.Chain) *dynamic.Chain {
 	if chain == nil {
 		return nil
@@ -15,9 +15,8 @@ import (
 )
 
 const (
-	roundRobinStrategy = "RoundRobin"
-	httpsProtocol      = "https"
-	httpProtocol       = "http"
+	httpsProtocol = "https"
+	httpProtocol  = "http"
 )
 
 func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.HTTPConfiguration {
@@ -240,8 +239,9 @@ func (c configBuilder) buildMirroring(ctx context.Context, tService *v1alpha1.Tr
 
 	conf[id] = &dynamic.Service{
 		Mirroring: &dynamic.Mirroring{
-			Service: fullNameMain,
-			Mirrors: mirrorServices,
+			Service:     fullNameMain,
+			MaxBodySize: tService.Spec.Mirroring.MaxBodySize,
+			Mirrors:     mirrorServices,
 		},
 	}
 
@@ -268,19 +268,14 @@ func (c configBuilder) buildServersLB(namespace string, svc v1alpha1.LoadBalance
 	lb.ResponseForwarding = conf.ResponseForwarding
 
 	lb.Sticky = svc.Sticky
+	lb.Strategy = svc.Strategy
+	lb.ConsistentHash = svc.ConsistentHash
+	lb.ServersTransport = svc.ServersTransport
 
 	return &dynamic.Service{LoadBalancer: lb}, nil
 }
 
 func (c configBuilder) loadServers(fallbackNamespace string, svc v1alpha1.LoadBalancerSpec) ([]dynamic.Server, error) {
-	strategy := svc.Strategy
-	if strategy == "" {
-		strategy = roundRobinStrategy
-	}
-	if strategy != roundRobinStrategy {
-		return nil, fmt.Errorf("load balancing strategy %s is not supported", strategy)
-	}
-
 	namespace := namespaceOrFallback(svc, fallbackNamespace)
 
 	// If the service uses explicitly the provider suffix
@@ -313,7 +308,8 @@ func (c configBuilder) loadServers(fallbackNamespace string, svc v1alpha1.LoadBa
 		}
 
 		return append(servers, dynamic.Server{
-			URL: fmt.Sprintf("%s://%s:%d", protocol, service.Spec.ExternalName, portSpec.Port),
+			URL:    fmt.Sprintf("%s://%s:%d", protocol, service.Spec.ExternalName, portSpec.Port),
+			Weight: svc.ServersWeight,
 		}), nil
 	}
 
@@ -348,7 +344,8 @@ func (c configBuilder) loadServers(fallbackNamespace string, svc v1alpha1.LoadBa
 
 		for _, addr := range subset.Addresses {
 			servers = append(servers, dynamic.Server{
-				URL: fmt.Sprintf("%s://%s:%d", protocol, addr.IP, port),
+				URL:    fmt.Sprintf("%s://%s:%d", protocol, addr.IP, port),
+				Weight: svc.ServersWeight,
 			})
 		}
 	}
@@ -15,8 +15,9 @@ import (
 
 func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.TCPConfiguration {
 	conf := &dynamic.TCPConfiguration{
-		Routers:  map[string]*dynamic.TCPRouter{},
-		Services: map[string]*dynamic.TCPService{},
+		Routers:     map[string]*dynamic.TCPRouter{},
+		Middlewares: map[string]*dynamic.TCPMiddleware{},
+		Services:    map[string]*dynamic.TCPService{},
 	}
 
 	for _, ingressRouteTCP := range client.GetIngressRouteTCPs() {
@@ -55,6 +56,25 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 				continue
 			}
 
+			var mds []string
+			for _, mi := range route.Middlewares {
+				if strings.Contains(mi.Name, providerNamespaceSeparator) {
+					if len(mi.Namespace) > 0 {
+						logger.
+							WithField(log.MiddlewareName, mi.Name).
+							Warnf("namespace %q is ignored in cross-provider context", mi.Namespace)
+					}
+					mds = append(mds, mi.Name)
+					continue
+				}
+
+				ns := mi.Namespace
+				if len(ns) == 0 {
+					ns = ingressRouteTCP.Namespace
+				}
+				mds = append(mds, makeID(ns, mi.Name))
+			}
+
 			serviceName := makeID(ingressRouteTCP.Namespace, key)
 
 			for _, service := range route.Services {
@@ -91,6 +111,7 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 
 			conf.Routers[serviceName] = &dynamic.TCPRouter{
 				EntryPoints: ingressRouteTCP.Spec.EntryPoints,
+				Middlewares: mds,
 				Rule:        route.Match,
 				Service:     serviceName,
 			}
@@ -149,6 +170,14 @@ func createLoadBalancerServerTCP(client Client, namespace string, service v1alph
 		tcpService.LoadBalancer.TerminationDelay = service.TerminationDelay
 	}
 
+	if service.HealthCheck != nil {
+		tcpService.LoadBalancer.HealthCheck = service.HealthCheck.DeepCopy()
+	}
+
+	if service.ProxyProtocol != nil {
+		tcpService.LoadBalancer.ProxyProtocol = service.ProxyProtocol.DeepCopy()
+	}
+
 	return tcpService, nil
 }
 
@@ -92,6 +92,10 @@ func createLoadBalancerServerUDP(client Client, namespace string, service v1alph
 		},
 	}
 
+	if service.HealthCheck != nil {
+		udpService.LoadBalancer.HealthCheck = service.HealthCheck.DeepCopy()
+	}
+
 	return udpService, nil
 }
 
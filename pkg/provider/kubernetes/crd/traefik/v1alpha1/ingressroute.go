@@ -74,8 +74,11 @@ type LoadBalancerSpec struct {
 	Scheme             string                      `json:"scheme,omitempty"`
 	HealthCheck        *HealthCheck                `json:"healthCheck,omitempty"`
 	Strategy           string                      `json:"strategy,omitempty"`
+	ConsistentHash     *dynamic.ConsistentHash     `json:"consistentHash,omitempty"`
 	PassHostHeader     *bool                       `json:"passHostHeader,omitempty"`
 	ResponseForwarding *dynamic.ResponseForwarding `json:"responseForwarding,omitempty"`
+	ServersTransport   string                      `json:"serversTransport,omitempty"`
+	ServersWeight      *int                        `json:"serversWeight,omitempty"`
 
 	// Weight should only be specified when Name references a TraefikService object
 	// (and to be precise, one that embeds a Weighted Round Robin).
@@ -98,8 +101,10 @@ func (lb LoadBalancerSpec) IsServersLB() (bool, error) {
 		lb.Scheme != "" ||
 		lb.HealthCheck != nil ||
 		lb.Strategy != "" ||
+		lb.ConsistentHash != nil ||
 		lb.PassHostHeader != nil ||
 		lb.ResponseForwarding != nil ||
+		lb.ServersTransport != "" ||
 		lb.Sticky != nil {
 		return false, fmt.Errorf("service of kind %v is incompatible with Kubernetes Service related fields", lb.Kind)
 	}
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -14,8 +15,9 @@ type IngressRouteTCPSpec struct {
 
 // RouteTCP contains the set of routes.
 type RouteTCP struct {
-	Match    string       `json:"match"`
-	Services []ServiceTCP `json:"services,omitempty"`
+	Match       string          `json:"match"`
+	Services    []ServiceTCP    `json:"services,omitempty"`
+	Middlewares []MiddlewareRef `json:"middlewares,omitempty"`
 }
 
 // TLSTCP contains the TLS certificates configuration of the routes.
@@ -53,11 +55,13 @@ type TLSStoreTCPRef struct {
 
 // ServiceTCP defines an upstream to proxy traffic.
 type ServiceTCP struct {
-	Name             string `json:"name"`
-	Namespace        string `json:"namespace"`
-	Port             int32  `json:"port"`
-	Weight           *int   `json:"weight,omitempty"`
-	TerminationDelay *int   `json:"terminationDelay,omitempty"`
+	Name             string                  `json:"name"`
+	Namespace        string                  `json:"namespace"`
+	Port             int32                   `json:"port"`
+	Weight           *int                    `json:"weight,omitempty"`
+	TerminationDelay *int                    `json:"terminationDelay,omitempty"`
+	HealthCheck      *dynamic.TCPHealthCheck `json:"healthCheck,omitempty"`
+	ProxyProtocol    *dynamic.ProxyProtocol  `json:"proxyProtocol,omitempty"`
 }
 
 // +genclient
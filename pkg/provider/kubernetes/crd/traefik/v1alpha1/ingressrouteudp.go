@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -23,10 +24,11 @@ type TLSOptionUDPRef struct {
 
 // ServiceUDP defines an upstream to proxy traffic.
 type ServiceUDP struct {
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
-	Port      int32  `json:"port"`
-	Weight    *int   `json:"weight,omitempty"`
+	Name        string                  `json:"name"`
+	Namespace   string                  `json:"namespace"`
+	Port        int32                   `json:"port"`
+	Weight      *int                    `json:"weight,omitempty"`
+	HealthCheck *dynamic.UDPHealthCheck `json:"healthCheck,omitempty"`
 }
 
 // +genclient
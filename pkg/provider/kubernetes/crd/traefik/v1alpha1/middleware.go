@@ -42,6 +42,10 @@ type MiddlewareSpec struct {
 	PassTLSClientCert *dynamic.PassTLSClientCert `json:"passTLSClientCert,omitempty"`
 	Retry             *dynamic.Retry             `json:"retry,omitempty"`
 	ContentType       *dynamic.ContentType       `json:"contentType,omitempty"`
+	Cache             *dynamic.Cache             `json:"cache,omitempty"`
+	JWT               *dynamic.JWT               `json:"jwt,omitempty"`
+	OIDC              *OIDC                      `json:"oidc,omitempty"`
+	RequestID         *dynamic.RequestID         `json:"requestId,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -82,12 +86,32 @@ type DigestAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// OIDC holds the OpenID Connect authentication configuration.
+// The client secret and the session secret are read from the clientSecret and sessionSecret keys of the Secret.
+type OIDC struct {
+	Issuer                string            `json:"issuer,omitempty"`
+	ClientID              string            `json:"clientID,omitempty"`
+	Secret                string            `json:"secret,omitempty"`
+	Scopes                []string          `json:"scopes,omitempty"`
+	RedirectPath          string            `json:"redirectPath,omitempty"`
+	LogoutPath            string            `json:"logoutPath,omitempty"`
+	PostLogoutRedirectURL string            `json:"postLogoutRedirectURL,omitempty"`
+	SessionCookieName     string            `json:"sessionCookieName,omitempty"`
+	ForwardClaims         map[string]string `json:"forwardClaims,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ForwardAuth holds the http forward authentication configuration.
 type ForwardAuth struct {
-	Address             string     `json:"address,omitempty"`
-	TrustForwardHeader  bool       `json:"trustForwardHeader,omitempty"`
-	AuthResponseHeaders []string   `json:"authResponseHeaders,omitempty"`
-	TLS                 *ClientTLS `json:"tls,omitempty"`
+	Address             string                    `json:"address,omitempty"`
+	TrustForwardHeader  bool                      `json:"trustForwardHeader,omitempty"`
+	AuthResponseHeaders []string                  `json:"authResponseHeaders,omitempty"`
+	AuthRequestHeaders  []string                  `json:"authRequestHeaders,omitempty"`
+	ForwardBody         bool                      `json:"forwardBody,omitempty"`
+	MaxBodySize         *int64                    `json:"maxBodySize,omitempty"`
+	Cache               *dynamic.ForwardAuthCache `json:"cache,omitempty"`
+	TLS                 *ClientTLS                `json:"tls,omitempty"`
 }
 
 // ClientTLS holds TLS specific configurations as client.
@@ -48,6 +48,13 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/services/Service01/loadBalancer/healthCheck/headers/name1":                     "foobar",
 		"traefik/http/services/Service01/loadBalancer/healthCheck/scheme":                            "foobar",
 		"traefik/http/services/Service01/loadBalancer/healthCheck/followredirects":                   "true",
+		"traefik/http/services/Service01/loadBalancer/healthCheck/mode":                              "foobar",
+		"traefik/http/services/Service01/loadBalancer/healthCheck/expectedStatus/0":                  "foobar",
+		"traefik/http/services/Service01/loadBalancer/healthCheck/expectedStatus/1":                  "foobar",
+		"traefik/http/services/Service01/loadBalancer/healthCheck/expectedBody":                      "foobar",
+		"traefik/http/services/Service01/loadBalancer/healthCheck/healthyThreshold":                  "42",
+		"traefik/http/services/Service01/loadBalancer/healthCheck/unhealthyThreshold":                "42",
+		"traefik/http/services/Service01/loadBalancer/hedging/delay":                                 "42",
 		"traefik/http/services/Service01/loadBalancer/responseForwarding/flushInterval":              "foobar",
 		"traefik/http/services/Service01/loadBalancer/passHostHeader":                                "true",
 		"traefik/http/services/Service01/loadBalancer/sticky/cookie/name":                            "foobar",
@@ -56,6 +63,7 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/services/Service01/loadBalancer/servers/0/url":                                 "foobar",
 		"traefik/http/services/Service01/loadBalancer/servers/1/url":                                 "foobar",
 		"traefik/http/services/Service02/mirroring/service":                                          "foobar",
+		"traefik/http/services/Service02/mirroring/maxBodySize":                                      "42",
 		"traefik/http/services/Service02/mirroring/mirrors/0/name":                                   "foobar",
 		"traefik/http/services/Service02/mirroring/mirrors/0/percent":                                "42",
 		"traefik/http/services/Service02/mirroring/mirrors/1/name":                                   "foobar",
@@ -67,6 +75,8 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/services/Service03/weighted/services/0/weight":                                 "42",
 		"traefik/http/services/Service03/weighted/services/1/name":                                   "foobar",
 		"traefik/http/services/Service03/weighted/services/1/weight":                                 "42",
+		"traefik/http/services/Service04/failover/service":                                           "foobar",
+		"traefik/http/services/Service04/failover/fallback":                                          "foobar",
 		"traefik/http/middlewares/Middleware08/forwardAuth/authResponseHeaders/0":                    "foobar",
 		"traefik/http/middlewares/Middleware08/forwardAuth/authResponseHeaders/1":                    "foobar",
 		"traefik/http/middlewares/Middleware08/forwardAuth/tls/key":                                  "foobar",
@@ -76,6 +86,13 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/middlewares/Middleware08/forwardAuth/tls/cert":                                 "foobar",
 		"traefik/http/middlewares/Middleware08/forwardAuth/address":                                  "foobar",
 		"traefik/http/middlewares/Middleware08/forwardAuth/trustForwardHeader":                       "true",
+		"traefik/http/middlewares/Middleware08/forwardAuth/authRequestHeaders/0":                     "foobar",
+		"traefik/http/middlewares/Middleware08/forwardAuth/authRequestHeaders/1":                     "foobar",
+		"traefik/http/middlewares/Middleware08/forwardAuth/forwardBody":                              "true",
+		"traefik/http/middlewares/Middleware08/forwardAuth/maxBodySize":                              "42",
+		"traefik/http/middlewares/Middleware08/forwardAuth/cache/ttl":                                "42s",
+		"traefik/http/middlewares/Middleware08/forwardAuth/cache/keyHeaders/0":                       "foobar",
+		"traefik/http/middlewares/Middleware08/forwardAuth/cache/keyHeaders/1":                       "foobar",
 		"traefik/http/middlewares/Middleware15/redirectScheme/scheme":                                "foobar",
 		"traefik/http/middlewares/Middleware15/redirectScheme/port":                                  "foobar",
 		"traefik/http/middlewares/Middleware15/redirectScheme/permanent":                             "true",
@@ -94,6 +111,10 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/middlewares/Middleware09/headers/accessControlAllowHeaders/0":                  "foobar",
 		"traefik/http/middlewares/Middleware09/headers/accessControlAllowHeaders/1":                  "foobar",
 		"traefik/http/middlewares/Middleware09/headers/accessControlAllowOrigin":                     "foobar",
+		"traefik/http/middlewares/Middleware09/headers/accessControlAllowOriginList/0":               "foobar",
+		"traefik/http/middlewares/Middleware09/headers/accessControlAllowOriginList/1":               "foobar",
+		"traefik/http/middlewares/Middleware09/headers/accessControlAllowOriginListRegex/0":          "foobar",
+		"traefik/http/middlewares/Middleware09/headers/accessControlAllowOriginListRegex/1":          "foobar",
 		"traefik/http/middlewares/Middleware09/headers/contentTypeNosniff":                           "true",
 		"traefik/http/middlewares/Middleware09/headers/accessControlAllowCredentials":                "true",
 		"traefik/http/middlewares/Middleware09/headers/featurePolicy":                                "foobar",
@@ -176,6 +197,35 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/middlewares/Middleware13/rateLimit/sourceCriterion/ipStrategy/excludedIPs/1":   "foobar",
 		"traefik/http/middlewares/Middleware20/stripPrefixRegex/regex/0":                             "foobar",
 		"traefik/http/middlewares/Middleware20/stripPrefixRegex/regex/1":                             "foobar",
+		"traefik/http/middlewares/Middleware22/cache/maxSize":                                        "42",
+		"traefik/http/middlewares/Middleware22/cache/defaultTTL":                                     "42",
+		"traefik/http/middlewares/Middleware22/cache/staleWhileRevalidate":                           "42",
+		"traefik/http/middlewares/Middleware23/jwt/keys/0":                                           "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/keys/1":                                           "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/jwksURL":                                          "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/jwksRefreshInterval":                              "42",
+		"traefik/http/middlewares/Middleware23/jwt/issuer":                                           "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/audience":                                         "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/requiredClaims/0":                                 "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/requiredClaims/1":                                 "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/forwardClaims/name0":                              "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/forwardClaims/name1":                              "foobar",
+		"traefik/http/middlewares/Middleware23/jwt/removeHeader":                                     "true",
+		"traefik/http/middlewares/Middleware24/oidc/issuer":                                          "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/clientID":                                        "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/clientSecret":                                    "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/scopes/0":                                        "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/scopes/1":                                        "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/redirectPath":                                    "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/logoutPath":                                      "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/postLogoutRedirectURL":                           "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/sessionSecret":                                   "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/sessionCookieName":                               "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/forwardClaims/name0":                             "foobar",
+		"traefik/http/middlewares/Middleware24/oidc/forwardClaims/name1":                             "foobar",
+		"traefik/http/middlewares/Middleware25/requestId/headerName":                                 "foobar",
+		"traefik/http/middlewares/Middleware25/requestId/trustedIPs/0":                               "foobar",
+		"traefik/http/middlewares/Middleware25/requestId/trustedIPs/1":                               "foobar",
 		"traefik/http/middlewares/Middleware01/basicAuth/users/0":                                    "foobar",
 		"traefik/http/middlewares/Middleware01/basicAuth/users/1":                                    "foobar",
 		"traefik/http/middlewares/Middleware01/basicAuth/usersFile":                                  "foobar",
@@ -189,6 +239,12 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/middlewares/Middleware02/buffering/memRequestBodyBytes":                        "42",
 		"traefik/http/middlewares/Middleware05/compress":                                             "",
 		"traefik/http/middlewares/Middleware18/retry/attempts":                                       "42",
+		"traefik/http/middlewares/Middleware18/retry/initialInterval":                                "42",
+		"traefik/http/middlewares/Middleware18/retry/status/0":                                       "foobar",
+		"traefik/http/middlewares/Middleware18/retry/status/1":                                       "foobar",
+		"traefik/http/middlewares/Middleware18/retry/perTryTimeout":                                  "42",
+		"traefik/http/middlewares/Middleware18/retry/budget/percent":                                 "42",
+		"traefik/http/middlewares/Middleware18/retry/budget/minRetries":                              "42",
 		"traefik/http/middlewares/Middleware19/stripPrefix/prefixes/0":                               "foobar",
 		"traefik/http/middlewares/Middleware19/stripPrefix/prefixes/1":                               "foobar",
 		"traefik/http/middlewares/Middleware19/stripPrefix/forceSlash":                               "true",
@@ -403,6 +459,19 @@ func Test_buildConfiguration(t *testing.T) {
 							"foobar",
 							"foobar",
 						},
+						AuthRequestHeaders: []string{
+							"foobar",
+							"foobar",
+						},
+						ForwardBody: true,
+						MaxBodySize: func(i int64) *int64 { return &i }(42),
+						Cache: &dynamic.ForwardAuthCache{
+							TTL: types.Duration(42 * time.Second),
+							KeyHeaders: []string{
+								"foobar",
+								"foobar",
+							},
+						},
 					},
 				},
 				"Middleware06": {
@@ -419,7 +488,14 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 				"Middleware18": {
 					Retry: &dynamic.Retry{
-						Attempts: 42,
+						Attempts:        42,
+						InitialInterval: types.Duration(42 * time.Second),
+						Status:          []string{"foobar", "foobar"},
+						PerTryTimeout:   types.Duration(42 * time.Second),
+						Budget: &dynamic.RetryBudget{
+							Percent:    42,
+							MinRetries: 42,
+						},
 					},
 				},
 				"Middleware16": {
@@ -435,6 +511,51 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				"Middleware22": {
+					Cache: &dynamic.Cache{
+						MaxSize:              42,
+						DefaultTTL:           types.Duration(42 * time.Second),
+						StaleWhileRevalidate: types.Duration(42 * time.Second),
+					},
+				},
+				"Middleware23": {
+					JWT: &dynamic.JWT{
+						Keys:                []string{"foobar", "foobar"},
+						JWKSURL:             "foobar",
+						JWKSRefreshInterval: types.Duration(42 * time.Second),
+						Issuer:              "foobar",
+						Audience:            "foobar",
+						RequiredClaims:      []string{"foobar", "foobar"},
+						ForwardClaims: map[string]string{
+							"name0": "foobar",
+							"name1": "foobar",
+						},
+						RemoveHeader: true,
+					},
+				},
+				"Middleware24": {
+					OIDC: &dynamic.OIDC{
+						Issuer:                "foobar",
+						ClientID:              "foobar",
+						ClientSecret:          "foobar",
+						Scopes:                []string{"foobar", "foobar"},
+						RedirectPath:          "foobar",
+						LogoutPath:            "foobar",
+						PostLogoutRedirectURL: "foobar",
+						SessionSecret:         "foobar",
+						SessionCookieName:     "foobar",
+						ForwardClaims: map[string]string{
+							"name0": "foobar",
+							"name1": "foobar",
+						},
+					},
+				},
+				"Middleware25": {
+					RequestID: &dynamic.RequestID{
+						HeaderName: "foobar",
+						TrustedIPs: []string{"foobar", "foobar"},
+					},
+				},
 				"Middleware03": {
 					Chain: &dynamic.Chain{
 						Middlewares: []string{
@@ -543,6 +664,14 @@ func Test_buildConfiguration(t *testing.T) {
 							"foobar",
 						},
 						AccessControlAllowOrigin: "foobar",
+						AccessControlAllowOriginList: []string{
+							"foobar",
+							"foobar",
+						},
+						AccessControlAllowOriginListRegex: []string{
+							"foobar",
+							"foobar",
+						},
 						AccessControlExposeHeaders: []string{
 							"foobar",
 							"foobar",
@@ -609,6 +738,7 @@ func Test_buildConfiguration(t *testing.T) {
 							},
 						},
 						HealthCheck: &dynamic.HealthCheck{
+							Mode:            "foobar",
 							Scheme:          "foobar",
 							Path:            "foobar",
 							Port:            42,
@@ -620,6 +750,13 @@ func Test_buildConfiguration(t *testing.T) {
 								"name0": "foobar",
 								"name1": "foobar",
 							},
+							ExpectedStatus:     []string{"foobar", "foobar"},
+							ExpectedBody:       "foobar",
+							HealthyThreshold:   42,
+							UnhealthyThreshold: 42,
+						},
+						Hedging: &dynamic.Hedging{
+							Delay: types.Duration(42 * time.Second),
 						},
 						PassHostHeader: func(v bool) *bool { return &v }(true),
 						ResponseForwarding: &dynamic.ResponseForwarding{
@@ -629,7 +766,8 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 				"Service02": {
 					Mirroring: &dynamic.Mirroring{
-						Service: "foobar",
+						Service:     "foobar",
+						MaxBodySize: func(v int64) *int64 { return &v }(42),
 						Mirrors: []dynamic.MirrorService{
 							{
 								Name:    "foobar",
@@ -663,6 +801,12 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				"Service04": {
+					Failover: &dynamic.Failover{
+						Service:  "foobar",
+						Fallback: "foobar",
+					},
+				},
 			},
 		},
 		TCP: &dynamic.TCPConfiguration{
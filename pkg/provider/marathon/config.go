@@ -316,7 +316,8 @@ func (p *Provider) getServer(app marathon.Application, task marathon.Task, extra
 	}
 
 	server := dynamic.Server{
-		URL: fmt.Sprintf("%s://%s", defaultServer.Scheme, net.JoinHostPort(host, port)),
+		URL:    fmt.Sprintf("%s://%s", defaultServer.Scheme, net.JoinHostPort(host, port)),
+		Weight: defaultServer.Weight,
 	}
 
 	return server, nil
@@ -281,7 +281,8 @@ func (p *Provider) addServers(ctx context.Context, service rancherData, loadBala
 	var servers []dynamic.Server
 	for _, containerIP := range service.Containers {
 		servers = append(servers, dynamic.Server{
-			URL: fmt.Sprintf("%s://%s", loadBalancer.Servers[0].Scheme, net.JoinHostPort(containerIP, port)),
+			URL:    fmt.Sprintf("%s://%s", loadBalancer.Servers[0].Scheme, net.JoinHostPort(containerIP, port)),
+			Weight: loadBalancer.Servers[0].Weight,
 		})
 	}
 
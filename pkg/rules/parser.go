@@ -32,6 +32,37 @@ func ParseDomains(rule string) ([]string, error) {
 // ParseHostSNI extracts the HostSNIs declared in a rule
 // This is a first naive implementation used in TCP routing
 func ParseHostSNI(rule string) ([]string, error) {
+	tcpTree, err := parseTCPRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return lower(parseDomain(tcpTree)), nil
+}
+
+// IsHostSNIOnly reports whether a TCP rule only consists of HostSNI matchers combined with the OR operator,
+// in which case the connections can be routed by SNI.
+func IsHostSNIOnly(rule string) (bool, error) {
+	tcpTree, err := parseTCPRule(rule)
+	if err != nil {
+		return false, err
+	}
+
+	return isHostSNIOnly(tcpTree), nil
+}
+
+func isHostSNIOnly(tree *tree) bool {
+	switch tree.matcher {
+	case "or":
+		return isHostSNIOnly(tree.ruleLeft) && isHostSNIOnly(tree.ruleRight)
+	case "HostSNI":
+		return true
+	default:
+		return false
+	}
+}
+
+func parseTCPRule(rule string) (*tree, error) {
 	parser, err := newTCPParser()
 	if err != nil {
 		return nil, err
@@ -47,7 +78,7 @@ func ParseHostSNI(rule string) ([]string, error) {
 		return nil, errors.New("cannot parse")
 	}
 
-	return lower(parseDomain(buildTree())), nil
+	return buildTree(), nil
 }
 
 func lower(slice []string) []string {
@@ -62,6 +93,9 @@ func parseDomain(tree *tree) []string {
 	switch tree.matcher {
 	case "and", "or":
 		return append(parseDomain(tree.ruleLeft), parseDomain(tree.ruleRight)...)
+	case "not":
+		// The domains of a negated rule are the ones not to match.
+		return nil
 	case "Host", "HostSNI":
 		return tree.value
 	default:
@@ -89,6 +123,15 @@ func orFunc(left, right treeBuilder) treeBuilder {
 	}
 }
 
+func notFunc(operand treeBuilder) treeBuilder {
+	return func() *tree {
+		return &tree{
+			matcher:  "not",
+			ruleLeft: operand(),
+		}
+	}
+}
+
 func newParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
@@ -108,10 +151,15 @@ func newParser() (predicate.Parser, error) {
 		parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 	}
 
+	parserFuncs["Not"] = notFunc
+	parserFuncs["not"] = notFunc
+	parserFuncs["NOT"] = notFunc
+
 	return predicate.NewParser(predicate.Def{
 		Operators: predicate.Operators{
 			AND: andFunc,
 			OR:  orFunc,
+			NOT: notFunc,
 		},
 		Functions: parserFuncs,
 	})
@@ -120,24 +168,26 @@ func newParser() (predicate.Parser, error) {
 func newTCPParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
-	// FIXME quircky way of waiting for new rules
-	matcherName := "HostSNI"
-	fn := func(value ...string) treeBuilder {
-		return func() *tree {
-			return &tree{
-				matcher: matcherName,
-				value:   value,
+	for matcherName := range tcpFuncs {
+		matcherName := matcherName
+		fn := func(value ...string) treeBuilder {
+			return func() *tree {
+				return &tree{
+					matcher: matcherName,
+					value:   value,
+				}
 			}
 		}
+		parserFuncs[matcherName] = fn
+		parserFuncs[strings.ToLower(matcherName)] = fn
+		parserFuncs[strings.ToUpper(matcherName)] = fn
+		parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 	}
-	parserFuncs[matcherName] = fn
-	parserFuncs[strings.ToLower(matcherName)] = fn
-	parserFuncs[strings.ToUpper(matcherName)] = fn
-	parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 
 	return predicate.NewParser(predicate.Def{
 		Operators: predicate.Operators{
-			OR: orFunc,
+			AND: andFunc,
+			OR:  orFunc,
 		},
 		Functions: parserFuncs,
 	})
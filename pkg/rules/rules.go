@@ -3,8 +3,10 @@ package rules
 import (
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
 
+	"github.com/containous/traefik/v2/pkg/ip"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/gorilla/mux"
@@ -16,28 +18,64 @@ var funcs = map[string]func(*mux.Route, ...string) error{
 	"HostRegexp":    hostRegexp,
 	"Path":          path,
 	"PathPrefix":    pathPrefix,
+	"PathRegexp":    pathRegexp,
 	"Method":        methods,
 	"Headers":       headers,
 	"HeadersRegexp": headersRegexp,
+	"HeaderExists":  headerExists,
 	"Query":         query,
+	"QueryRegexp":   queryRegexp,
+	"Cookie":        cookie,
+	"ClientIP":      remoteAddrClientIP,
 }
 
 // Router handle routing with rules
 type Router struct {
 	*mux.Router
-	parser predicate.Parser
+	parser   predicate.Parser
+	matchers map[string]func(*mux.Route, ...string) error
 }
 
 // NewRouter returns a new router instance.
+// Its ClientIP matcher uses the remote address of the requests.
 func NewRouter() (*Router, error) {
+	return newRouter(&ip.RemoteAddrStrategy{})
+}
+
+// NewForwardedRouter returns a new router instance.
+// Its ClientIP matcher uses the X-Forwarded-For header of the requests coming from the trusted IPs (or from any IP if insecure is set),
+// in the same way as the forwarded headers of the entry points.
+func NewForwardedRouter(insecure bool, trustedIPs []string) (*Router, error) {
+	strategy := &ip.ForwardedStrategy{Insecure: insecure}
+	if len(trustedIPs) > 0 {
+		checker, err := ip.NewChecker(trustedIPs)
+		if err != nil {
+			return nil, err
+		}
+		strategy.Checker = checker
+	}
+
+	return newRouter(strategy)
+}
+
+func newRouter(clientIPStrategy ip.Strategy) (*Router, error) {
 	parser, err := newParser()
 	if err != nil {
 		return nil, err
 	}
 
+	matchers := make(map[string]func(*mux.Route, ...string) error, len(funcs))
+	for name, matcher := range funcs {
+		matchers[name] = matcher
+	}
+	matchers["ClientIP"] = func(route *mux.Route, ranges ...string) error {
+		return clientIP(route, clientIPStrategy, ranges...)
+	}
+
 	return &Router{
-		Router: mux.NewRouter().SkipClean(true),
-		parser: parser,
+		Router:   mux.NewRouter().SkipClean(true),
+		parser:   parser,
+		matchers: matchers,
 	}, nil
 }
 
@@ -58,7 +96,7 @@ func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error
 	}
 
 	route := r.NewRoute().Handler(handler).Priority(priority)
-	return addRuleOnRoute(route, buildTree())
+	return r.addRuleOnRoute(route, buildTree())
 }
 
 type tree struct {
@@ -92,6 +130,27 @@ func pathPrefix(route *mux.Route, paths ...string) error {
 	return nil
 }
 
+func pathRegexp(route *mux.Route, exprs ...string) error {
+	var regexps []*regexp.Regexp
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return err
+		}
+		regexps = append(regexps, re)
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		for _, re := range regexps {
+			if re.MatchString(req.URL.Path) {
+				return true
+			}
+		}
+		return false
+	})
+	return nil
+}
+
 func host(route *mux.Route, hosts ...string) error {
 	for i, host := range hosts {
 		hosts[i] = strings.ToLower(host)
@@ -175,58 +234,159 @@ func query(route *mux.Route, query ...string) error {
 	return route.GetError()
 }
 
-func addRuleOnRouter(router *mux.Router, rule *tree) error {
+func headerExists(route *mux.Route, names ...string) error {
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		for _, name := range names {
+			if _, ok := req.Header[http.CanonicalHeaderKey(name)]; !ok {
+				return false
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+func queryRegexp(route *mux.Route, query ...string) error {
+	regexps := make(map[string]*regexp.Regexp)
+	for _, elem := range query {
+		parts := strings.SplitN(elem, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid query regexp %q, expected key=regexp", elem)
+		}
+
+		re, err := regexp.Compile(parts[1])
+		if err != nil {
+			return err
+		}
+		regexps[parts[0]] = re
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		values := req.URL.Query()
+		for key, re := range regexps {
+			if !containsMatch(values[key], re) {
+				return false
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+func containsMatch(values []string, re *regexp.Regexp) bool {
+	for _, value := range values {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func cookie(route *mux.Route, cookie ...string) error {
+	if len(cookie) > 2 {
+		return fmt.Errorf("too many args for matcher Cookie, expected a name and an optional value: %v", cookie)
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		c, err := req.Cookie(cookie[0])
+		if err != nil {
+			return false
+		}
+		return len(cookie) == 1 || c.Value == cookie[1]
+	})
+	return nil
+}
+
+func remoteAddrClientIP(route *mux.Route, ranges ...string) error {
+	return clientIP(route, &ip.RemoteAddrStrategy{}, ranges...)
+}
+
+func clientIP(route *mux.Route, strategy ip.Strategy, ranges ...string) error {
+	checker, err := ip.NewChecker(ranges)
+	if err != nil {
+		return err
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		ok, err := checker.Contains(strategy.GetIP(req))
+		if err != nil {
+			log.FromContext(req.Context()).Debugf("ClientIP: %v", err)
+			return false
+		}
+		return ok
+	})
+	return nil
+}
+
+// not adds to the route a matcher negating the given rule.
+func (r *Router) not(route *mux.Route, rule *tree) error {
+	negated := mux.NewRouter().SkipClean(true).NewRoute()
+	if err := r.addRuleOnRoute(negated, rule); err != nil {
+		return err
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		return !negated.Match(req, &mux.RouteMatch{})
+	})
+	return nil
+}
+
+func (r *Router) addRuleOnRouter(router *mux.Router, rule *tree) error {
 	switch rule.matcher {
 	case "and":
 		route := router.NewRoute()
-		err := addRuleOnRoute(route, rule.ruleLeft)
+		err := r.addRuleOnRoute(route, rule.ruleLeft)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRoute(route, rule.ruleRight)
+		return r.addRuleOnRoute(route, rule.ruleRight)
 	case "or":
-		err := addRuleOnRouter(router, rule.ruleLeft)
+		err := r.addRuleOnRouter(router, rule.ruleLeft)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRouter(router, rule.ruleRight)
+		return r.addRuleOnRouter(router, rule.ruleRight)
+	case "not":
+		return r.not(router.NewRoute(), rule.ruleLeft)
 	default:
 		err := checkRule(rule)
 		if err != nil {
 			return err
 		}
 
-		return funcs[rule.matcher](router.NewRoute(), rule.value...)
+		return r.matchers[rule.matcher](router.NewRoute(), rule.value...)
 	}
 }
 
-func addRuleOnRoute(route *mux.Route, rule *tree) error {
+func (r *Router) addRuleOnRoute(route *mux.Route, rule *tree) error {
 	switch rule.matcher {
 	case "and":
-		err := addRuleOnRoute(route, rule.ruleLeft)
+		err := r.addRuleOnRoute(route, rule.ruleLeft)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRoute(route, rule.ruleRight)
+		return r.addRuleOnRoute(route, rule.ruleRight)
 	case "or":
 		subRouter := route.Subrouter()
 
-		err := addRuleOnRouter(subRouter, rule.ruleLeft)
+		err := r.addRuleOnRouter(subRouter, rule.ruleLeft)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRouter(subRouter, rule.ruleRight)
+		return r.addRuleOnRouter(subRouter, rule.ruleRight)
+	case "not":
+		return r.not(route, rule.ruleLeft)
 	default:
 		err := checkRule(rule)
 		if err != nil {
 			return err
 		}
 
-		return funcs[rule.matcher](route, rule.value...)
+		return r.matchers[rule.matcher](route, rule.value...)
 	}
 }
 
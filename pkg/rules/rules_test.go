@@ -17,6 +17,8 @@ func Test_addRoute(t *testing.T) {
 		desc          string
 		rule          string
 		headers       map[string]string
+		remoteAddr    string
+		trustedIPs    []string
 		expected      map[string]int
 		expectedError bool
 	}{
@@ -221,6 +223,218 @@ func Test_addRoute(t *testing.T) {
 				"http://localhost/foo?bar=baz":         http.StatusNotFound,
 			},
 		},
+		{
+			desc: "QueryRegexp",
+			rule: "QueryRegexp(`id=^[0-9]+$`)",
+			expected: map[string]int{
+				"http://localhost/foo?id=42":       http.StatusOK,
+				"http://localhost/foo?id=a&id=42":  http.StatusOK,
+				"http://localhost/foo?id=a42":      http.StatusNotFound,
+				"http://localhost/foo?other=42":    http.StatusNotFound,
+				"http://localhost/foo?id=42&foo=b": http.StatusOK,
+			},
+		},
+		{
+			desc: "PathRegexp",
+			rule: "PathRegexp(`^/articles/[0-9]+$`, `^/posts/`)",
+			expected: map[string]int{
+				"http://localhost/articles/42":     http.StatusOK,
+				"http://localhost/articles/foo":    http.StatusNotFound,
+				"http://localhost/posts/foo":       http.StatusOK,
+				"http://localhost/foo/articles/42": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "HeaderExists with the header",
+			rule: "HeaderExists(`X-Foo`, `x-bar`)",
+			headers: map[string]string{
+				"X-Foo": "",
+				"X-Bar": "bar",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "HeaderExists without one of the headers",
+			rule: "HeaderExists(`X-Foo`, `X-Bar`)",
+			headers: map[string]string{
+				"X-Foo": "foo",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Cookie with name",
+			rule: "Cookie(`session`)",
+			headers: map[string]string{
+				"Cookie": "foo=bar; session=abc",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "Cookie with name and value",
+			rule: "Cookie(`session`, `abc`)",
+			headers: map[string]string{
+				"Cookie": "session=abc",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "Cookie with wrong value",
+			rule: "Cookie(`session`, `def`)",
+			headers: map[string]string{
+				"Cookie": "session=abc",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Cookie without the cookie",
+			rule: "Cookie(`session`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:       "ClientIP with remote address in range",
+			rule:       "ClientIP(`10.0.0.0/8`, `192.168.1.1`)",
+			remoteAddr: "10.1.2.3:4242",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc:       "ClientIP with remote address out of range",
+			rule:       "ClientIP(`10.0.0.0/8`, `192.168.1.1`)",
+			remoteAddr: "192.168.1.2:4242",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:       "ClientIP ignores X-Forwarded-For without trusted IPs",
+			rule:       "ClientIP(`192.168.1.1`)",
+			remoteAddr: "10.1.2.3:4242",
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1, 10.0.0.1",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:       "ClientIP with X-Forwarded-For from a trusted proxy",
+			rule:       "ClientIP(`192.168.1.1`)",
+			remoteAddr: "10.1.2.3:4242",
+			trustedIPs: []string{"10.0.0.0/8"},
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1, 10.0.0.1",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc:       "ClientIP with X-Forwarded-For spoofed by the client of a trusted proxy",
+			rule:       "ClientIP(`192.168.1.1`)",
+			remoteAddr: "10.1.2.3:4242",
+			trustedIPs: []string{"10.0.0.0/8"},
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1, 172.16.0.1",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:       "ClientIP with X-Forwarded-For from an untrusted source",
+			rule:       "ClientIP(`192.168.1.1`)",
+			remoteAddr: "172.16.0.1:4242",
+			trustedIPs: []string{"10.0.0.0/8"},
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:       "ClientIP with remote address of a trusted proxy and no X-Forwarded-For",
+			rule:       "ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "10.1.2.3:4242",
+			trustedIPs: []string{"10.0.0.0/8"},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc:       "ClientIP with remote address in range and X-Forwarded-For",
+			rule:       "ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "10.1.2.3:4242",
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "Not",
+			rule: "Host(`localhost`) && !PathPrefix(`/foo`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+				"http://localhost/bar": http.StatusOK,
+			},
+		},
+		{
+			desc: "Not function",
+			rule: "Not(PathPrefix(`/foo`))",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+				"http://localhost/bar": http.StatusOK,
+			},
+		},
+		{
+			desc: "Not with parenthesis",
+			rule: "!(Host(`localhost`) && PathPrefix(`/foo`)) && PathPrefix(`/`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+				"http://localhost/bar": http.StatusOK,
+				"http://other/foo":     http.StatusOK,
+			},
+		},
+		{
+			desc: "Not with OR",
+			rule: "!(PathPrefix(`/foo`) || PathPrefix(`/bar`))",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+				"http://localhost/bar": http.StatusNotFound,
+				"http://localhost/baz": http.StatusOK,
+			},
+		},
+		{
+			desc: "Not method",
+			rule: "!Method(`POST`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "OR with Not",
+			rule: "PathPrefix(`/foo`) || !Host(`localhost`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+				"http://localhost/bar": http.StatusNotFound,
+				"http://other/bar":     http.StatusOK,
+			},
+		},
 		{
 			desc: "Rule with simple path",
 			rule: `Path("/a")`,
@@ -392,6 +606,31 @@ func Test_addRoute(t *testing.T) {
 			rule:          `Query("titi={test")`,
 			expectedError: true,
 		},
+		{
+			desc:          "Rule QueryRegexp without regexp",
+			rule:          `QueryRegexp("titi")`,
+			expectedError: true,
+		},
+		{
+			desc:          "Rule PathRegexp with bad regexp",
+			rule:          `PathRegexp("/titi(")`,
+			expectedError: true,
+		},
+		{
+			desc:          "Rule Cookie with too many args",
+			rule:          `Cookie("titi", "toto", "tata")`,
+			expectedError: true,
+		},
+		{
+			desc:          "Rule ClientIP with bad range",
+			rule:          `ClientIP("titi")`,
+			expectedError: true,
+		},
+		{
+			desc:          "Rule Not with bad matcher",
+			rule:          `!Path("titi")`,
+			expectedError: true,
+		},
 		{
 			desc:          "Rule with Path without args",
 			rule:          `Host("tchouk") && Path()`,
@@ -415,7 +654,7 @@ func Test_addRoute(t *testing.T) {
 			t.Parallel()
 
 			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
-			router, err := NewRouter()
+			router, err := NewForwardedRouter(false, test.trustedIPs)
 			require.NoError(t, err)
 
 			err = router.AddRoute(test.rule, 0, handler)
@@ -435,6 +674,9 @@ func Test_addRoute(t *testing.T) {
 					for key, value := range test.headers {
 						req.Header.Set(key, value)
 					}
+					if test.remoteAddr != "" {
+						req.RemoteAddr = test.remoteAddr
+					}
 					reqHost.ServeHTTP(w, req, router.ServeHTTP)
 					results[calledURL] = w.Code
 				}
@@ -698,6 +940,12 @@ func TestParseDomains(t *testing.T) {
 			domain:        []string{"foo.bar"},
 			errorExpected: false,
 		},
+		{
+			description:   "Negated host rule",
+			expression:    "Host(`foo.bar`) && !Host(`test.bar`)",
+			domain:        []string{"foo.bar"},
+			errorExpected: false,
+		},
 		{
 			description:   "Host rule with no domain",
 			expression:    "Host() && Path(`/test`)",
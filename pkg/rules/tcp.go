@@ -0,0 +1,96 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/ip"
+	"github.com/containous/traefik/v2/pkg/tcp"
+)
+
+var tcpFuncs = map[string]func(...string) (tcp.Matcher, error){
+	"HostSNI":  hostSNI,
+	"ClientIP": clientIPTCP,
+	"ALPN":     alpn,
+}
+
+// NewTCPMatcher returns a matcher for the connections matching the given TCP rule.
+func NewTCPMatcher(rule string) (tcp.Matcher, error) {
+	tcpTree, err := parseTCPRule(rule)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing rule %s: %w", rule, err)
+	}
+
+	return buildTCPMatcher(tcpTree)
+}
+
+func buildTCPMatcher(rule *tree) (tcp.Matcher, error) {
+	switch rule.matcher {
+	case "and", "or":
+		left, err := buildTCPMatcher(rule.ruleLeft)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := buildTCPMatcher(rule.ruleRight)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule.matcher == "and" {
+			return func(data tcp.ConnData) bool {
+				return left(data) && right(data)
+			}, nil
+		}
+		return func(data tcp.ConnData) bool {
+			return left(data) || right(data)
+		}, nil
+	default:
+		err := checkRule(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		return tcpFuncs[rule.matcher](rule.value...)
+	}
+}
+
+func hostSNI(hosts ...string) (tcp.Matcher, error) {
+	for i, host := range hosts {
+		hosts[i] = strings.ToLower(host)
+	}
+
+	return func(data tcp.ConnData) bool {
+		for _, host := range hosts {
+			if host == "*" || host == data.ServerName {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func clientIPTCP(ranges ...string) (tcp.Matcher, error) {
+	checker, err := ip.NewChecker(ranges)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(data tcp.ConnData) bool {
+		ok, err := checker.Contains(data.RemoteIP)
+		return err == nil && ok
+	}, nil
+}
+
+func alpn(protos ...string) (tcp.Matcher, error) {
+	return func(data tcp.ConnData) bool {
+		for _, proto := range protos {
+			for _, clientProto := range data.ALPNProtos {
+				if proto == clientProto {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
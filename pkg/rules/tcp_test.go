@@ -0,0 +1,145 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTCPMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[string]tcp.ConnData
+		notExpected   map[string]tcp.ConnData
+		expectedError bool
+	}{
+		{
+			desc: "HostSNI",
+			rule: "HostSNI(`foo.bar`, `Bar.Foo`)",
+			expected: map[string]tcp.ConnData{
+				"first host":  {ServerName: "foo.bar"},
+				"second host": {ServerName: "bar.foo"},
+			},
+			notExpected: map[string]tcp.ConnData{
+				"other host": {ServerName: "foo.foo"},
+				"no SNI":     {},
+			},
+		},
+		{
+			desc: "HostSNI catch-all",
+			rule: "HostSNI(`*`)",
+			expected: map[string]tcp.ConnData{
+				"host":   {ServerName: "foo.bar"},
+				"no SNI": {},
+			},
+		},
+		{
+			desc: "ClientIP",
+			rule: "ClientIP(`10.0.0.0/8`, `192.168.1.1`)",
+			expected: map[string]tcp.ConnData{
+				"in range": {RemoteIP: "10.1.2.3"},
+				"IP":       {RemoteIP: "192.168.1.1"},
+			},
+			notExpected: map[string]tcp.ConnData{
+				"out of range": {RemoteIP: "192.168.1.2"},
+				"no IP":        {},
+			},
+		},
+		{
+			desc: "ALPN",
+			rule: "ALPN(`h2`)",
+			expected: map[string]tcp.ConnData{
+				"h2": {ALPNProtos: []string{"http/1.1", "h2"}},
+			},
+			notExpected: map[string]tcp.ConnData{
+				"http/1.1": {ALPNProtos: []string{"http/1.1"}},
+				"no ALPN":  {},
+			},
+		},
+		{
+			desc: "HostSNI and ALPN",
+			rule: "HostSNI(`foo.bar`) && ALPN(`h2`)",
+			expected: map[string]tcp.ConnData{
+				"both": {ServerName: "foo.bar", ALPNProtos: []string{"h2"}},
+			},
+			notExpected: map[string]tcp.ConnData{
+				"host only": {ServerName: "foo.bar", ALPNProtos: []string{"http/1.1"}},
+				"ALPN only": {ServerName: "bar.foo", ALPNProtos: []string{"h2"}},
+			},
+		},
+		{
+			desc: "HostSNI and (ClientIP or ClientIP)",
+			rule: "HostSNI(`*`) && (ClientIP(`10.0.0.1`) || ClientIP(`10.0.0.2`))",
+			expected: map[string]tcp.ConnData{
+				"first IP":  {RemoteIP: "10.0.0.1"},
+				"second IP": {RemoteIP: "10.0.0.2"},
+			},
+			notExpected: map[string]tcp.ConnData{
+				"other IP": {RemoteIP: "10.0.0.3"},
+			},
+		},
+		{
+			desc:          "unknown matcher",
+			rule:          "Host(`foo.bar`)",
+			expectedError: true,
+		},
+		{
+			desc:          "ClientIP with bad range",
+			rule:          "ClientIP(`foo`)",
+			expectedError: true,
+		},
+		{
+			desc:          "ALPN without protocol",
+			rule:          "ALPN()",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := NewTCPMatcher(test.rule)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for desc, data := range test.expected {
+				assert.True(t, matcher(data), desc)
+			}
+			for desc, data := range test.notExpected {
+				assert.False(t, matcher(data), desc)
+			}
+		})
+	}
+}
+
+func TestIsHostSNIOnly(t *testing.T) {
+	testCases := []struct {
+		rule     string
+		expected bool
+	}{
+		{rule: "HostSNI(`foo.bar`)", expected: true},
+		{rule: "HostSNI(`foo.bar`) || HostSNI(`bar.foo`)", expected: true},
+		{rule: "HostSNI(`foo.bar`) && HostSNI(`bar.foo`)", expected: false},
+		{rule: "HostSNI(`*`) && ClientIP(`10.0.0.1`)", expected: false},
+		{rule: "HostSNI(`foo.bar`) || ALPN(`h2`)", expected: false},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.rule, func(t *testing.T) {
+			t.Parallel()
+
+			hostSNIOnly, err := IsHostSNIOnly(test.rule)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, hostSNIOnly)
+		})
+	}
+}
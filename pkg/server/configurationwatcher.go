@@ -233,9 +233,9 @@ func isEmptyConfiguration(conf *dynamic.Configuration) bool {
 		conf.HTTP = &dynamic.HTTPConfiguration{}
 	}
 
-	httpEmpty := conf.HTTP.Routers == nil && conf.HTTP.Services == nil && conf.HTTP.Middlewares == nil
+	httpEmpty := conf.HTTP.Routers == nil && conf.HTTP.Services == nil && conf.HTTP.Middlewares == nil && conf.HTTP.ServersTransports == nil
 	tlsEmpty := conf.TLS == nil || conf.TLS.Certificates == nil && conf.TLS.Stores == nil && conf.TLS.Options == nil
-	tcpEmpty := conf.TCP.Routers == nil && conf.TCP.Services == nil
+	tcpEmpty := conf.TCP.Routers == nil && conf.TCP.Services == nil && conf.TCP.Middlewares == nil
 
 	return httpEmpty && tlsEmpty && tcpEmpty
 }
@@ -68,10 +68,12 @@ func TestNewConfigurationWatcher(t *testing.T) {
 						th.WithServiceName("scv"))),
 				th.WithMiddlewares(),
 				th.WithLoadBalancerServices(),
+				th.WithServersTransports(),
 			),
 			TCP: &dynamic.TCPConfiguration{
-				Routers:  map[string]*dynamic.TCPRouter{},
-				Services: map[string]*dynamic.TCPService{},
+				Routers:     map[string]*dynamic.TCPRouter{},
+				Middlewares: map[string]*dynamic.TCPMiddleware{},
+				Services:    map[string]*dynamic.TCPService{},
 			},
 			TLS: &dynamic.TLSConfiguration{
 				Options: map[string]tls.Options{
@@ -223,10 +225,12 @@ func TestListenProvidersDoesNotSkipFlappingConfiguration(t *testing.T) {
 			th.WithRouters(th.WithRouter("foo@mock")),
 			th.WithLoadBalancerServices(th.WithService("bar@mock")),
 			th.WithMiddlewares(),
+			th.WithServersTransports(),
 		),
 		TCP: &dynamic.TCPConfiguration{
-			Routers:  map[string]*dynamic.TCPRouter{},
-			Services: map[string]*dynamic.TCPService{},
+			Routers:     map[string]*dynamic.TCPRouter{},
+			Middlewares: map[string]*dynamic.TCPMiddleware{},
+			Services:    map[string]*dynamic.TCPService{},
 		},
 		UDP: &dynamic.UDPConfiguration{
 			Routers:  map[string]*dynamic.UDPRouter{},
@@ -279,10 +283,12 @@ func TestListenProvidersPublishesConfigForEachProvider(t *testing.T) {
 			th.WithRouters(th.WithRouter("foo@mock"), th.WithRouter("foo@mock2")),
 			th.WithLoadBalancerServices(th.WithService("bar@mock"), th.WithService("bar@mock2")),
 			th.WithMiddlewares(),
+			th.WithServersTransports(),
 		),
 		TCP: &dynamic.TCPConfiguration{
-			Routers:  map[string]*dynamic.TCPRouter{},
-			Services: map[string]*dynamic.TCPService{},
+			Routers:     map[string]*dynamic.TCPRouter{},
+			Middlewares: map[string]*dynamic.TCPMiddleware{},
+			Services:    map[string]*dynamic.TCPService{},
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Options: map[string]tls.Options{
@@ -9,9 +9,11 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/containous/traefik/v2/pkg/middlewares/auth"
 	"github.com/containous/traefik/v2/pkg/middlewares/buffering"
+	"github.com/containous/traefik/v2/pkg/middlewares/cache"
 	"github.com/containous/traefik/v2/pkg/middlewares/chain"
 	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/containous/traefik/v2/pkg/middlewares/compress"
@@ -24,6 +26,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/middlewares/redirect"
 	"github.com/containous/traefik/v2/pkg/middlewares/replacepath"
 	"github.com/containous/traefik/v2/pkg/middlewares/replacepathregex"
+	"github.com/containous/traefik/v2/pkg/middlewares/requestid"
 	"github.com/containous/traefik/v2/pkg/middlewares/retry"
 	"github.com/containous/traefik/v2/pkg/middlewares/stripprefix"
 	"github.com/containous/traefik/v2/pkg/middlewares/stripprefixregex"
@@ -41,6 +44,7 @@ const (
 type Builder struct {
 	configs        map[string]*runtime.MiddlewareInfo
 	serviceBuilder serviceBuilder
+	redisClients   *ratelimiter.RedisClients
 }
 
 type serviceBuilder interface {
@@ -48,8 +52,8 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder}
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, redisClients *ratelimiter.RedisClients) *Builder {
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, redisClients: redisClients}
 }
 
 // BuildChain creates a middleware chain
@@ -136,6 +140,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Cache
+	if config.Cache != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cache.New(ctx, next, *config.Cache, middlewareName)
+		}
+	}
+
 	// Chain
 	if config.Chain != nil {
 		if middleware != nil {
@@ -247,6 +261,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// JWT
+	if config.JWT != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewJWT(ctx, next, *config.JWT, middlewareName)
+		}
+	}
+
+	// OIDC
+	if config.OIDC != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewOIDC(ctx, next, *config.OIDC, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -263,7 +297,7 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return ratelimiter.New(ctx, next, *config.RateLimit, middlewareName)
+			return ratelimiter.New(ctx, next, *config.RateLimit, b.serviceBuilder, b.redisClients, middlewareName)
 		}
 	}
 
@@ -307,14 +341,24 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// RequestID
+	if config.RequestID != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return requestid.New(ctx, next, *config.RequestID, middlewareName)
+		}
+	}
+
 	// Retry
 	if config.Retry != nil {
 		if middleware != nil {
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			// FIXME missing metrics / accessLog
-			return retry.New(ctx, next, *config.Retry, retry.Listeners{}, middlewareName)
+			// FIXME missing metrics
+			return retry.New(ctx, next, *config.Retry, retry.Listeners{&accesslog.SaveRetries{}}, middlewareName)
 		}
 	}
 
@@ -0,0 +1,100 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/middlewares/tcp/inflightconn"
+	"github.com/containous/traefik/v2/pkg/middlewares/tcp/ipwhitelist"
+	"github.com/containous/traefik/v2/pkg/middlewares/tcp/ratelimiter"
+	"github.com/containous/traefik/v2/pkg/server/provider"
+	"github.com/containous/traefik/v2/pkg/tcp"
+)
+
+// Builder the middleware builder.
+type Builder struct {
+	configs map[string]*runtime.TCPMiddlewareInfo
+}
+
+// NewBuilder creates a new Builder.
+func NewBuilder(configs map[string]*runtime.TCPMiddlewareInfo) *Builder {
+	return &Builder{configs: configs}
+}
+
+// BuildChain creates a middleware chain.
+func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *tcp.Chain {
+	chain := tcp.NewChain()
+
+	for _, name := range middlewares {
+		middlewareName := provider.GetQualifiedName(ctx, name)
+
+		chain = chain.Append(func(next tcp.Handler) (tcp.Handler, error) {
+			constructorContext := provider.AddInContext(ctx, middlewareName)
+			if midInf, ok := b.configs[middlewareName]; !ok || midInf.TCPMiddleware == nil {
+				return nil, fmt.Errorf("middleware %q does not exist", middlewareName)
+			}
+
+			constructor, err := b.buildConstructor(constructorContext, middlewareName)
+			if err != nil {
+				b.configs[middlewareName].AddError(err, true)
+				return nil, err
+			}
+
+			handler, err := constructor(next)
+			if err != nil {
+				b.configs[middlewareName].AddError(err, true)
+				return nil, err
+			}
+
+			return handler, nil
+		})
+	}
+
+	return &chain
+}
+
+// it is the responsibility of the caller to make sure that b.configs[middlewareName].TCPMiddleware exists.
+func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (tcp.Constructor, error) {
+	config := b.configs[middlewareName]
+	if config == nil || config.TCPMiddleware == nil {
+		return nil, fmt.Errorf("invalid middleware %q configuration", middlewareName)
+	}
+
+	var middleware tcp.Constructor
+	badConf := errors.New("cannot create middleware: multi-types middleware not supported, consider declaring two different pieces of middleware instead")
+
+	// InFlightConn
+	if config.InFlightConn != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return inflightconn.New(ctx, next, *config.InFlightConn, middlewareName)
+		}
+	}
+
+	// IPWhiteList
+	if config.IPWhiteList != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return ipwhitelist.New(ctx, next, *config.IPWhiteList, middlewareName)
+		}
+	}
+
+	// RateLimit
+	if config.RateLimit != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return ratelimiter.New(ctx, next, *config.RateLimit, middlewareName)
+		}
+	}
+
+	if middleware == nil {
+		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
+	}
+
+	return middleware, nil
+}
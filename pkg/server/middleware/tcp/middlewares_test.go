@@ -0,0 +1,181 @@
+package tcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/server/provider"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_BuildChainNilConfig(t *testing.T) {
+	testConfig := map[string]*runtime.TCPMiddlewareInfo{
+		"empty": {},
+	}
+	middlewaresBuilder := NewBuilder(testConfig)
+
+	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
+	_, err := chain.Then(tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+	require.Error(t, err)
+}
+
+func TestBuilder_BuildChainWithContext(t *testing.T) {
+	testConfig := map[string]*runtime.TCPMiddlewareInfo{
+		"allowlist@provider-1": {
+			TCPMiddleware: &dynamic.TCPMiddleware{
+				IPWhiteList: &dynamic.TCPIPWhiteList{
+					SourceRange: []string{"10.0.0.0/8"},
+				},
+			},
+		},
+		"conns@provider-2": {
+			TCPMiddleware: &dynamic.TCPMiddleware{
+				InFlightConn: &dynamic.TCPInFlightConn{
+					Amount: 1,
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc            string
+		contextProvider string
+		middlewares     []string
+		expectedError   bool
+	}{
+		{
+			desc:            "Middleware from the context provider",
+			contextProvider: "provider-1",
+			middlewares:     []string{"allowlist"},
+		},
+		{
+			desc:            "Middlewares with their provider",
+			contextProvider: "provider-1",
+			middlewares:     []string{"allowlist", "conns@provider-2"},
+		},
+		{
+			desc:            "Middleware from another provider without its provider",
+			contextProvider: "provider-1",
+			middlewares:     []string{"conns"},
+			expectedError:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := provider.AddInContext(context.Background(), "foobar@"+test.contextProvider)
+
+			builder := NewBuilder(testConfig)
+			handler, err := builder.BuildChain(ctx, test.middlewares).Then(tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestBuilder_buildConstructor(t *testing.T) {
+	testConfig := map[string]*dynamic.TCPMiddleware{
+		"allowlist-empty": {
+			IPWhiteList: &dynamic.TCPIPWhiteList{},
+		},
+		"allowlist-foo": {
+			IPWhiteList: &dynamic.TCPIPWhiteList{
+				SourceRange: []string{"1.1.1.1"},
+			},
+		},
+		"conns-zero": {
+			InFlightConn: &dynamic.TCPInFlightConn{},
+		},
+		"ratelimit-foo": {
+			RateLimit: &dynamic.TCPRateLimit{
+				Average: 10,
+				Burst:   20,
+			},
+		},
+		"multi-types": {
+			IPWhiteList: &dynamic.TCPIPWhiteList{
+				SourceRange: []string{"1.1.1.1"},
+			},
+			InFlightConn: &dynamic.TCPInFlightConn{
+				Amount: 1,
+			},
+		},
+		"no-type": {},
+	}
+
+	rtConf := runtime.NewConfig(dynamic.Configuration{
+		TCP: &dynamic.TCPConfiguration{
+			Middlewares: testConfig,
+		},
+	})
+	middlewaresBuilder := NewBuilder(rtConf.TCPMiddlewares)
+
+	testCases := []struct {
+		desc                     string
+		middlewareID             string
+		expectedConstructorError bool
+		expectedError            bool
+	}{
+		{
+			desc:          "Should not create an IPWhiteList middleware without source range",
+			middlewareID:  "allowlist-empty",
+			expectedError: true,
+		},
+		{
+			desc:         "Should create an IPWhiteList middleware",
+			middlewareID: "allowlist-foo",
+		},
+		{
+			desc:          "Should not create an InFlightConn middleware without amount",
+			middlewareID:  "conns-zero",
+			expectedError: true,
+		},
+		{
+			desc:         "Should create a RateLimit middleware",
+			middlewareID: "ratelimit-foo",
+		},
+		{
+			desc:                     "Should not create a middleware with multiple types",
+			middlewareID:             "multi-types",
+			expectedConstructorError: true,
+		},
+		{
+			desc:                     "Should not create a middleware without type",
+			middlewareID:             "no-type",
+			expectedConstructorError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			constructor, err := middlewaresBuilder.buildConstructor(context.Background(), test.middlewareID)
+			if test.expectedConstructorError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			middleware, err := constructor(tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, middleware)
+		})
+	}
+}
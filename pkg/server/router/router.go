@@ -7,6 +7,7 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/v2/pkg/middlewares/recovery"
@@ -41,6 +42,7 @@ type Manager struct {
 	chainBuilder       *middleware.ChainBuilder
 	modifierBuilder    responseModifierBuilder
 	conf               *runtime.Configuration
+	entryPoints        static.EntryPoints
 }
 
 // NewManager Creates a new Manager
@@ -49,6 +51,7 @@ func NewManager(conf *runtime.Configuration,
 	middlewaresBuilder middlewareBuilder,
 	modifierBuilder responseModifierBuilder,
 	chainBuilder *middleware.ChainBuilder,
+	entryPoints static.EntryPoints,
 ) *Manager {
 	return &Manager{
 		routerHandlers:     make(map[string]http.Handler),
@@ -57,6 +60,7 @@ func NewManager(conf *runtime.Configuration,
 		modifierBuilder:    modifierBuilder,
 		chainBuilder:       chainBuilder,
 		conf:               conf,
+		entryPoints:        entryPoints,
 	}
 }
 
@@ -76,7 +80,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 		entryPointName := entryPointName
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		handler, err := m.buildEntryPointHandler(ctx, routers)
+		handler, err := m.buildEntryPointHandler(ctx, entryPointName, routers)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -112,8 +116,8 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.RouterInfo) (http.Handler, error) {
-	router, err := rules.NewRouter()
+func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName string, configs map[string]*runtime.RouterInfo) (http.Handler, error) {
+	router, err := m.newRouter(entryPointName)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +151,17 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 	return chain.Then(router)
 }
 
+// newRouter creates the rules router of the given entry point,
+// whose ClientIP matcher trusts the forwarded headers in the same way as the entry point.
+func (m *Manager) newRouter(entryPointName string) (*rules.Router, error) {
+	entryPoint, ok := m.entryPoints[entryPointName]
+	if !ok || entryPoint == nil || entryPoint.ForwardedHeaders == nil {
+		return rules.NewRouter()
+	}
+
+	return rules.NewForwardedRouter(entryPoint.ForwardedHeaders.Insecure, entryPoint.ForwardedHeaders.TrustedIPs)
+}
+
 func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, routerConfig *runtime.RouterInfo) (http.Handler, error) {
 	if handler, ok := m.routerHandlers[routerName]; ok {
 		return handler, nil
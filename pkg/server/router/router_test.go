@@ -308,12 +308,12 @@ func TestRouterManager_Get(t *testing.T) {
 				},
 			})
 
-			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
 			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil)
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -411,12 +411,12 @@ func TestAccessLog(t *testing.T) {
 				},
 			})
 
-			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
 			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil)
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -699,12 +699,12 @@ func TestRuntimeConfiguration(t *testing.T) {
 				},
 			})
 
-			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(map[string]*runtime.MiddlewareInfo{})
 			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -771,12 +771,12 @@ func TestProviderOnMiddlewares(t *testing.T) {
 		},
 	})
 
-	serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+	serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 	responseModifierFactory := responsemodifiers.NewBuilder(map[string]*runtime.MiddlewareInfo{})
 	chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil)
 
 	_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -786,6 +786,88 @@ func TestProviderOnMiddlewares(t *testing.T) {
 	assert.Equal(t, []string{"m1@docker", "m2@docker", "m1@file"}, rtConf.Middlewares["chain@docker"].Chain.Middlewares)
 }
 
+func TestManager_BuildHandlers_ClientIPForwardedHeaders(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		forwardedHeaders *static.ForwardedHeaders
+		remoteAddr       string
+		xForwardedFor    string
+		expected         int
+	}{
+		{
+			desc:             "X-Forwarded-For from a trusted proxy",
+			forwardedHeaders: &static.ForwardedHeaders{TrustedIPs: []string{"10.0.0.0/8"}},
+			remoteAddr:       "10.0.0.1:4242",
+			xForwardedFor:    "192.168.1.1",
+			expected:         http.StatusOK,
+		},
+		{
+			desc:             "X-Forwarded-For from an untrusted source",
+			forwardedHeaders: &static.ForwardedHeaders{TrustedIPs: []string{"10.0.0.0/8"}},
+			remoteAddr:       "172.16.0.1:4242",
+			xForwardedFor:    "192.168.1.1",
+			expected:         http.StatusNotFound,
+		},
+		{
+			desc:          "X-Forwarded-For without forwarded headers configuration",
+			remoteAddr:    "10.0.0.1:4242",
+			xForwardedFor: "192.168.1.1",
+			expected:      http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			defer server.Close()
+
+			rtConf := runtime.NewConfig(dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Services: map[string]*dynamic.Service{
+						"foo-service": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{{URL: server.URL}},
+							},
+						},
+					},
+					Routers: map[string]*dynamic.Router{
+						"foo": {
+							EntryPoints: []string{"web"},
+							Service:     "foo-service",
+							Rule:        "ClientIP(`192.168.1.1`)",
+						},
+					},
+				},
+			})
+
+			entryPoints := static.EntryPoints{
+				"web": {ForwardedHeaders: test.forwardedHeaders},
+			}
+
+			serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+			responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
+			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, entryPoints)
+
+			handlers := routerManager.BuildHandlers(context.Background(), []string{"web"}, false)
+
+			w := httptest.NewRecorder()
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/", nil)
+			req.RemoteAddr = test.remoteAddr
+			req.Header.Set("X-Forwarded-For", test.xForwardedFor)
+
+			handlers["web"].ServeHTTP(w, req)
+
+			assert.Equal(t, test.expected, w.Code)
+		})
+	}
+}
+
 type staticTransport struct {
 	res *http.Response
 }
@@ -830,12 +912,12 @@ func BenchmarkRouterServe(b *testing.B) {
 		},
 	})
 
-	serviceManager := service.NewManager(rtConf.Services, &staticTransport{res}, nil, nil)
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+	serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(&staticTransport{res}), nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 	responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
 	chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil)
 
 	handlers := routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -873,7 +955,7 @@ func BenchmarkService(b *testing.B) {
 		},
 	})
 
-	serviceManager := service.NewManager(rtConf.Services, &staticTransport{res}, nil, nil)
+	serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(&staticTransport{res}), nil, nil)
 	w := httptest.NewRecorder()
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/", nil)
 
@@ -21,29 +21,36 @@ const (
 	defaultTLSStoreName  = "default"
 )
 
+type middlewareBuilder interface {
+	BuildChain(ctx context.Context, names []string) *tcp.Chain
+}
+
 // NewManager Creates a new Manager
 func NewManager(conf *runtime.Configuration,
 	serviceManager *tcpservice.Manager,
+	middlewaresBuilder middlewareBuilder,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		httpHandlers:   httpHandlers,
-		httpsHandlers:  httpsHandlers,
-		tlsManager:     tlsManager,
-		conf:           conf,
+		serviceManager:     serviceManager,
+		middlewaresBuilder: middlewaresBuilder,
+		httpHandlers:       httpHandlers,
+		httpsHandlers:      httpsHandlers,
+		tlsManager:         tlsManager,
+		conf:               conf,
 	}
 }
 
 // Manager is a route/router manager
 type Manager struct {
-	serviceManager *tcpservice.Manager
-	httpHandlers   map[string]http.Handler
-	httpsHandlers  map[string]http.Handler
-	tlsManager     *traefiktls.Manager
-	conf           *runtime.Configuration
+	serviceManager     *tcpservice.Manager
+	middlewaresBuilder middlewareBuilder
+	httpHandlers       map[string]http.Handler
+	httpsHandlers      map[string]http.Handler
+	tlsManager         *traefiktls.Manager
+	conf               *runtime.Configuration
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -197,7 +204,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		handler, err := m.serviceManager.BuildTCP(ctxRouter, routerConfig.Service)
+		handler, err := m.buildTCPHandler(ctxRouter, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
@@ -212,6 +219,22 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
+		hostSNIOnly, err := rules.IsHostSNIOnly(routerConfig.Rule)
+		if err != nil {
+			routerConfig.AddError(err, true)
+			logger.Error(err)
+			continue
+		}
+
+		if !hostSNIOnly {
+			err := m.addRouteMatcher(ctxRouter, router, routerConfig, domains, handler)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Error(err)
+			}
+			continue
+		}
+
 		for _, domain := range domains {
 			logger.Debugf("Adding route %s on TCP", domain)
 			switch {
@@ -248,3 +271,65 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 	return router, nil
 }
+
+func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
+	var qualifiedNames []string
+	for _, name := range router.Middlewares {
+		qualifiedNames = append(qualifiedNames, provider.GetQualifiedName(ctx, name))
+	}
+	router.Middlewares = qualifiedNames
+
+	sHandler, err := m.serviceManager.BuildTCP(ctx, router.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
+
+	return tcp.NewChain().Extend(*mHandler).Then(sHandler)
+}
+
+// addRouteMatcher adds a route for a router whose rule is not only made of HostSNI matchers,
+// e.g. with ClientIP or ALPN matchers, which are evaluated for each connection.
+func (m *Manager) addRouteMatcher(ctx context.Context, router *tcp.Router, routerConfig *runtime.TCPRouterInfo, domains []string, handler tcp.Handler) error {
+	matcher, err := rules.NewTCPMatcher(routerConfig.Rule)
+	if err != nil {
+		return err
+	}
+
+	priority := len(routerConfig.Rule)
+
+	log.FromContext(ctx).Debugf("Adding route %s on TCP", routerConfig.Rule)
+
+	switch {
+	case routerConfig.TLS == nil:
+		for _, domain := range domains {
+			if domain != "*" {
+				return errors.New("cannot specify a HostSNI rule without TLS, except HostSNI(`*`)")
+			}
+		}
+
+		router.AddRouteMatcherNoTLS(matcher, priority, handler)
+	case routerConfig.TLS.Passthrough:
+		router.AddRouteMatcher(matcher, priority, handler)
+	default:
+		tlsOptionsName := routerConfig.TLS.Options
+
+		if len(tlsOptionsName) == 0 {
+			tlsOptionsName = defaultTLSConfigName
+		}
+
+		if tlsOptionsName != defaultTLSConfigName {
+			tlsOptionsName = provider.GetQualifiedName(ctx, tlsOptionsName)
+		}
+
+		tlsConf, err := m.tlsManager.Get(defaultTLSStoreName, tlsOptionsName)
+		if err != nil {
+			return err
+		}
+
+		router.AddRouteMatcherTLS(matcher, priority, handler, tlsConf)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	tcpmiddleware "github.com/containous/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/containous/traefik/v2/pkg/server/service/tcp"
 	"github.com/containous/traefik/v2/pkg/tls"
 	"github.com/stretchr/testify/assert"
@@ -13,10 +14,11 @@ import (
 
 func TestRuntimeConfiguration(t *testing.T) {
 	testCases := []struct {
-		desc          string
-		serviceConfig map[string]*runtime.TCPServiceInfo
-		routerConfig  map[string]*runtime.TCPRouterInfo
-		expectedError int
+		desc             string
+		serviceConfig    map[string]*runtime.TCPServiceInfo
+		routerConfig     map[string]*runtime.TCPRouterInfo
+		middlewareConfig map[string]*runtime.TCPMiddlewareInfo
+		expectedError    int
 	}{
 		{
 			desc: "No error",
@@ -65,6 +67,156 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 0,
 		},
+		{
+			desc: "Routers with ClientIP and ALPN matchers",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`) && ClientIP(`10.0.0.0/8`)",
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`foo.bar`) && ALPN(`h2`)",
+						TLS: &dynamic.RouterTCPTLSConfig{
+							Passthrough: true,
+						},
+					},
+				},
+				"baz": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "ALPN(`http/1.1`)",
+						TLS: &dynamic.RouterTCPTLSConfig{
+							Options: "foo",
+						},
+					},
+				},
+			},
+			expectedError: 0,
+		},
+		{
+			desc: "Router with a HostSNI and ClientIP rule without TLS",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`foo.bar`) && ClientIP(`10.0.0.0/8`)",
+					},
+				},
+			},
+			expectedError: 1,
+		},
+		{
+			desc: "Router with middlewares",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			middlewareConfig: map[string]*runtime.TCPMiddlewareInfo{
+				"allowlist": {
+					TCPMiddleware: &dynamic.TCPMiddleware{
+						IPWhiteList: &dynamic.TCPIPWhiteList{
+							SourceRange: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`)",
+						Middlewares: []string{"allowlist"},
+					},
+				},
+			},
+			expectedError: 0,
+		},
+		{
+			desc: "Router with a broken middleware",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			middlewareConfig: map[string]*runtime.TCPMiddlewareInfo{
+				"allowlist": {
+					TCPMiddleware: &dynamic.TCPMiddleware{
+						IPWhiteList: &dynamic.TCPIPWhiteList{},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`)",
+						Middlewares: []string{"allowlist"},
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`)",
+						Middlewares: []string{"unknown"},
+					},
+				},
+			},
+			expectedError: 3,
+		},
 		{
 			desc: "One router with wrong rule",
 			serviceConfig: map[string]*runtime.TCPServiceInfo{
@@ -197,8 +349,9 @@ func TestRuntimeConfiguration(t *testing.T) {
 			entryPoints := []string{"web"}
 
 			conf := &runtime.Configuration{
-				TCPServices: test.serviceConfig,
-				TCPRouters:  test.routerConfig,
+				TCPServices:    test.serviceConfig,
+				TCPRouters:     test.routerConfig,
+				TCPMiddlewares: test.middlewareConfig,
 			}
 			serviceManager := tcp.NewManager(conf)
 			tlsManager := tls.NewManager()
@@ -218,7 +371,9 @@ func TestRuntimeConfiguration(t *testing.T) {
 				},
 				[]*tls.CertAndStores{})
 
-			routerManager := NewManager(conf, serviceManager,
+			middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
+
+			routerManager := NewManager(conf, serviceManager, middlewaresBuilder,
 				nil, nil, tlsManager)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
@@ -237,6 +392,11 @@ func TestRuntimeConfiguration(t *testing.T) {
 					allErrors++
 				}
 			}
+			for _, v := range conf.TCPMiddlewares {
+				if len(v.Err) > 0 {
+					allErrors++
+				}
+			}
 			assert.Equal(t, test.expectedError, allErrors)
 		})
 	}
@@ -7,8 +7,10 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares/ratelimiter"
 	"github.com/containous/traefik/v2/pkg/responsemodifiers"
 	"github.com/containous/traefik/v2/pkg/server/middleware"
+	middlewaretcp "github.com/containous/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/containous/traefik/v2/pkg/server/router"
 	routertcp "github.com/containous/traefik/v2/pkg/server/router/tcp"
 	routerudp "github.com/containous/traefik/v2/pkg/server/router/udp"
@@ -24,8 +26,10 @@ import (
 type RouterFactory struct {
 	entryPointsTCP []string
 	entryPointsUDP []string
+	entryPoints    static.EntryPoints
 
 	managerFactory *service.ManagerFactory
+	redisClients   *ratelimiter.RedisClients
 
 	chainBuilder *middleware.ChainBuilder
 	tlsManager   *tls.Manager
@@ -51,7 +55,9 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 	return &RouterFactory{
 		entryPointsTCP: entryPointsTCP,
 		entryPointsUDP: entryPointsUDP,
+		entryPoints:    staticConfiguration.EntryPoints,
 		managerFactory: managerFactory,
+		redisClients:   ratelimiter.NewRedisClients(),
 		tlsManager:     tlsManager,
 		chainBuilder:   chainBuilder,
 	}
@@ -64,12 +70,16 @@ func (f *RouterFactory) CreateRouters(conf dynamic.Configuration) (map[string]*t
 	rtConf := runtime.NewConfig(conf)
 
 	// HTTP
+	if conf.HTTP != nil {
+		f.managerFactory.UpdateServersTransports(conf.HTTP.ServersTransports)
+	}
+
 	serviceManager := f.managerFactory.Build(rtConf)
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.redisClients)
 	responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
 
-	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, f.chainBuilder)
+	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, f.chainBuilder, f.entryPoints)
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
@@ -79,15 +89,27 @@ func (f *RouterFactory) CreateRouters(conf dynamic.Configuration) (map[string]*t
 	// TCP
 	svcTCPManager := tcp.NewManager(rtConf)
 
-	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, handlersNonTLS, handlersTLS, f.tlsManager)
+	middlewaresTCPBuilder := middlewaretcp.NewBuilder(rtConf.TCPMiddlewares)
+
+	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
+	svcTCPManager.LaunchHealthCheck()
+
 	// UDP
 	svcUDPManager := udp.NewManager(rtConf)
 	rtUDPManager := routerudp.NewManager(rtConf, svcUDPManager)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
+	svcUDPManager.LaunchHealthCheck()
+
 	rtConf.PopulateUsedBy()
 
 	return routersTCP, routersUDP
 }
+
+// CloseUnusedClients closes the clients, shared across configurations, which are not used by the last created routers.
+// It is meant to be called once these routers replaced the previous ones.
+func (f *RouterFactory) CloseUnusedClients() {
+	f.redisClients.CloseUnused()
+}
@@ -21,6 +21,9 @@ func TestShutdownUDPConn(t *testing.T) {
 				GraceTimeOut: types.Duration(5 * time.Second),
 			},
 		},
+		UDP: &static.UDPConfig{
+			Timeout: types.Duration(3 * time.Second),
+		},
 	})
 	require.NoError(t, err)
 
@@ -0,0 +1,132 @@
+package failover
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// Failover is an http.Handler that forwards the requests to its main handler,
+// or to its fallback handler when the main handler is down.
+type Failover struct {
+	handler         http.Handler
+	fallbackHandler http.Handler
+
+	mutex          sync.RWMutex
+	handlerStatus  bool
+	fallbackStatus bool
+	updaters       []func(up bool)
+}
+
+// New creates a new Failover handler.
+// Both handlers are considered up until their status is set otherwise.
+func New() *Failover {
+	return &Failover{
+		handlerStatus:  true,
+		fallbackStatus: true,
+	}
+}
+
+// SetHandler sets the main handler.
+func (f *Failover) SetHandler(handler http.Handler) {
+	f.handler = handler
+}
+
+// SetFallbackHandler sets the fallback handler.
+func (f *Failover) SetFallbackHandler(handler http.Handler) {
+	f.fallbackHandler = handler
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the status of the Failover changes,
+// i.e. when it goes down because both of its handlers are down, or back up.
+func (f *Failover) RegisterStatusUpdater(fn func(up bool)) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.updaters = append(f.updaters, fn)
+	if !f.up() {
+		fn(false)
+	}
+
+	return nil
+}
+
+// SetHandlerStatus sets the status (UP or DOWN) of the main handler.
+func (f *Failover) SetHandlerStatus(ctx context.Context, up bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.handlerStatus == up {
+		return
+	}
+
+	wasUp := f.up()
+	f.handlerStatus = up
+
+	if up {
+		log.FromContext(ctx).Info("Failover: main service is up, switching back to it")
+	} else {
+		log.FromContext(ctx).Warn("Failover: main service is down, switching to the fallback service")
+	}
+
+	f.propagate(wasUp)
+}
+
+// SetFallbackHandlerStatus sets the status (UP or DOWN) of the fallback handler.
+func (f *Failover) SetFallbackHandlerStatus(ctx context.Context, up bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.fallbackStatus == up {
+		return
+	}
+
+	wasUp := f.up()
+	f.fallbackStatus = up
+
+	if up {
+		log.FromContext(ctx).Info("Failover: fallback service is up")
+	} else {
+		log.FromContext(ctx).Warn("Failover: fallback service is down")
+	}
+
+	f.propagate(wasUp)
+}
+
+// up reports whether at least one of the handlers is up.
+func (f *Failover) up() bool {
+	return f.handlerStatus || (f.fallbackHandler != nil && f.fallbackStatus)
+}
+
+// propagate runs the registered hooks if the status of the Failover changed.
+func (f *Failover) propagate(wasUp bool) {
+	up := f.up()
+	if up == wasUp {
+		return
+	}
+
+	for _, fn := range f.updaters {
+		fn(up)
+	}
+}
+
+func (f *Failover) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f.mutex.RLock()
+	handlerStatus := f.handlerStatus
+	fallbackStatus := f.fallbackStatus
+	f.mutex.RUnlock()
+
+	if handlerStatus {
+		f.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	if f.fallbackHandler != nil && fallbackStatus {
+		f.fallbackHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
@@ -0,0 +1,363 @@
+package hedging
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/vulcand/oxy/utils"
+)
+
+const (
+	primaryAttempt = iota
+	hedgedAttempt
+)
+
+type attemptKey struct{}
+
+// attempt holds the server chosen by the load-balancer for the primary attempt of a request.
+type attempt struct {
+	mu     sync.Mutex
+	server *url.URL
+}
+
+func (a *attempt) setServer(u *url.URL) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.server = u
+}
+
+func (a *attempt) getServer() *url.URL {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.server
+}
+
+// Hedger is a load-balancer sending a second (hedged) request to another server,
+// when the server chosen for an idempotent request has not answered within a delay.
+// The response of the first server to answer is used, and the other request is cancelled.
+type Hedger struct {
+	healthcheck.BalancerHandler
+
+	name    string
+	delay   time.Duration
+	counter metrics.Counter // can be nil
+	fwd     http.Handler
+}
+
+// New returns a new Hedger.
+// Forwarder and SetBalancer must be called before the Hedger serves any request.
+func New(serviceName string, delay time.Duration, counter metrics.Counter) *Hedger {
+	return &Hedger{
+		name:    serviceName,
+		delay:   delay,
+		counter: counter,
+	}
+}
+
+// Forwarder returns the handler the load-balancer must forward the requests to.
+// It records the server chosen by the load-balancer, so that the hedged request is sent to another one.
+func (h *Hedger) Forwarder(fwd http.Handler) http.Handler {
+	h.fwd = fwd
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if a, ok := req.Context().Value(attemptKey{}).(*attempt); ok {
+			a.setServer(req.URL)
+		}
+
+		fwd.ServeHTTP(rw, req)
+	})
+}
+
+// SetBalancer sets the load-balancer choosing the server of the primary requests.
+func (h *Hedger) SetBalancer(lb healthcheck.BalancerHandler) {
+	h.BalancerHandler = lb
+}
+
+// RegisterStatusUpdater registers fn with the load-balancer, if it propagates its status.
+func (h *Hedger) RegisterStatusUpdater(fn func(up bool)) error {
+	statusUpdater, ok := h.BalancerHandler.(healthcheck.StatusUpdater)
+	if !ok {
+		return errors.New("the load-balancer does not propagate its status")
+	}
+	return statusUpdater.RegisterStatusUpdater(fn)
+}
+
+func (h *Hedger) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !middlewares.IsIdempotent(req.Method) || middlewares.HasBody(req) || isUpgrade(req) {
+		h.BalancerHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	r := newRace(rw)
+
+	primary := &attempt{}
+	primaryReq := req.WithContext(context.WithValue(req.Context(), attemptKey{}, primary))
+	r.start(primaryAttempt, primaryReq, h.BalancerHandler)
+
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+
+	var hedgedReq *http.Request
+	select {
+	case <-timer.C:
+		hedgedReq = h.hedge(r, req, primary.getServer())
+	case <-r.answered:
+	case <-r.done[primaryAttempt]:
+	case <-req.Context().Done():
+	}
+
+	r.wait()
+
+	if hedgedReq != nil && r.winner == hedgedAttempt {
+		// The access log reports the server which actually answered.
+		if table := accesslog.GetLogData(req); table != nil {
+			table.Core[accesslog.ServiceURL] = hedgedReq.URL
+			table.Core[accesslog.ServiceAddr] = hedgedReq.URL.Host
+		}
+	}
+
+	if p := r.panicked(); p != nil {
+		panic(p)
+	}
+}
+
+// hedge sends the hedged request to another server than the given one,
+// and returns it, or nil if there is no other server.
+func (h *Hedger) hedge(r *race, req *http.Request, primaryServer *url.URL) *http.Request {
+	server := h.pickServer(primaryServer)
+	if server == nil {
+		return nil
+	}
+
+	log.FromContext(req.Context()).Debugf("Service %s: no answer after %s, sending a hedged request to %s", h.name, h.delay, server)
+
+	if h.counter != nil {
+		h.counter.Add(1)
+	}
+
+	// The hedged request must not write the access log data of the primary one concurrently.
+	hedgedReq := req.Clone(context.WithValue(req.Context(), accesslog.DataTableKey, nil))
+	hedgedReq.URL = utils.CopyURL(server)
+
+	tracing.LogEventf(req, "Hedged request sent to %s", server)
+
+	tr, err := tracing.FromContext(req.Context())
+	if err != nil {
+		r.start(hedgedAttempt, hedgedReq, h.fwd)
+		return hedgedReq
+	}
+
+	span, spanReq, finish := tr.StartSpanf(hedgedReq, ext.SpanKindRPCClientEnum, "hedge", []string{h.name}, "/")
+	span.SetTag("service.name", h.name)
+	ext.HTTPMethod.Set(span, spanReq.Method)
+	ext.HTTPUrl.Set(span, spanReq.URL.String())
+	span.SetTag("http.host", spanReq.Host)
+
+	tracing.InjectRequestHeaders(spanReq)
+
+	r.start(hedgedAttempt, spanReq, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		defer finish()
+
+		h.fwd.ServeHTTP(rw, req)
+
+		if aw, ok := rw.(*attemptWriter); ok && aw.won {
+			tracing.LogResponseCode(span, aw.code)
+			return
+		}
+		span.SetTag("hedge.cancelled", true)
+	}))
+
+	return hedgedReq
+}
+
+// pickServer returns a random server of the load-balancer other than the given one.
+func (h *Hedger) pickServer(exclude *url.URL) *url.URL {
+	var candidates []*url.URL
+	for _, u := range h.Servers() {
+		if exclude == nil || serverKey(u) != serverKey(exclude) {
+			candidates = append(candidates, u)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	return candidates[rand.Intn(len(candidates))]
+}
+
+// race runs the attempts of a request concurrently.
+// The first attempt writing its response headers wins: its response is forwarded to the client,
+// and the other attempt is cancelled.
+type race struct {
+	rw       http.ResponseWriter
+	answered chan struct{}
+	done     [2]chan struct{}
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	winner  int
+	cancels [2]context.CancelFunc
+	panics  [2]interface{}
+}
+
+func newRace(rw http.ResponseWriter) *race {
+	return &race{
+		rw:       rw,
+		answered: make(chan struct{}),
+		done:     [2]chan struct{}{make(chan struct{}), make(chan struct{})},
+		winner:   -1,
+	}
+}
+
+// start serves the request with next in a new goroutine, as the given attempt.
+func (r *race) start(id int, req *http.Request, next http.Handler) {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	r.mu.Lock()
+	r.cancels[id] = cancel
+	lost := r.winner != -1
+	r.mu.Unlock()
+
+	if lost {
+		cancel()
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer close(r.done[id])
+		defer cancel()
+		defer func() {
+			if p := recover(); p != nil {
+				r.mu.Lock()
+				r.panics[id] = p
+				r.mu.Unlock()
+			}
+		}()
+
+		next.ServeHTTP(&attemptWriter{race: r, id: id, header: make(http.Header)}, req.WithContext(ctx))
+	}()
+}
+
+// claim makes the given attempt the winner if there is none yet, cancels the other attempt,
+// and reports whether the given attempt is the winner.
+func (r *race) claim(id int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.winner == -1 {
+		r.winner = id
+		close(r.answered)
+
+		for other, cancel := range r.cancels {
+			if other != id && cancel != nil {
+				cancel()
+			}
+		}
+	}
+
+	return r.winner == id
+}
+
+// wait waits for all the attempts to end.
+func (r *race) wait() {
+	r.wg.Wait()
+}
+
+// panicked returns the value the winning attempt panicked with, if any, to be propagated.
+// The panics of a cancelled attempt (e.g. http.ErrAbortHandler) are ignored.
+// Without winner, the panic of any attempt is returned.
+func (r *race) panicked() interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.winner != -1 {
+		return r.panics[r.winner]
+	}
+
+	for _, p := range r.panics {
+		if p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// attemptWriter is the response writer of an attempt.
+// It forwards the response to the client only if the attempt wins the race, and discards it otherwise.
+type attemptWriter struct {
+	race   *race
+	id     int
+	header http.Header
+	code   int
+	won    bool
+	lost   bool
+}
+
+func (w *attemptWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *attemptWriter) WriteHeader(code int) {
+	if w.won || w.lost {
+		return
+	}
+
+	if !w.race.claim(w.id) {
+		w.lost = true
+		return
+	}
+
+	w.won = true
+	w.code = code
+
+	utils.CopyHeaders(w.race.rw.Header(), w.header)
+	w.race.rw.WriteHeader(code)
+}
+
+func (w *attemptWriter) Write(b []byte) (int, error) {
+	if !w.won && !w.lost {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.lost {
+		return len(b), nil
+	}
+
+	return w.race.rw.Write(b)
+}
+
+// Flush sends any buffered data to the client, if the attempt won the race.
+func (w *attemptWriter) Flush() {
+	if !w.won {
+		return
+	}
+
+	if f, ok := w.race.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// isUpgrade reports whether the given request asks for a protocol upgrade, e.g. a WebSocket.
+func isUpgrade(req *http.Request) bool {
+	return strings.Contains(strings.ToLower(req.Header.Get("Connection")), "upgrade")
+}
+
+// serverKey returns the key identifying a server, the way the load-balancers do.
+func serverKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
@@ -0,0 +1,174 @@
+package hedging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestHedger(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		method         string
+		body           string
+		servers        []string
+		primaryDelay   time.Duration
+		expectedServer string
+		expectedHedged float64
+	}{
+		{
+			desc:           "primary answers within the delay",
+			method:         http.MethodGet,
+			servers:        []string{"http://a", "http://b"},
+			expectedServer: "a",
+		},
+		{
+			desc:           "primary too slow",
+			method:         http.MethodGet,
+			servers:        []string{"http://a", "http://b"},
+			primaryDelay:   time.Second,
+			expectedServer: "b",
+			expectedHedged: 1,
+		},
+		{
+			desc:           "no other server",
+			method:         http.MethodGet,
+			servers:        []string{"http://a"},
+			primaryDelay:   100 * time.Millisecond,
+			expectedServer: "a",
+		},
+		{
+			desc:           "non idempotent request",
+			method:         http.MethodPost,
+			servers:        []string{"http://a", "http://b"},
+			primaryDelay:   100 * time.Millisecond,
+			expectedServer: "a",
+		},
+		{
+			desc:           "request with body",
+			method:         http.MethodPut,
+			body:           "data",
+			servers:        []string{"http://a", "http://b"},
+			primaryDelay:   100 * time.Millisecond,
+			expectedServer: "a",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			cancelled := make(map[string]bool)
+
+			fwd := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Host == "a" && test.primaryDelay > 0 {
+					select {
+					case <-time.After(test.primaryDelay):
+					case <-req.Context().Done():
+						mu.Lock()
+						cancelled[req.URL.Host] = true
+						mu.Unlock()
+						rw.WriteHeader(499)
+						return
+					}
+				}
+
+				rw.Header().Set("X-Server", req.URL.Host)
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write([]byte(req.URL.Host))
+			})
+
+			counter := &lockedCounter{}
+			hedger := New("service", 20*time.Millisecond, counter)
+			lb := &testBalancer{next: hedger.Forwarder(fwd)}
+			for _, s := range test.servers {
+				lb.servers = append(lb.servers, testhelpers.MustParseURL(s))
+			}
+			hedger.SetBalancer(lb)
+
+			recorder := httptest.NewRecorder()
+			hedger.ServeHTTP(recorder, httptest.NewRequest(test.method, "http://foo/bar", strings.NewReader(test.body)))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedServer, recorder.Header().Get("X-Server"))
+			assert.Equal(t, test.expectedServer, recorder.Body.String())
+			assert.Equal(t, test.expectedHedged, counter.value())
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, test.expectedHedged > 0, cancelled["a"])
+		})
+	}
+}
+
+func TestHedgerPanic(t *testing.T) {
+	hedger := New("service", 20*time.Millisecond, nil)
+	hedger.SetBalancer(&testBalancer{
+		servers: []*url.URL{testhelpers.MustParseURL("http://a")},
+		next: hedger.Forwarder(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+			panic(http.ErrAbortHandler)
+		})),
+	})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		hedger.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://foo/bar", nil))
+	})
+}
+
+// testBalancer is a load-balancer always choosing its first server.
+type testBalancer struct {
+	servers []*url.URL
+	next    http.Handler
+}
+
+func (b *testBalancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	newReq := *req
+	newReq.URL = b.servers[0]
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+func (b *testBalancer) Servers() []*url.URL {
+	return b.servers
+}
+
+func (b *testBalancer) RemoveServer(u *url.URL) error {
+	return nil
+}
+
+func (b *testBalancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	return nil
+}
+
+// lockedCounter is a metrics.Counter safe for concurrent use.
+type lockedCounter struct {
+	mu    sync.Mutex
+	count float64
+}
+
+func (c *lockedCounter) With(labelValues ...string) metrics.Counter {
+	return c
+}
+
+func (c *lockedCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count += delta
+}
+
+func (c *lockedCounter) value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
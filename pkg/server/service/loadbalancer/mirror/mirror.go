@@ -2,14 +2,19 @@ package mirror
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"sync"
 
+	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/go-kit/kit/metrics"
 )
 
 // Mirroring is an http.Handler that can mirror requests.
@@ -18,17 +23,24 @@ type Mirroring struct {
 	mirrorHandlers []*mirrorHandler
 	rw             http.ResponseWriter
 	routinePool    *safe.Pool
+	maxBodySize    int64
+	skippedCounter metrics.Counter // can be nil
 
 	lock  sync.RWMutex
 	total uint64
 }
 
 // New returns a new instance of *Mirroring.
-func New(handler http.Handler, pool *safe.Pool) *Mirroring {
+// The request bodies are buffered, so that they are sent to the mirrors as well,
+// up to maxBodySize bytes (a negative value means no limit).
+// The requests with a larger body are not mirrored, and counted with skippedCounter, if not nil.
+func New(handler http.Handler, pool *safe.Pool, maxBodySize int64, skippedCounter metrics.Counter) *Mirroring {
 	return &Mirroring{
-		routinePool: pool,
-		handler:     handler,
-		rw:          blackholeResponseWriter{},
+		routinePool:    pool,
+		handler:        handler,
+		rw:             blackholeResponseWriter{},
+		maxBodySize:    maxBodySize,
+		skippedCounter: skippedCounter,
 	}
 }
 
@@ -47,41 +59,74 @@ type mirrorHandler struct {
 	count uint64
 }
 
+func (m *Mirroring) getActiveMirrors() []http.Handler {
+	total := m.inc()
+
+	var mirrors []http.Handler
+	for _, handler := range m.mirrorHandlers {
+		handler.lock.Lock()
+		if handler.count*100 < total*uint64(handler.percent) {
+			handler.count++
+			handler.lock.Unlock()
+			mirrors = append(mirrors, handler)
+		} else {
+			handler.lock.Unlock()
+		}
+	}
+	return mirrors
+}
+
 func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	m.handler.ServeHTTP(rw, req)
+	mirrors := m.getActiveMirrors()
+	if len(mirrors) == 0 {
+		m.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := log.FromContext(req.Context())
+	rr, bytesRead, err := newReusableRequest(req, m.maxBodySize)
+	if err != nil && !errors.Is(err, errBodyTooLarge) {
+		logger.Errorf("Error while reading the request body for mirroring: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if errors.Is(err, errBodyTooLarge) {
+		req.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(bytesRead), req.Body))
+		m.handler.ServeHTTP(rw, req)
+		logger.Debug("No mirroring, request body larger than allowed size")
+		if m.skippedCounter != nil {
+			m.skippedCounter.Add(1)
+		}
+		return
+	}
+
+	m.handler.ServeHTTP(rw, rr.clone(req.Context()))
 
 	select {
 	case <-req.Context().Done():
 		// No mirroring if request has been canceled during main handler ServeHTTP
+		logger.Debug("No mirroring due to request cancellation")
 		return
 	default:
 	}
 
 	m.routinePool.GoCtx(func(_ context.Context) {
-		total := m.inc()
-		for _, handler := range m.mirrorHandlers {
-			handler.lock.Lock()
-			if handler.count*100 < total*uint64(handler.percent) {
-				handler.count++
-				handler.lock.Unlock()
-
-				// In ServeHTTP, we rely on the presence of the accesslog datatable found in the
-				// request's context to know whether we should mutate said datatable (and
-				// contribute some fields to the log). In this instance, we do not want the mirrors
-				// mutating (i.e. changing the service name in) the logs related to the mirrored
-				// server. Especially since it would result in unguarded concurrent reads/writes on
-				// the datatable. Therefore, we reset any potential datatable key in the new
-				// context that we pass around.
-				ctx := context.WithValue(req.Context(), accesslog.DataTableKey, nil)
-
-				// When a request served by m.handler is successful, req.Context will be canceled,
-				// which would trigger a cancellation of the ongoing mirrored requests.
-				// Therefore, we give a new, non-cancellable context  to each of the mirrored calls,
-				// so they can terminate by themselves.
-				handler.ServeHTTP(m.rw, req.WithContext(contextStopPropagation{ctx}))
-			} else {
-				handler.lock.Unlock()
-			}
+		for _, handler := range mirrors {
+			// In ServeHTTP, we rely on the presence of the accesslog datatable found in the
+			// request's context to know whether we should mutate said datatable (and
+			// contribute some fields to the log). In this instance, we do not want the mirrors
+			// mutating (i.e. changing the service name in) the logs related to the mirrored
+			// server. Especially since it would result in unguarded concurrent reads/writes on
+			// the datatable. Therefore, we reset any potential datatable key in the new
+			// context that we pass around.
+			ctx := context.WithValue(req.Context(), accesslog.DataTableKey, nil)
+
+			// When a request served by m.handler is successful, req.Context will be canceled,
+			// which would trigger a cancellation of the ongoing mirrored requests.
+			// Therefore, we give a new, non-cancellable context  to each of the mirrored calls,
+			// so they can terminate by themselves.
+			handler.ServeHTTP(m.rw, rr.clone(contextStopPropagation{ctx}))
 		}
 	})
 }
@@ -120,3 +165,65 @@ type contextStopPropagation struct {
 func (c contextStopPropagation) Done() <-chan struct{} {
 	return make(chan struct{})
 }
+
+// reusableRequest keeps in memory the body of the given request,
+// so that the request can be fully read several times.
+type reusableRequest struct {
+	req  *http.Request
+	body []byte
+}
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// if the returned error is errBodyTooLarge, newReusableRequest also returns the
+// bytes that were already consumed from the request's body.
+func newReusableRequest(req *http.Request, maxBodySize int64) (*reusableRequest, []byte, error) {
+	if req == nil {
+		return nil, nil, errors.New("nil input request")
+	}
+	if req.Body == nil || req.ContentLength == 0 {
+		return &reusableRequest{req: req}, nil, nil
+	}
+
+	// unbounded body size
+	if maxBodySize < 0 {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &reusableRequest{
+			req:  req,
+			body: body,
+		}, nil, nil
+	}
+
+	// we purposefully try to read _more_ than maxBodySize to detect whether
+	// the request body is larger than what we allow for the mirrors.
+	body := make([]byte, maxBodySize+1)
+	n, err := io.ReadFull(req.Body, body)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, nil, err
+	}
+
+	// we got an ErrUnexpectedEOF (or an EOF for an empty body), which means there was less than maxBodySize data to read,
+	// which permits us sending also to all the mirrors later.
+	if err != nil {
+		return &reusableRequest{
+			req:  req,
+			body: body[:n],
+		}, nil, nil
+	}
+
+	// If we got here, n == maxBodySize+1
+	return nil, body[:n], errBodyTooLarge
+}
+
+func (rr reusableRequest) clone(ctx context.Context) *http.Request {
+	req := rr.req.Clone(ctx)
+
+	if rr.body != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(rr.body))
+	}
+
+	return req
+}
@@ -1,23 +1,29 @@
 package mirror
 
 import (
+	"bytes"
 	"context"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"sync/atomic"
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+const defaultMaxBodySize int64 = -1
+
 func TestMirroringOn100(t *testing.T) {
 	var countMirror1, countMirror2 int32
 	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(http.StatusOK)
 	})
 	pool := safe.NewPool(context.Background())
-	mirror := New(handler, pool)
+	mirror := New(handler, pool, defaultMaxBodySize, nil)
 	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror1, 1)
 	}), 10)
@@ -46,7 +52,7 @@ func TestMirroringOn10(t *testing.T) {
 		rw.WriteHeader(http.StatusOK)
 	})
 	pool := safe.NewPool(context.Background())
-	mirror := New(handler, pool)
+	mirror := New(handler, pool, defaultMaxBodySize, nil)
 	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror1, 1)
 	}), 10)
@@ -70,7 +76,7 @@ func TestMirroringOn10(t *testing.T) {
 }
 
 func TestInvalidPercent(t *testing.T) {
-	mirror := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), safe.NewPool(context.Background()))
+	mirror := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), safe.NewPool(context.Background()), defaultMaxBodySize, nil)
 	err := mirror.AddMirror(nil, -1)
 	assert.Error(t, err)
 
@@ -89,7 +95,7 @@ func TestHijack(t *testing.T) {
 		rw.WriteHeader(http.StatusOK)
 	})
 	pool := safe.NewPool(context.Background())
-	mirror := New(handler, pool)
+	mirror := New(handler, pool, defaultMaxBodySize, nil)
 
 	var mirrorRequest bool
 	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -113,7 +119,7 @@ func TestFlush(t *testing.T) {
 		rw.WriteHeader(http.StatusOK)
 	})
 	pool := safe.NewPool(context.Background())
-	mirror := New(handler, pool)
+	mirror := New(handler, pool, defaultMaxBodySize, nil)
 
 	var mirrorRequest bool
 	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -131,3 +137,162 @@ func TestFlush(t *testing.T) {
 	pool.Stop()
 	assert.Equal(t, true, mirrorRequest)
 }
+
+func TestMirroringWithBody(t *testing.T) {
+	const numMirrors = 10
+
+	var (
+		countMirror int32
+		body        = []byte(`body`)
+	)
+
+	pool := safe.NewPool(context.Background())
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.NotNil(t, req.Body)
+		bb, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, bb)
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	mirror := New(handler, pool, defaultMaxBodySize, nil)
+
+	for i := 0; i < numMirrors; i++ {
+		err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.NotNil(t, req.Body)
+			bb, err := ioutil.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, bb)
+			atomic.AddInt32(&countMirror, 1)
+		}), 100)
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body))
+
+	mirror.ServeHTTP(httptest.NewRecorder(), req)
+
+	pool.Stop()
+
+	val := atomic.LoadInt32(&countMirror)
+	assert.Equal(t, numMirrors, int(val))
+}
+
+func TestMirroringWithTooLargeBody(t *testing.T) {
+	var countMirror int32
+	body := []byte(`a body larger than the limit`)
+
+	pool := safe.NewPool(context.Background())
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The main handler still receives the full body.
+		bb, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, bb)
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	skipped := &testhelpers.CollectingCounter{}
+	mirror := New(handler, pool, 8, skipped)
+
+	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&countMirror, 1)
+	}), 100)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	mirror.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body)))
+
+	pool.Stop()
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 0, int(atomic.LoadInt32(&countMirror)))
+	assert.Equal(t, float64(1), skipped.CounterValue)
+}
+
+func TestCloneRequest(t *testing.T) {
+	t.Run("http request body is nil", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/", nil)
+		assert.NoError(t, err)
+
+		ctx := req.Context()
+		rr, _, err := newReusableRequest(req, defaultMaxBodySize)
+		assert.NoError(t, err)
+
+		// first call
+		cloned := rr.clone(ctx)
+		assert.Equal(t, cloned, req)
+		assert.Nil(t, cloned.Body)
+
+		// second call
+		cloned = rr.clone(ctx)
+		assert.Equal(t, cloned, req)
+		assert.Nil(t, cloned.Body)
+	})
+
+	t.Run("http request body is not nil", func(t *testing.T) {
+		bb := []byte(`¯\_(ツ)_/¯`)
+		contentLength := len(bb)
+		buf := bytes.NewBuffer(bb)
+		req, err := http.NewRequest(http.MethodPost, "/", buf)
+		assert.NoError(t, err)
+
+		ctx := req.Context()
+		req.ContentLength = int64(contentLength)
+
+		rr, _, err := newReusableRequest(req, defaultMaxBodySize)
+		assert.NoError(t, err)
+
+		// first call
+		cloned := rr.clone(ctx)
+		body, err := ioutil.ReadAll(cloned.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, bb, body)
+
+		// second call
+		cloned = rr.clone(ctx)
+		body, err = ioutil.ReadAll(cloned.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, bb, body)
+	})
+
+	t.Run("failed case", func(t *testing.T) {
+		bb := []byte(`1234567890`)
+		buf := bytes.NewBuffer(bb)
+		req, err := http.NewRequest(http.MethodPost, "/", buf)
+		assert.NoError(t, err)
+
+		_, expectedBytes, err := newReusableRequest(req, 2)
+		assert.Error(t, err)
+		assert.Equal(t, expectedBytes, bb[:3])
+	})
+
+	t.Run("valid case with maxBodySize", func(t *testing.T) {
+		bb := []byte(`1234567890`)
+		buf := bytes.NewBuffer(bb)
+		req, err := http.NewRequest(http.MethodPost, "/", buf)
+		assert.NoError(t, err)
+
+		rr, expectedBytes, err := newReusableRequest(req, 20)
+		assert.NoError(t, err)
+		assert.Nil(t, expectedBytes)
+		assert.Len(t, rr.body, 10)
+	})
+
+	t.Run("valid GET case with maxBodySize", func(t *testing.T) {
+		buf := bytes.NewBuffer([]byte{})
+		req, err := http.NewRequest(http.MethodGet, "/", buf)
+		assert.NoError(t, err)
+
+		rr, expectedBytes, err := newReusableRequest(req, 20)
+		assert.NoError(t, err)
+		assert.Nil(t, expectedBytes)
+		assert.Len(t, rr.body, 0)
+	})
+
+	t.Run("no request given", func(t *testing.T) {
+		_, _, err := newReusableRequest(nil, defaultMaxBodySize)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+package strategy
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// decayTime is the time window over which past latencies lose their influence on the peak EWMA.
+const decayTime = 10 * time.Second
+
+// NewPeakEWMA creates a load-balancer which, for each request, randomly samples two servers
+// and sends the request to the one with the lowest cost,
+// the cost being the peak exponentially weighted moving average of its latency,
+// multiplied by its number of in-flight requests, relative to its weight.
+func NewPeakEWMA(next http.Handler) *Balancer {
+	b := newBalancer(next, &powerOfTwoChoices{
+		rand: newRand(),
+		cost: ewmaCost,
+	})
+	b.trackLatency = true
+	return b
+}
+
+func ewmaCost(s *server) float64 {
+	return s.latency.value() * s.load()
+}
+
+// peakEWMA is an exponentially weighted moving average of latencies,
+// which immediately jumps to any latency higher than its current value.
+// Servers without any observed latency have a zero cost, so that they are tried first.
+type peakEWMA struct {
+	mu    sync.Mutex
+	ewma  float64
+	stamp time.Time
+}
+
+func (p *peakEWMA) observe(latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	rtt := float64(latency)
+
+	switch {
+	case p.stamp.IsZero(), rtt > p.ewma:
+		p.ewma = rtt
+	default:
+		w := math.Exp(-float64(now.Sub(p.stamp)) / float64(decayTime))
+		p.ewma = p.ewma*w + rtt*(1-w)
+	}
+	p.stamp = now
+}
+
+func (p *peakEWMA) value() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.ewma
+}
@@ -0,0 +1,38 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeakEWMA_Observe(t *testing.T) {
+	p := &peakEWMA{}
+
+	p.observe(10 * time.Millisecond)
+	assert.Equal(t, float64(10*time.Millisecond), p.value())
+
+	// A peak is taken into account immediately.
+	p.observe(100 * time.Millisecond)
+	assert.Equal(t, float64(100*time.Millisecond), p.value())
+
+	// Lower latencies are only taken into account progressively.
+	p.observe(10 * time.Millisecond)
+	assert.True(t, p.value() > float64(10*time.Millisecond))
+	assert.True(t, p.value() <= float64(100*time.Millisecond))
+}
+
+func TestPeakEWMA_Pick(t *testing.T) {
+	slow := &server{url: testhelpers.MustParseURL("http://slow:80"), weight: 1, latency: &peakEWMA{}}
+	slow.latency.observe(time.Second)
+
+	fast := &server{url: testhelpers.MustParseURL("http://fast:80"), weight: 1, latency: &peakEWMA{}}
+	fast.latency.observe(time.Millisecond)
+
+	picker := &powerOfTwoChoices{rand: newRand(), cost: ewmaCost}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "fast:80", picker.pick(nil, []*server{slow, fast}).url.Host)
+	}
+}
@@ -0,0 +1,67 @@
+package strategy
+
+import (
+	"hash/fnv"
+	"math"
+	"net"
+	"net/http"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+// NewConsistentHash creates a load-balancer which consistently sends the requests sharing the same key to the same server,
+// the key being the value of the configured header or cookie, or the client IP.
+// It relies on weighted rendezvous hashing, so that adding or removing a server only moves the keys of that server.
+func NewConsistentHash(next http.Handler, config *dynamic.ConsistentHash) *Balancer {
+	c := &consistentHash{}
+	if config != nil {
+		c.header = config.Header
+		c.cookie = config.Cookie
+	}
+	return newBalancer(next, c)
+}
+
+type consistentHash struct {
+	header string
+	cookie string
+}
+
+func (c *consistentHash) pick(req *http.Request, servers []*server) *server {
+	key := c.key(req)
+
+	var best *server
+	bestScore := math.Inf(-1)
+	for _, srv := range servers {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		_, _ = h.Write([]byte(srv.url.String()))
+
+		// Maps the hash to (0,1), and gives higher scores to heavier servers.
+		x := (float64(h.Sum64()>>11) + 0.5) / float64(1<<53)
+		score := -float64(srv.weight) / math.Log(x)
+		if score > bestScore {
+			best, bestScore = srv, score
+		}
+	}
+	return best
+}
+
+func (c *consistentHash) key(req *http.Request) string {
+	if c.header != "" {
+		if value := req.Header.Get(c.header); value != "" {
+			return value
+		}
+	}
+
+	if c.cookie != "" {
+		if cookie, err := req.Cookie(c.cookie); err == nil && cookie.Value != "" {
+			return cookie.Value
+		}
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,114 @@
+package strategy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsistentHash_Key(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *dynamic.ConsistentHash
+		header   string
+		cookie   string
+		expected string
+	}{
+		{
+			desc:     "source IP by default",
+			header:   "foo",
+			expected: "192.0.2.1",
+		},
+		{
+			desc:     "header",
+			config:   &dynamic.ConsistentHash{Header: "X-User"},
+			header:   "foo",
+			expected: "foo",
+		},
+		{
+			desc:     "cookie",
+			config:   &dynamic.ConsistentHash{Cookie: "session"},
+			cookie:   "bar",
+			expected: "bar",
+		},
+		{
+			desc:     "header before cookie",
+			config:   &dynamic.ConsistentHash{Header: "X-User", Cookie: "session"},
+			header:   "foo",
+			cookie:   "bar",
+			expected: "foo",
+		},
+		{
+			desc:     "fallback on source IP",
+			config:   &dynamic.ConsistentHash{Header: "X-User", Cookie: "session"},
+			expected: "192.0.2.1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			picker := NewConsistentHash(nil, test.config).picker.(*consistentHash)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.header != "" {
+				req.Header.Set("X-User", test.header)
+			}
+			if test.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "session", Value: test.cookie})
+			}
+
+			assert.Equal(t, test.expected, picker.key(req))
+		})
+	}
+}
+
+func TestConsistentHash(t *testing.T) {
+	next := &hostRecorder{hosts: map[string]int{}}
+	balancer := NewConsistentHash(next, &dynamic.ConsistentHash{Header: "X-User"})
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, balancer.UpsertServer(testhelpers.MustParseURL(fmt.Sprintf("http://server%d:80", i))))
+	}
+
+	picked := map[string]string{}
+	for i := 0; i < 100; i++ {
+		user := fmt.Sprintf("user%d", i)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", user)
+
+		srv, err := balancer.nextServer(req)
+		require.NoError(t, err)
+		picked[user] = srv.url.Host
+
+		// The same key always goes to the same server.
+		srv, err = balancer.nextServer(req)
+		require.NoError(t, err)
+		assert.Equal(t, picked[user], srv.url.Host)
+	}
+
+	// Removing a server only moves the keys of that server.
+	require.NoError(t, balancer.RemoveServer(testhelpers.MustParseURL("http://server0:80")))
+
+	for user, host := range picked {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", user)
+
+		srv, err := balancer.nextServer(req)
+		require.NoError(t, err)
+
+		if host != "server0:80" {
+			assert.Equal(t, host, srv.url.Host)
+		} else {
+			assert.NotEqual(t, host, srv.url.Host)
+		}
+	}
+}
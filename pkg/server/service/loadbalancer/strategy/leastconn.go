@@ -0,0 +1,32 @@
+package strategy
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// NewLeastConnections creates a load-balancer sending each request
+// to the server with the fewest in-flight requests relative to its weight.
+func NewLeastConnections(next http.Handler) *Balancer {
+	return newBalancer(next, &leastConnections{})
+}
+
+type leastConnections struct {
+	// offset rotates the starting point of the scan,
+	// so that ties are not always resolved in favor of the first server.
+	offset uint64
+}
+
+func (l *leastConnections) pick(_ *http.Request, servers []*server) *server {
+	start := int(atomic.AddUint64(&l.offset, 1) % uint64(len(servers)))
+
+	best := servers[start]
+	bestLoad := best.load()
+	for i := 1; i < len(servers); i++ {
+		srv := servers[(start+i)%len(servers)]
+		if load := srv.load(); load < bestLoad {
+			best, bestLoad = srv, load
+		}
+	}
+	return best
+}
@@ -0,0 +1,65 @@
+package strategy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeastConnections(t *testing.T) {
+	release := make(chan struct{})
+	blocked := sync.WaitGroup{}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", req.URL.Host)
+		if req.URL.Host == "slow:80" {
+			blocked.Done()
+			<-release
+		}
+	})
+
+	balancer := NewLeastConnections(next)
+	require.NoError(t, balancer.UpsertServer(testhelpers.MustParseURL("http://slow:80")))
+	require.NoError(t, balancer.UpsertServer(testhelpers.MustParseURL("http://fast:80")))
+
+	// Pins one request on the slow server.
+	blocked.Add(1)
+	done := make(chan struct{})
+	go func() {
+		for {
+			recorder := httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			if recorder.Header().Get("server") == "slow:80" {
+				close(done)
+				return
+			}
+		}
+	}()
+	blocked.Wait()
+
+	for i := 0; i < 10; i++ {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "fast:80", recorder.Header().Get("server"))
+	}
+
+	close(release)
+	<-done
+}
+
+func TestLeastConnections_Weights(t *testing.T) {
+	servers := []*server{
+		{url: testhelpers.MustParseURL("http://first:80"), weight: 3, inflight: 2},
+		{url: testhelpers.MustParseURL("http://second:80"), weight: 1, inflight: 1},
+	}
+
+	picker := &leastConnections{}
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, "first:80", picker.pick(nil, servers).url.Host)
+	}
+}
@@ -0,0 +1,57 @@
+package strategy
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NewPowerOfTwoChoices creates a load-balancer which, for each request,
+// randomly samples two servers and sends the request to the one with the fewest in-flight requests relative to its weight.
+func NewPowerOfTwoChoices(next http.Handler) *Balancer {
+	return newBalancer(next, &powerOfTwoChoices{
+		rand: newRand(),
+		cost: (*server).load,
+	})
+}
+
+type powerOfTwoChoices struct {
+	rand *lockedRand
+	cost func(*server) float64
+}
+
+func (p *powerOfTwoChoices) pick(_ *http.Request, servers []*server) *server {
+	if len(servers) == 1 {
+		return servers[0]
+	}
+
+	i, j := p.rand.pair(len(servers))
+	if p.cost(servers[j]) < p.cost(servers[i]) {
+		return servers[j]
+	}
+	return servers[i]
+}
+
+// lockedRand is a source of random numbers safe for concurrent use.
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newRand() *lockedRand {
+	return &lockedRand{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// pair returns two distinct random integers in [0,n), n must be at least 2.
+func (r *lockedRand) pair(n int) (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.rand.Intn(n)
+	j := r.rand.Intn(n - 1)
+	if j >= i {
+		j++
+	}
+	return i, j
+}
@@ -0,0 +1,39 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerOfTwoChoices(t *testing.T) {
+	servers := []*server{
+		{url: testhelpers.MustParseURL("http://busy:80"), weight: 1, inflight: 10},
+		{url: testhelpers.MustParseURL("http://idle:80"), weight: 1},
+	}
+
+	picker := &powerOfTwoChoices{rand: newRand(), cost: (*server).load}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "idle:80", picker.pick(nil, servers).url.Host)
+	}
+}
+
+func TestPowerOfTwoChoices_OneServer(t *testing.T) {
+	servers := []*server{
+		{url: testhelpers.MustParseURL("http://busy:80"), weight: 1, inflight: 10},
+	}
+
+	picker := &powerOfTwoChoices{rand: newRand(), cost: (*server).load}
+	assert.Equal(t, "busy:80", picker.pick(nil, servers).url.Host)
+}
+
+func TestLockedRand_Pair(t *testing.T) {
+	r := newRand()
+	for i := 0; i < 100; i++ {
+		a, b := r.pair(3)
+		assert.NotEqual(t, a, b)
+		assert.True(t, a >= 0 && a < 3)
+		assert.True(t, b >= 0 && b < 3)
+	}
+}
@@ -0,0 +1,196 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+// picker selects, amongst the given servers, the one that should handle the request.
+// The given slice is never empty.
+type picker interface {
+	pick(req *http.Request, servers []*server) *server
+}
+
+type server struct {
+	// inflight is the number of requests currently handled by the server,
+	// it is first in the struct to guarantee its 64-bit alignment for atomic operations.
+	inflight int64
+
+	url    *url.URL
+	weight int
+
+	// latency is only maintained for the pickers that need it.
+	latency *peakEWMA
+}
+
+func (s *server) load() float64 {
+	return float64(atomic.LoadInt64(&s.inflight)+1) / float64(s.weight)
+}
+
+// Balancer is a load-balancer of servers,
+// which delegates the choice of the server for each request to a picking strategy.
+// It implements healthcheck.BalancerHandler.
+type Balancer struct {
+	next   http.Handler
+	picker picker
+
+	// trackLatency tells whether the response time of the servers must be recorded.
+	trackLatency bool
+
+	mutex   sync.RWMutex
+	servers []*server
+}
+
+func newBalancer(next http.Handler, p picker) *Balancer {
+	return &Balancer{next: next, picker: p}
+}
+
+func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	srv, err := b.nextServer(req)
+	if err != nil {
+		log.FromContext(req.Context()).Errorf("Unable to select a server: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *req
+	newReq.URL = utils.CopyURL(srv.url)
+
+	atomic.AddInt64(&srv.inflight, 1)
+	defer atomic.AddInt64(&srv.inflight, -1)
+
+	if srv.latency == nil {
+		b.next.ServeHTTP(w, &newReq)
+		return
+	}
+
+	start := time.Now()
+	b.next.ServeHTTP(w, &newReq)
+	srv.latency.observe(time.Since(start))
+}
+
+func (b *Balancer) nextServer(req *http.Request) (*server, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if len(b.servers) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	srv := b.picker.pick(req, b.servers)
+
+	log.FromContext(req.Context()).Debugf("Server selected by load-balancer: %s", srv.url)
+	return srv, nil
+}
+
+// Servers returns the URLs of the servers in the pool.
+func (b *Balancer) Servers() []*url.URL {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	out := make([]*url.URL, len(b.servers))
+	for i, srv := range b.servers {
+		out[i] = srv.url
+	}
+	return out
+}
+
+// ServerWeight returns the weight of the given server.
+func (b *Balancer) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		return srv.weight, true
+	}
+	return -1, false
+}
+
+// RemoveServer removes the given server from the pool.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv, index := b.findServer(u)
+	if srv == nil {
+		return errors.New("server not found")
+	}
+
+	b.servers = append(b.servers[:index], b.servers[index+1:]...)
+	return nil
+}
+
+// UpsertServer adds the given server to the pool, with a weight of 1,
+// or keeps its current weight if it is already part of it.
+// The weight of the servers must be given with UpsertWeightedServer, as the server options can't be read.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if len(options) > 0 {
+		return errors.New("server options are not supported, the weight must be given with UpsertWeightedServer")
+	}
+
+	if u == nil {
+		return errors.New("server URL can't be nil")
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		return nil
+	}
+
+	b.addServer(u, 1)
+	return nil
+}
+
+// UpsertWeightedServer adds the given server to the pool with the given weight,
+// or updates its weight if it is already part of it.
+func (b *Balancer) UpsertWeightedServer(u *url.URL, weight int) error {
+	if u == nil {
+		return errors.New("server URL can't be nil")
+	}
+
+	if weight < 0 {
+		return fmt.Errorf("invalid weight %d", weight)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		srv.weight = weight
+		return nil
+	}
+
+	b.addServer(u, weight)
+	return nil
+}
+
+// addServer adds a server to the pool, it must be called with the lock held.
+func (b *Balancer) addServer(u *url.URL, weight int) {
+	srv := &server{url: utils.CopyURL(u), weight: weight}
+	if b.trackLatency {
+		srv.latency = &peakEWMA{}
+	}
+
+	b.servers = append(b.servers, srv)
+}
+
+func (b *Balancer) findServer(u *url.URL) (*server, int) {
+	for i, srv := range b.servers {
+		if srv.url.Path == u.Path && srv.url.Host == u.Host && srv.url.Scheme == u.Scheme {
+			return srv, i
+		}
+	}
+	return nil, -1
+}
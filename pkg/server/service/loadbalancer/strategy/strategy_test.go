@@ -0,0 +1,92 @@
+package strategy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+var _ healthcheck.BalancerHandler = (*Balancer)(nil)
+
+// hostRecorder is a next handler recording the servers it has been called for.
+type hostRecorder struct {
+	hosts map[string]int
+}
+
+func (h *hostRecorder) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	h.hosts[req.URL.Host]++
+	rw.WriteHeader(http.StatusOK)
+}
+
+func TestBalancer_UpsertServer(t *testing.T) {
+	balancer := NewLeastConnections(http.NotFoundHandler())
+
+	serverURL := testhelpers.MustParseURL("http://foo:80")
+	require.NoError(t, balancer.UpsertWeightedServer(serverURL, 3))
+	require.NoError(t, balancer.UpsertServer(testhelpers.MustParseURL("http://bar:80")))
+
+	assert.Len(t, balancer.Servers(), 2)
+
+	weight, found := balancer.ServerWeight(serverURL)
+	require.True(t, found)
+	assert.Equal(t, 3, weight)
+
+	require.NoError(t, balancer.UpsertWeightedServer(serverURL, 5))
+	assert.Len(t, balancer.Servers(), 2)
+
+	weight, found = balancer.ServerWeight(serverURL)
+	require.True(t, found)
+	assert.Equal(t, 5, weight)
+
+	// Upserting a server without weight keeps its current weight.
+	require.NoError(t, balancer.UpsertServer(serverURL))
+
+	weight, found = balancer.ServerWeight(serverURL)
+	require.True(t, found)
+	assert.Equal(t, 5, weight)
+
+	assert.Error(t, balancer.UpsertWeightedServer(serverURL, -1))
+	assert.Error(t, balancer.UpsertServer(serverURL, roundrobin.Weight(2)))
+}
+
+func TestBalancer_RemoveServer(t *testing.T) {
+	balancer := NewPowerOfTwoChoices(http.NotFoundHandler())
+
+	serverURL := testhelpers.MustParseURL("http://foo:80")
+	require.NoError(t, balancer.UpsertServer(serverURL))
+	require.NoError(t, balancer.RemoveServer(serverURL))
+
+	assert.Empty(t, balancer.Servers())
+	assert.Error(t, balancer.RemoveServer(serverURL))
+
+	_, found := balancer.ServerWeight(serverURL)
+	assert.False(t, found)
+}
+
+func TestBalancer_NoServer(t *testing.T) {
+	balancer := NewPeakEWMA(http.NotFoundHandler())
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestBalancer_ForwardsToServer(t *testing.T) {
+	next := &hostRecorder{hosts: map[string]int{}}
+	balancer := NewConsistentHash(next, nil)
+
+	require.NoError(t, balancer.UpsertServer(testhelpers.MustParseURL("http://foo:80")))
+
+	req := httptest.NewRequest(http.MethodGet, "http://callme/path", nil)
+	balancer.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, map[string]int{"foo:80": 1}, next.hosts)
+	assert.Equal(t, "callme", req.URL.Host, "the original request must not be modified")
+}
@@ -2,6 +2,8 @@ package wrr
 
 import (
 	"container/heap"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -23,9 +25,13 @@ type stickyCookie struct {
 	httpOnly bool
 }
 
+var errNoAvailableServer = errors.New("no available server")
+
 // New creates a new load balancer.
 func New(sticky *dynamic.Sticky) *Balancer {
-	balancer := &Balancer{}
+	balancer := &Balancer{
+		status: make(map[string]struct{}),
+	}
 	if sticky != nil && sticky.Cookie != nil {
 		balancer.stickyCookie = &stickyCookie{
 			name:     sticky.Cookie.Name,
@@ -78,6 +84,59 @@ type Balancer struct {
 	mutex       sync.RWMutex
 	handlers    []*namedHandler
 	curDeadline float64
+	// status is a record of which child services of the Balancer are healthy, keyed by name of child service.
+	// A service is initially added to the map when it is created via AddService,
+	// and it is later removed or added to the map as needed, through the SetStatus method.
+	status map[string]struct{}
+	// updaters is the list of hooks that are run (to update the Balancer parent(s)), whenever the Balancer status changes.
+	updaters []func(up bool)
+}
+
+// SetStatus sets on the balancer that its given child is now of the given status.
+// It propagates the change to the parent(s) of the balancer if its own status changes,
+// i.e. when its last healthy child goes down, or when its first child goes back up.
+// The status of a child which was not added to the balancer, such as one with a non-positive weight, is ignored.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.hasHandler(childName) {
+		log.FromContext(ctx).Debugf("Ignoring status of unknown child service %s", childName)
+		return
+	}
+
+	upBefore := len(b.status) > 0
+
+	log.FromContext(ctx).Debugf("Setting status of child service %s to up: %v", childName, up)
+	if up {
+		b.status[childName] = struct{}{}
+	} else {
+		delete(b.status, childName)
+	}
+
+	upAfter := len(b.status) > 0
+	if upBefore == upAfter {
+		return
+	}
+
+	log.FromContext(ctx).Debugf("Propagating new status (up: %v)", upAfter)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the status of the Balancer changes.
+// fn is run right away if the Balancer is already down.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.updaters = append(b.updaters, fn)
+	if len(b.status) == 0 {
+		fn(false)
+	}
+
+	return nil
 }
 
 func (b *Balancer) nextServer() (*namedHandler, error) {
@@ -88,17 +147,51 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 		return nil, fmt.Errorf("no servers in the pool")
 	}
 
-	// Pick handler with closest deadline.
-	handler := heap.Pop(b).(*namedHandler)
+	if len(b.status) == 0 {
+		return nil, errNoAvailableServer
+	}
+
+	// The child services which are down are set aside until a healthy one is found,
+	// so that each handler is looked at, at most once.
+	var skipped []*namedHandler
+	defer func() {
+		for _, h := range skipped {
+			heap.Push(b, h)
+		}
+	}()
+
+	for n := len(b.handlers); n > 0; n-- {
+		// Pick handler with closest deadline.
+		handler := heap.Pop(b).(*namedHandler)
+
+		// curDeadline should be handler's deadline so that new added entry would have a fair competition environment with the old ones.
+		b.curDeadline = handler.deadline
+		handler.deadline += 1 / handler.weight
+
+		// Skips the child services that are down.
+		if _, ok := b.status[handler.name]; !ok {
+			skipped = append(skipped, handler)
+			continue
+		}
+
+		heap.Push(b, handler)
 
-	// curDeadline should be handler's deadline so that new added entry would have a fair competition environment with the old ones.
-	b.curDeadline = handler.deadline
-	handler.deadline += 1 / handler.weight
+		log.WithoutContext().Debugf("Service selected by WRR: %s", handler.name)
+		return handler, nil
+	}
 
-	heap.Push(b, handler)
+	return nil, errNoAvailableServer
+}
 
-	log.WithoutContext().Debugf("Service selected by WRR: %s", handler.name)
-	return handler, nil
+// hasHandler reports whether a child service of the given name was added to the balancer.
+// It must be called with the mutex held.
+func (b *Balancer) hasHandler(name string) bool {
+	for _, handler := range b.handlers {
+		if handler.name == name {
+			return true
+		}
+	}
+	return false
 }
 
 func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -110,17 +203,19 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 
 		if err == nil && cookie != nil {
-			for _, handler := range b.handlers {
-				if handler.name == cookie.Value {
-					handler.ServeHTTP(w, req)
-					return
-				}
+			if handler := b.stickyHandler(cookie.Value); handler != nil {
+				handler.ServeHTTP(w, req)
+				return
 			}
 		}
 	}
 
 	server, err := b.nextServer()
 	if err != nil {
+		if errors.Is(err, errNoAvailableServer) {
+			http.Error(w, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+			return
+		}
 		http.Error(w, http.StatusText(http.StatusInternalServerError)+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -133,6 +228,23 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server.ServeHTTP(w, req)
 }
 
+// stickyHandler returns the child service of the given name, if it is up.
+func (b *Balancer) stickyHandler(name string) *namedHandler {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if _, ok := b.status[name]; !ok {
+		return nil
+	}
+
+	for _, handler := range b.handlers {
+		if handler.name == name {
+			return handler
+		}
+	}
+	return nil
+}
+
 // AddService adds a handler.
 // It is not thread safe with ServeHTTP.
 // A handler with a non-positive weight is ignored.
@@ -147,10 +259,10 @@ func (b *Balancer) AddService(name string, handler http.Handler, weight *int) {
 
 	h := &namedHandler{Handler: handler, name: name, weight: float64(w)}
 
-	// use RWLock to protect b.curDeadline
-	b.mutex.RLock()
+	// use the lock to protect b.curDeadline and b.status
+	b.mutex.Lock()
 	h.deadline = b.curDeadline + 1/h.weight
-	b.mutex.RUnlock()
-
 	heap.Push(b, h)
+	b.status[name] = struct{}{}
+	b.mutex.Unlock()
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/containous/traefik/v2/pkg/api"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/metrics"
@@ -14,7 +15,7 @@ import (
 type ManagerFactory struct {
 	metricsRegistry metrics.Registry
 
-	defaultRoundTripper http.RoundTripper
+	roundTripperManager *RoundTripperManager
 
 	api              func(configuration *runtime.Configuration) http.Handler
 	restHandler      http.Handler
@@ -29,7 +30,7 @@ type ManagerFactory struct {
 func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry) *ManagerFactory {
 	factory := &ManagerFactory{
 		metricsRegistry:     metricsRegistry,
-		defaultRoundTripper: setupDefaultRoundTripper(staticConfiguration.ServersTransport),
+		roundTripperManager: NewRoundTripperManager(setupDefaultRoundTripper(staticConfiguration.ServersTransport)),
 		routinesPool:        routinesPool,
 	}
 
@@ -59,8 +60,13 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 	return factory
 }
 
+// UpdateServersTransports updates the round trippers used by the service managers with the given ServersTransports.
+func (f *ManagerFactory) UpdateServersTransports(configs map[string]*dynamic.ServersTransport) {
+	f.roundTripperManager.Update(configs)
+}
+
 // Build creates a service manager.
 func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHandlers {
-	svcManager := NewManager(configuration.Services, f.defaultRoundTripper, f.metricsRegistry, f.routinesPool)
+	svcManager := NewManager(configuration.Services, f.roundTripperManager, f.metricsRegistry, f.routinesPool)
 	return NewInternalHandlers(f.api, configuration, f.restHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, svcManager)
 }
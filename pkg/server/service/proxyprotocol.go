@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/tcp"
+)
+
+type proxyProtocolAddrsKey struct{}
+
+// proxyProtocolAddrs are the addresses announced by the PROXY protocol header.
+type proxyProtocolAddrs struct {
+	src net.Addr
+	dst net.Addr
+}
+
+// proxyProtocolRoundTripper is an http.RoundTripper opening a new connection to the server for each request,
+// starting with a PROXY protocol header announcing the client connection the request comes from.
+type proxyProtocolRoundTripper struct {
+	transport *http.Transport
+}
+
+// newProxyProtocolRoundTripper returns a round tripper based on the given one, sending a PROXY protocol header to the servers.
+// As the header describes the client connection, the connections to the servers can't be reused, nor multiplexed with HTTP/2.
+func newProxyProtocolRoundTripper(roundTripper http.RoundTripper, proxyProtocol *dynamic.ProxyProtocol) (http.RoundTripper, error) {
+	version := proxyProtocol.Version
+	if version == 0 {
+		version = 2
+	}
+
+	if version != 1 && version != 2 {
+		return nil, fmt.Errorf("unsupported PROXY protocol version: %d", version)
+	}
+
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("PROXY protocol is not supported with the round tripper %T", roundTripper)
+	}
+
+	transport = transport.Clone()
+	transport.DisableKeepAlives = true
+	transport.ForceAttemptHTTP2 = false
+	transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+
+	dialContext := transport.DialContext
+	if dialContext == nil {
+		dialContext = (&net.Dialer{}).DialContext
+	}
+
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		// Without client connection, e.g. for the health checks, the header announces a local connection.
+		addrs, _ := ctx.Value(proxyProtocolAddrsKey{}).(proxyProtocolAddrs)
+		if err := tcp.WriteProxyProtocolHeader(conn, version, addrs.src, addrs.dst); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("error while writing the PROXY protocol header: %w", err)
+		}
+
+		return conn, nil
+	}
+
+	return &proxyProtocolRoundTripper{transport: transport}, nil
+}
+
+func (p *proxyProtocolRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	addrs := proxyProtocolAddrs{src: parseTCPAddr(req.RemoteAddr)}
+	if localAddr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		addrs.dst = localAddr
+	}
+
+	return p.transport.RoundTrip(req.WithContext(context.WithValue(req.Context(), proxyProtocolAddrsKey{}, addrs)))
+}
+
+// CloseIdleConnections closes the idle connections of the underlying transport.
+func (p *proxyProtocolRoundTripper) CloseIdleConnections() {
+	p.transport.CloseIdleConnections()
+}
+
+// parseTCPAddr parses a host:port address with an IP host, and returns nil if it can't.
+func parseTCPAddr(address string) net.Addr {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return nil
+	}
+
+	return &net.TCPAddr{IP: ip, Port: portNumber}
+}
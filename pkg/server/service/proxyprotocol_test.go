@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	proxyprotocol "github.com/c0va23/go-proxyprotocol"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyProtocolRoundTripper(t *testing.T) {
+	// The server answers with the client address it sees, as announced by the PROXY protocol header.
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.RemoteAddr))
+	}))
+	server.Listener = proxyprotocol.NewDefaultListener(server.Listener)
+	server.Start()
+	defer server.Close()
+
+	testCases := []struct {
+		desc       string
+		version    int
+		remoteAddr string
+		localAddr  string
+		expected   string
+	}{
+		{
+			desc:       "v1",
+			version:    1,
+			remoteAddr: "10.0.0.1:1234",
+			localAddr:  "10.0.0.2:80",
+			expected:   "10.0.0.1:1234",
+		},
+		{
+			desc:       "v2",
+			version:    2,
+			remoteAddr: "10.0.0.1:1234",
+			localAddr:  "10.0.0.2:80",
+			expected:   "10.0.0.1:1234",
+		},
+		{
+			desc:       "default version",
+			remoteAddr: "[2001:db8::1]:1234",
+			localAddr:  "[2001:db8::2]:80",
+			expected:   "[2001:db8::1]:1234",
+		},
+		{
+			desc: "without client address",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			rt, err := newProxyProtocolRoundTripper(http.DefaultTransport, &dynamic.ProxyProtocol{Version: test.version})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, server.URL, nil)
+			req.RequestURI = ""
+			req.RemoteAddr = test.remoteAddr
+			if test.localAddr != "" {
+				req = req.WithContext(context.WithValue(req.Context(), http.LocalAddrContextKey, parseTCPAddr(test.localAddr)))
+			}
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			if test.expected == "" {
+				// The server gets the real address of the connection.
+				host, _, err := net.SplitHostPort(string(body))
+				require.NoError(t, err)
+				assert.Equal(t, "127.0.0.1", host)
+				return
+			}
+
+			assert.Equal(t, test.expected, string(body))
+		})
+	}
+}
+
+func TestNewProxyProtocolRoundTripper_unsupportedVersion(t *testing.T) {
+	_, err := newProxyProtocolRoundTripper(http.DefaultTransport, &dynamic.ProxyProtocol{Version: 3})
+	assert.Error(t, err)
+}
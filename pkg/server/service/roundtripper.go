@@ -4,10 +4,14 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"reflect"
+	"sync"
 	"time"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/log"
 	traefiktls "github.com/containous/traefik/v2/pkg/tls"
@@ -23,12 +27,106 @@ func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, erro
 	return t.Transport.RoundTrip(req)
 }
 
-// createHTTPTransport creates an http.Transport configured with the Transport configuration settings.
+// NewRoundTripperManager creates a new RoundTripperManager,
+// using defaultRoundTripper for the services which do not reference a ServersTransport.
+func NewRoundTripperManager(defaultRoundTripper http.RoundTripper) *RoundTripperManager {
+	return &RoundTripperManager{
+		defaultRoundTripper: defaultRoundTripper,
+		roundTrippers:       make(map[string]http.RoundTripper),
+		configs:             make(map[string]*dynamic.ServersTransport),
+	}
+}
+
+// RoundTripperManager handles the round trippers of the ServersTransports defined in the dynamic configuration.
+type RoundTripperManager struct {
+	defaultRoundTripper http.RoundTripper
+
+	rtLock        sync.RWMutex
+	roundTrippers map[string]http.RoundTripper
+	configs       map[string]*dynamic.ServersTransport
+}
+
+// Update updates the round trippers with the given ServersTransports configurations.
+// The round trippers whose configuration did not change are kept, so that their idle connections are reused.
+func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTransport) {
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	for configName, config := range r.configs {
+		newConfig, ok := newConfigs[configName]
+		if !ok {
+			r.closeIdleConnections(configName)
+			delete(r.configs, configName)
+			delete(r.roundTrippers, configName)
+			continue
+		}
+
+		if reflect.DeepEqual(newConfig, config) {
+			continue
+		}
+
+		roundTripper, err := createRoundTripper(newConfig)
+		if err != nil {
+			log.WithoutContext().Errorf("Could not configure HTTP Transport %s, keeping the previous transport: %v", configName, err)
+			continue
+		}
+
+		r.closeIdleConnections(configName)
+		r.configs[configName] = newConfig
+		r.roundTrippers[configName] = roundTripper
+	}
+
+	for newConfigName, newConfig := range newConfigs {
+		if _, ok := r.configs[newConfigName]; ok {
+			continue
+		}
+
+		roundTripper, err := createRoundTripper(newConfig)
+		if err != nil {
+			log.WithoutContext().Errorf("Could not configure HTTP Transport %s, fallbacking on default transport: %v", newConfigName, err)
+			roundTripper = r.defaultRoundTripper
+		}
+		r.configs[newConfigName] = newConfig
+		r.roundTrippers[newConfigName] = roundTripper
+	}
+}
+
+// Get returns the round tripper of the ServersTransport with the given name,
+// or the default round tripper if name is empty.
+func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
+	if len(name) == 0 {
+		return r.defaultRoundTripper, nil
+	}
+
+	r.rtLock.RLock()
+	defer r.rtLock.RUnlock()
+
+	if rt, ok := r.roundTrippers[name]; ok {
+		return rt, nil
+	}
+
+	return nil, fmt.Errorf("servers transport not found %s", name)
+}
+
+// closeIdleConnections closes the idle connections of the round tripper of the ServersTransport with the given name.
+// The default round tripper, used when the round tripper could not be built, is shared and left untouched.
+func (r *RoundTripperManager) closeIdleConnections(name string) {
+	roundTripper := r.roundTrippers[name]
+	if roundTripper == r.defaultRoundTripper {
+		return
+	}
+
+	if rt, ok := roundTripper.(interface{ CloseIdleConnections() }); ok {
+		rt.CloseIdleConnections()
+	}
+}
+
+// createRoundTripper creates an http.RoundTripper configured with the Transport configuration settings.
 // For the settings that can't be configured in Traefik it uses the default http.Transport settings.
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost
 // in Traefik at this point in time. Setting this value to the default of 100 could lead to confusing
 // behavior and backwards compatibility issues.
-func createHTTPTransport(transportConfiguration *static.ServersTransport) (*http.Transport, error) {
+func createRoundTripper(transportConfiguration *dynamic.ServersTransport) (http.RoundTripper, error) {
 	if transportConfiguration == nil {
 		return nil, errors.New("no transport configuration given")
 	}
@@ -66,16 +164,20 @@ func createHTTPTransport(transportConfiguration *static.ServersTransport) (*http
 		transport.IdleConnTimeout = time.Duration(transportConfiguration.ForwardingTimeouts.IdleConnTimeout)
 	}
 
-	if transportConfiguration.InsecureSkipVerify {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	}
-
-	if len(transportConfiguration.RootCAs) > 0 {
+	if transportConfiguration.InsecureSkipVerify || len(transportConfiguration.RootCAs) > 0 ||
+		len(transportConfiguration.ServerName) > 0 || len(transportConfiguration.Certificates) > 0 {
 		transport.TLSClientConfig = &tls.Config{
-			RootCAs: createRootCACertPool(transportConfiguration.RootCAs),
+			ServerName:         transportConfiguration.ServerName,
+			InsecureSkipVerify: transportConfiguration.InsecureSkipVerify,
+			RootCAs:            createRootCACertPool(transportConfiguration.RootCAs),
+			Certificates:       createClientCertificates(transportConfiguration.Certificates),
 		}
 	}
 
+	if transportConfiguration.DisableHTTP2 {
+		return transport, nil
+	}
+
 	err := http2.ConfigureTransport(transport)
 	if err != nil {
 		return nil, err
@@ -85,6 +187,10 @@ func createHTTPTransport(transportConfiguration *static.ServersTransport) (*http
 }
 
 func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
+	if len(rootCAs) == 0 {
+		return nil
+	}
+
 	roots := x509.NewCertPool()
 
 	for _, cert := range rootCAs {
@@ -99,8 +205,53 @@ func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
 	return roots
 }
 
+func createClientCertificates(certificates traefiktls.Certificates) []tls.Certificate {
+	var certs []tls.Certificate
+
+	for _, certificate := range certificates {
+		certContent, err := certificate.CertFile.Read()
+		if err != nil {
+			log.WithoutContext().Errorf("Error while reading client certificate: %v", err)
+			continue
+		}
+
+		keyContent, err := certificate.KeyFile.Read()
+		if err != nil {
+			log.WithoutContext().Errorf("Error while reading client certificate key: %v", err)
+			continue
+		}
+
+		cert, err := tls.X509KeyPair(certContent, keyContent)
+		if err != nil {
+			log.WithoutContext().Errorf("Error while loading client certificate: %v", err)
+			continue
+		}
+
+		certs = append(certs, cert)
+	}
+
+	return certs
+}
+
 func setupDefaultRoundTripper(conf *static.ServersTransport) http.RoundTripper {
-	transport, err := createHTTPTransport(conf)
+	var transportConfiguration *dynamic.ServersTransport
+	if conf != nil {
+		transportConfiguration = &dynamic.ServersTransport{
+			InsecureSkipVerify:  conf.InsecureSkipVerify,
+			RootCAs:             conf.RootCAs,
+			MaxIdleConnsPerHost: conf.MaxIdleConnsPerHost,
+		}
+
+		if conf.ForwardingTimeouts != nil {
+			transportConfiguration.ForwardingTimeouts = &dynamic.ForwardingTimeouts{
+				DialTimeout:           conf.ForwardingTimeouts.DialTimeout,
+				ResponseHeaderTimeout: conf.ForwardingTimeouts.ResponseHeaderTimeout,
+				IdleConnTimeout:       conf.ForwardingTimeouts.IdleConnTimeout,
+			}
+		}
+	}
+
+	transport, err := createRoundTripper(transportConfiguration)
 	if err != nil {
 		log.WithoutContext().Errorf("Could not configure HTTP Transport, fallbacking on default transport: %v", err)
 		return http.DefaultTransport
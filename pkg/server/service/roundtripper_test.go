@@ -0,0 +1,298 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTripperManager_Get(t *testing.T) {
+	defaultRoundTripper := &http.Transport{}
+	rtManager := NewRoundTripperManager(defaultRoundTripper)
+
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"test": {MaxIdleConnsPerHost: 42},
+	})
+
+	rt, err := rtManager.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, defaultRoundTripper, rt)
+
+	rt, err = rtManager.Get("test")
+	require.NoError(t, err)
+	assert.NotEqual(t, defaultRoundTripper, rt)
+	assert.Equal(t, 42, rt.(*http.Transport).MaxIdleConnsPerHost)
+
+	_, err = rtManager.Get("unknown")
+	assert.Error(t, err)
+}
+
+func TestRoundTripperManager_Update(t *testing.T) {
+	rtManager := NewRoundTripperManager(http.DefaultTransport)
+
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"unchanged": {MaxIdleConnsPerHost: 1},
+		"changed":   {MaxIdleConnsPerHost: 1},
+		"removed":   {MaxIdleConnsPerHost: 1},
+	})
+
+	unchanged, err := rtManager.Get("unchanged")
+	require.NoError(t, err)
+	changed, err := rtManager.Get("changed")
+	require.NoError(t, err)
+
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"unchanged": {MaxIdleConnsPerHost: 1},
+		"changed":   {MaxIdleConnsPerHost: 2},
+		"added":     {MaxIdleConnsPerHost: 3},
+	})
+
+	rt, err := rtManager.Get("unchanged")
+	require.NoError(t, err)
+	assert.Same(t, unchanged, rt)
+
+	rt, err = rtManager.Get("changed")
+	require.NoError(t, err)
+	assert.NotSame(t, changed, rt)
+	assert.Equal(t, 2, rt.(*http.Transport).MaxIdleConnsPerHost)
+
+	rt, err = rtManager.Get("added")
+	require.NoError(t, err)
+	assert.Equal(t, 3, rt.(*http.Transport).MaxIdleConnsPerHost)
+
+	_, err = rtManager.Get("removed")
+	assert.Error(t, err)
+}
+
+type closeCountingRoundTripper struct {
+	http.RoundTripper
+	closed int
+}
+
+func (c *closeCountingRoundTripper) CloseIdleConnections() {
+	c.closed++
+}
+
+func TestRoundTripperManager_UpdateFailure(t *testing.T) {
+	defaultRoundTripper := &closeCountingRoundTripper{RoundTripper: http.DefaultTransport}
+	rtManager := NewRoundTripperManager(defaultRoundTripper)
+
+	// The round trippers which cannot be built fall back on the default one.
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"broken": nil,
+		"valid":  {MaxIdleConnsPerHost: 1},
+	})
+
+	rt, err := rtManager.Get("broken")
+	require.NoError(t, err)
+	assert.Same(t, defaultRoundTripper, rt)
+
+	valid, err := rtManager.Get("valid")
+	require.NoError(t, err)
+
+	// The previous round tripper is kept when the new one cannot be built.
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"broken": {MaxIdleConnsPerHost: 2},
+		"valid":  nil,
+	})
+
+	rt, err = rtManager.Get("valid")
+	require.NoError(t, err)
+	assert.Same(t, valid, rt)
+
+	rt, err = rtManager.Get("broken")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rt.(*http.Transport).MaxIdleConnsPerHost)
+
+	// The shared default round tripper is never closed.
+	rtManager.Update(map[string]*dynamic.ServersTransport{"removed": nil})
+	rtManager.Update(nil)
+
+	assert.Equal(t, 0, defaultRoundTripper.closed)
+}
+
+func TestRoundTripperManager_TLS(t *testing.T) {
+	serverCert, serverKey := generateCertificate(t, "backend.local")
+	clientCert, clientKey := generateCertificate(t, "client")
+
+	serverTLSCert, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(clientCert)
+
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	backend.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverTLSCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	backend.StartTLS()
+	defer backend.Close()
+
+	testCases := []struct {
+		desc           string
+		config         *dynamic.ServersTransport
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			desc: "with server name, root CA and client certificate",
+			config: &dynamic.ServersTransport{
+				ServerName: "backend.local",
+				RootCAs:    []traefiktls.FileOrContent{traefiktls.FileOrContent(serverCert)},
+				Certificates: traefiktls.Certificates{
+					{CertFile: traefiktls.FileOrContent(clientCert), KeyFile: traefiktls.FileOrContent(clientKey)},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc: "without client certificate",
+			config: &dynamic.ServersTransport{
+				ServerName: "backend.local",
+				RootCAs:    []traefiktls.FileOrContent{traefiktls.FileOrContent(serverCert)},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "with wrong server name",
+			config: &dynamic.ServersTransport{
+				ServerName: "other.local",
+				RootCAs:    []traefiktls.FileOrContent{traefiktls.FileOrContent(serverCert)},
+				Certificates: traefiktls.Certificates{
+					{CertFile: traefiktls.FileOrContent(clientCert), KeyFile: traefiktls.FileOrContent(clientKey)},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "with insecure skip verify and client certificate",
+			config: &dynamic.ServersTransport{
+				InsecureSkipVerify: true,
+				Certificates: traefiktls.Certificates{
+					{CertFile: traefiktls.FileOrContent(clientCert), KeyFile: traefiktls.FileOrContent(clientKey)},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			rtManager := NewRoundTripperManager(http.DefaultTransport)
+			rtManager.Update(map[string]*dynamic.ServersTransport{"test": test.config})
+
+			rt, err := rtManager.Get("test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, backend.URL, nil)
+			req.RequestURI = ""
+
+			resp, err := rt.RoundTrip(req)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestRoundTripperManager_DisableHTTP2(t *testing.T) {
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Proto", req.Proto)
+	}))
+	backend.EnableHTTP2 = true
+	backend.StartTLS()
+	defer backend.Close()
+
+	testCases := []struct {
+		desc          string
+		disableHTTP2  bool
+		expectedProto string
+	}{
+		{
+			desc:          "HTTP/2 enabled",
+			expectedProto: "HTTP/2.0",
+		},
+		{
+			desc:          "HTTP/2 disabled",
+			disableHTTP2:  true,
+			expectedProto: "HTTP/1.1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			rtManager := NewRoundTripperManager(http.DefaultTransport)
+			rtManager.Update(map[string]*dynamic.ServersTransport{
+				"test": {InsecureSkipVerify: true, DisableHTTP2: test.disableHTTP2},
+			})
+
+			rt, err := rtManager.Get("test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, backend.URL, nil)
+			req.RequestURI = ""
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedProto, resp.Header.Get("X-Proto"))
+		})
+	}
+}
+
+// generateCertificate returns a PEM encoded self-signed certificate and its key, valid for the given name.
+func generateCertificate(t *testing.T, name string) ([]byte, []byte) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM
+}
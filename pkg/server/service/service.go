@@ -8,6 +8,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"reflect"
+	"regexp"
 	"time"
 
 	"github.com/containous/alice"
@@ -23,23 +24,42 @@ import (
 	"github.com/containous/traefik/v2/pkg/safe"
 	"github.com/containous/traefik/v2/pkg/server/cookie"
 	"github.com/containous/traefik/v2/pkg/server/provider"
+	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/failover"
+	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/hedging"
 	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/mirror"
+	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/strategy"
 	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/wrr"
+	"github.com/containous/traefik/v2/pkg/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/vulcand/oxy/roundrobin"
 )
 
 const (
-	defaultHealthCheckInterval = 30 * time.Second
-	defaultHealthCheckTimeout  = 5 * time.Second
+	defaultHealthCheckInterval  = 30 * time.Second
+	defaultHealthCheckTimeout   = 5 * time.Second
+	defaultHealthCheckThreshold = 1
+)
+
+// defaultMirroringMaxBodySize is the default maximum size of the request bodies buffered by the mirroring services,
+// a negative value meaning no limit.
+const defaultMirroringMaxBodySize int64 = -1
+
+const defaultHedgingDelay = 100 * time.Millisecond
+
+const (
+	defaultOutlierConsecutiveErrors  = 5
+	defaultOutlierBaseEjectionTime   = 30 * time.Second
+	defaultOutlierMaxEjectionTime    = 300 * time.Second
+	defaultOutlierMaxEjectionPercent = 10
 )
 
 // NewManager creates a new Manager
-func NewManager(configs map[string]*runtime.ServiceInfo, defaultRoundTripper http.RoundTripper, metricsRegistry metrics.Registry, routinePool *safe.Pool) *Manager {
+func NewManager(configs map[string]*runtime.ServiceInfo, roundTripperManager *RoundTripperManager, metricsRegistry metrics.Registry, routinePool *safe.Pool) *Manager {
 	return &Manager{
 		routinePool:         routinePool,
 		metricsRegistry:     metricsRegistry,
 		bufferPool:          newBufferPool(),
-		defaultRoundTripper: defaultRoundTripper,
+		roundTripperManager: roundTripperManager,
 		balancers:           make(map[string]healthcheck.Balancers),
 		configs:             configs,
 	}
@@ -50,7 +70,7 @@ type Manager struct {
 	routinePool         *safe.Pool
 	metricsRegistry     metrics.Registry
 	bufferPool          httputil.BufferPool
-	defaultRoundTripper http.RoundTripper
+	roundTripperManager *RoundTripperManager
 	// balancers is the map of all Balancers, keyed by service name.
 	// There is one Balancer per service handler, and there is one service handler per reference to a service
 	// (e.g. if 2 routers refer to the same service name, 2 service handlers are created),
@@ -103,7 +123,14 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string, respons
 		}
 	case conf.Mirroring != nil:
 		var err error
-		lb, err = m.getMirrorServiceHandler(ctx, conf.Mirroring, responseModifier)
+		lb, err = m.getMirrorServiceHandler(ctx, serviceName, conf.Mirroring, responseModifier)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+	case conf.Failover != nil:
+		var err error
+		lb, err = m.getFailoverServiceHandler(ctx, serviceName, conf.Failover, responseModifier)
 		if err != nil {
 			conf.AddError(err, true)
 			return nil, err
@@ -117,13 +144,23 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string, respons
 	return lb, nil
 }
 
-func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.Mirroring, responseModifier func(*http.Response) error) (http.Handler, error) {
+func (m *Manager) getMirrorServiceHandler(ctx context.Context, serviceName string, config *dynamic.Mirroring, responseModifier func(*http.Response) error) (http.Handler, error) {
 	serviceHandler, err := m.BuildHTTP(ctx, config.Service, responseModifier)
 	if err != nil {
 		return nil, err
 	}
 
-	handler := mirror.New(serviceHandler, m.routinePool)
+	maxBodySize := defaultMirroringMaxBodySize
+	if config.MaxBodySize != nil {
+		maxBodySize = *config.MaxBodySize
+	}
+
+	var skippedCounter gokitmetrics.Counter
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+		skippedCounter = m.metricsRegistry.ServiceMirrorSkippedCounter().With("service", serviceName)
+	}
+
+	handler := mirror.New(serviceHandler, m.routinePool, maxBodySize, skippedCounter)
 	for _, mirrorConfig := range config.Mirrors {
 		mirrorHandler, err := m.BuildHTTP(ctx, mirrorConfig.Name, responseModifier)
 		if err != nil {
@@ -138,6 +175,41 @@ func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.M
 	return handler, nil
 }
 
+func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName string, config *dynamic.Failover, responseModifier func(*http.Response) error) (http.Handler, error) {
+	f := failover.New()
+
+	serviceHandler, err := m.BuildHTTP(ctx, config.Service, responseModifier)
+	if err != nil {
+		return nil, err
+	}
+
+	f.SetHandler(serviceHandler)
+
+	if err := m.registerChildStatusUpdater(ctx, serviceName, config.Service, serviceHandler, func(up bool) {
+		f.SetHandlerStatus(ctx, up)
+	}); err != nil {
+		return nil, err
+	}
+
+	fallbackHandler, err := m.BuildHTTP(ctx, config.Fallback, responseModifier)
+	if err != nil {
+		return nil, err
+	}
+
+	f.SetFallbackHandler(fallbackHandler)
+
+	// The fallback service is considered always up when its status is not known.
+	if err := m.registerChildStatusUpdater(ctx, serviceName, config.Fallback, fallbackHandler, func(up bool) {
+		f.SetFallbackHandlerStatus(ctx, up)
+	}); err != nil {
+		log.FromContext(ctx).Debugf("Status of the fallback service not tracked: %v", err)
+	}
+
+	m.trackHealthStatus(serviceName, f)
+
+	return f, nil
+}
+
 func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string, config *dynamic.WeightedRoundRobin, responseModifier func(*http.Response) error) (http.Handler, error) {
 	// TODO Handle accesslog and metrics with multiple service name
 	if config.Sticky != nil && config.Sticky.Cookie != nil {
@@ -152,10 +224,73 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 		}
 
 		balancer.AddService(service.Name, serviceHandler, service.Weight)
+
+		// A child service with a non-positive weight is not added to the balancer, so its status is not tracked.
+		if service.Weight != nil && *service.Weight <= 0 {
+			continue
+		}
+
+		// A child service is considered always up when its status is not known.
+		childName := service.Name
+		if err := m.registerChildStatusUpdater(ctx, serviceName, childName, serviceHandler, func(up bool) {
+			balancer.SetStatus(ctx, childName, up)
+		}); err != nil {
+			log.FromContext(ctx).Debugf("Status of the child service not tracked: %v", err)
+		}
 	}
+
+	m.trackHealthStatus(serviceName, balancer)
+
 	return balancer, nil
 }
 
+// registerChildStatusUpdater registers with the handler of the child service a hook propagating its status (UP or DOWN)
+// to its parent service, through setStatus, and to the runtime configuration of the parent service.
+func (m *Manager) registerChildStatusUpdater(ctx context.Context, serviceName, childName string, child http.Handler, setStatus func(up bool)) error {
+	updater, ok := child.(healthcheck.StatusUpdater)
+	if !ok {
+		return fmt.Errorf("child service %v of %v not a healthcheck.StatusUpdater (%T)", childName, serviceName, child)
+	}
+
+	conf := m.configs[serviceName]
+	childName = provider.GetQualifiedName(ctx, childName)
+
+	if err := updater.RegisterStatusUpdater(func(up bool) {
+		setStatus(up)
+		if conf != nil {
+			conf.UpdateServerStatus(childName, statusString(up))
+		}
+	}); err != nil {
+		return fmt.Errorf("cannot register %v as updater for %v: %w", childName, serviceName, err)
+	}
+
+	if conf != nil && conf.GetAllStatus()[childName] == "" {
+		conf.UpdateServerStatus(childName, statusString(true))
+	}
+
+	return nil
+}
+
+// trackHealthStatus keeps track of the aggregated status of the given service in its runtime configuration.
+func (m *Manager) trackHealthStatus(serviceName string, updater healthcheck.StatusUpdater) {
+	conf := m.configs[serviceName]
+	if conf == nil {
+		return
+	}
+
+	conf.UpdateHealthStatus(statusString(true))
+	_ = updater.RegisterStatusUpdater(func(up bool) {
+		conf.UpdateHealthStatus(statusString(up))
+	})
+}
+
+func statusString(up bool) string {
+	if up {
+		return "UP"
+	}
+	return "DOWN"
+}
+
 func (m *Manager) getLoadBalancerServiceHandler(
 	ctx context.Context,
 	serviceName string,
@@ -167,7 +302,12 @@ func (m *Manager) getLoadBalancerServiceHandler(
 		service.PassHostHeader = &defaultPassHostHeader
 	}
 
-	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, m.defaultRoundTripper, m.bufferPool, responseModifier)
+	roundTripper, err := m.getLoadBalancerRoundTripper(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, roundTripper, m.bufferPool, responseModifier)
 	if err != nil {
 		return nil, err
 	}
@@ -185,24 +325,68 @@ func (m *Manager) getLoadBalancerServiceHandler(
 		return nil, err
 	}
 
+	var outlierDetector *healthcheck.OutlierDetector
+	if service.OutlierDetection != nil {
+		outlierDetector = m.buildOutlierDetector(ctx, serviceName, service.OutlierDetection)
+		handler = outlierDetector.Handler(handler)
+	}
+
+	var hedger *hedging.Hedger
+	if service.Hedging != nil {
+		hedger = m.buildHedger(ctx, serviceName, service.Hedging)
+		handler = hedger.Forwarder(handler)
+	}
+
 	balancer, err := m.getLoadBalancer(ctx, serviceName, service, handler)
 	if err != nil {
 		return nil, err
 	}
 
+	if outlierDetector != nil {
+		outlierDetector.SetBalancer(balancer)
+	}
+
 	// TODO rename and checks
 	m.balancers[serviceName] = append(m.balancers[serviceName], balancer)
 
+	if hedger != nil {
+		hedger.SetBalancer(balancer)
+		return emptybackendhandler.New(hedger), nil
+	}
+
 	// Empty (backend with no servers)
 	return emptybackendhandler.New(balancer), nil
 }
 
+func (m *Manager) getRoundTripper(ctx context.Context, serversTransportName string) (http.RoundTripper, error) {
+	if serversTransportName == "" {
+		return m.roundTripperManager.Get("")
+	}
+
+	return m.roundTripperManager.Get(provider.GetQualifiedName(ctx, serversTransportName))
+}
+
+// getLoadBalancerRoundTripper returns the round tripper used to reach the servers of the given load-balancer.
+func (m *Manager) getLoadBalancerRoundTripper(ctx context.Context, service *dynamic.ServersLoadBalancer) (http.RoundTripper, error) {
+	roundTripper, err := m.getRoundTripper(ctx, service.ServersTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	if service.ProxyProtocol == nil {
+		return roundTripper, nil
+	}
+
+	return newProxyProtocolRoundTripper(roundTripper, service.ProxyProtocol)
+}
+
 // LaunchHealthCheck Launches the health checks.
 func (m *Manager) LaunchHealthCheck() {
 	backendConfigs := make(map[string]*healthcheck.BackendConfig)
 
 	for serviceName, balancers := range m.balancers {
 		ctx := log.With(context.Background(), log.Str(log.ServiceName, serviceName))
+		ctx = provider.AddInContext(ctx, serviceName)
 
 		// TODO Should all the services handle healthcheck? Handle different types
 		service := m.configs[serviceName].LoadBalancer
@@ -212,7 +396,13 @@ func (m *Manager) LaunchHealthCheck() {
 		if hcOpts := buildHealthCheckOptions(ctx, balancers, serviceName, service.HealthCheck); hcOpts != nil {
 			log.FromContext(ctx).Debugf("Setting up healthcheck for service %s with %s", serviceName, *hcOpts)
 
-			hcOpts.Transport = m.defaultRoundTripper
+			roundTripper, err := m.getLoadBalancerRoundTripper(ctx, service)
+			if err != nil {
+				log.FromContext(ctx).Errorf("Cannot set up the health check transport: %v", err)
+				continue
+			}
+
+			hcOpts.Transport = roundTripper
 			backendHealthCheck = healthcheck.NewBackendConfig(*hcOpts, serviceName)
 		}
 
@@ -225,13 +415,29 @@ func (m *Manager) LaunchHealthCheck() {
 	healthcheck.GetHealthCheck().SetBackendsConfiguration(context.Background(), backendConfigs)
 }
 
+// healthCheckEnabled reports whether the given configuration enables the active health check,
+// i.e. whether it has a path, or uses the gRPC mode.
+func healthCheckEnabled(hc *dynamic.HealthCheck) bool {
+	return hc != nil && (hc.Path != "" || hc.Mode == healthcheck.ModeGRPC)
+}
+
 func buildHealthCheckOptions(ctx context.Context, lb healthcheck.Balancer, backend string, hc *dynamic.HealthCheck) *healthcheck.Options {
-	if hc == nil || hc.Path == "" {
+	if !healthCheckEnabled(hc) {
 		return nil
 	}
 
 	logger := log.FromContext(ctx)
 
+	mode := healthcheck.ModeHTTP
+	switch hc.Mode {
+	case "", healthcheck.ModeHTTP:
+	case healthcheck.ModeGRPC:
+		mode = healthcheck.ModeGRPC
+	default:
+		logger.Errorf("Illegal health check mode for backend '%s': %s", backend, hc.Mode)
+		return nil
+	}
+
 	interval := defaultHealthCheckInterval
 	if hc.Interval != "" {
 		intervalOverride, err := time.ParseDuration(hc.Interval)
@@ -267,23 +473,147 @@ func buildHealthCheckOptions(ctx context.Context, lb healthcheck.Balancer, backe
 		followRedirects = *hc.FollowRedirects
 	}
 
+	expectedStatus, err := types.NewHTTPCodeRanges(hc.ExpectedStatus)
+	if err != nil {
+		logger.Errorf("Illegal health check expected status for backend '%s': %s", backend, err)
+		expectedStatus = nil
+	}
+
+	var expectedBody *regexp.Regexp
+	if hc.ExpectedBody != "" {
+		expectedBody, err = regexp.Compile(hc.ExpectedBody)
+		if err != nil {
+			logger.Errorf("Illegal health check expected body for backend '%s': %s", backend, err)
+			expectedBody = nil
+		}
+	}
+
+	healthyThreshold := defaultHealthCheckThreshold
+	if hc.HealthyThreshold > 0 {
+		healthyThreshold = hc.HealthyThreshold
+	}
+
+	unhealthyThreshold := defaultHealthCheckThreshold
+	if hc.UnhealthyThreshold > 0 {
+		unhealthyThreshold = hc.UnhealthyThreshold
+	}
+
 	return &healthcheck.Options{
-		Scheme:          hc.Scheme,
-		Path:            hc.Path,
-		Port:            hc.Port,
-		Interval:        interval,
-		Timeout:         timeout,
-		LB:              lb,
-		Hostname:        hc.Hostname,
-		Headers:         hc.Headers,
-		FollowRedirects: followRedirects,
+		Mode:               mode,
+		Scheme:             hc.Scheme,
+		Path:               hc.Path,
+		Port:               hc.Port,
+		Interval:           interval,
+		Timeout:            timeout,
+		LB:                 lb,
+		Hostname:           hc.Hostname,
+		Headers:            hc.Headers,
+		FollowRedirects:    followRedirects,
+		ExpectedStatus:     expectedStatus,
+		ExpectedBody:       expectedBody,
+		HealthyThreshold:   healthyThreshold,
+		UnhealthyThreshold: unhealthyThreshold,
+	}
+}
+
+func (m *Manager) buildOutlierDetector(ctx context.Context, serviceName string, config *dynamic.OutlierDetection) *healthcheck.OutlierDetector {
+	options := healthcheck.OutlierOptions{
+		ConsecutiveErrors:  defaultOutlierConsecutiveErrors,
+		BaseEjectionTime:   defaultOutlierBaseEjectionTime,
+		MaxEjectionTime:    defaultOutlierMaxEjectionTime,
+		MaxEjectionPercent: defaultOutlierMaxEjectionPercent,
+	}
+
+	if config.ConsecutiveErrors > 0 {
+		options.ConsecutiveErrors = config.ConsecutiveErrors
+	}
+	if config.BaseEjectionTime > 0 {
+		options.BaseEjectionTime = time.Duration(config.BaseEjectionTime)
+	}
+	if config.MaxEjectionTime > 0 {
+		options.MaxEjectionTime = time.Duration(config.MaxEjectionTime)
+	}
+	if config.MaxEjectionPercent > 0 {
+		options.MaxEjectionPercent = config.MaxEjectionPercent
 	}
+
+	if options.MaxEjectionTime < options.BaseEjectionTime {
+		log.FromContext(ctx).Warnf("Outlier detection maximum ejection time for service '%s' is lower than the base ejection time, using the base ejection time.", serviceName)
+		options.MaxEjectionTime = options.BaseEjectionTime
+	}
+
+	log.FromContext(ctx).Debugf("Setting up outlier detection for service %s with %s", serviceName, options)
+
+	var gauge gokitmetrics.Gauge
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+		gauge = m.metricsRegistry.ServiceServerUpGauge()
+	}
+
+	return healthcheck.NewOutlierDetector(ctx, serviceName, options, gauge)
+}
+
+func (m *Manager) buildHedger(ctx context.Context, serviceName string, config *dynamic.Hedging) *hedging.Hedger {
+	delay := defaultHedgingDelay
+	if config.Delay > 0 {
+		delay = time.Duration(config.Delay)
+	}
+
+	log.FromContext(ctx).Debugf("Setting up request hedging for service %s with a delay of %s", serviceName, delay)
+
+	var counter gokitmetrics.Counter
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+		counter = m.metricsRegistry.ServiceHedgedRequestsCounter().With("service", serviceName)
+	}
+
+	return hedging.New(serviceName, delay, counter)
 }
 
 func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer, fwd http.Handler) (healthcheck.BalancerHandler, error) {
 	logger := log.FromContext(ctx)
 	logger.Debug("Creating load-balancer")
 
+	lb, err := newBalancer(ctx, serviceName, service, fwd)
+	if err != nil {
+		return nil, err
+	}
+
+	wantsHealthCheck := healthCheckEnabled(service.HealthCheck) || service.OutlierDetection != nil
+	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName], wantsHealthCheck)
+	if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
+		return nil, fmt.Errorf("error configuring load balancer for service %s: %v", serviceName, err)
+	}
+
+	return lbsu, nil
+}
+
+func newBalancer(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer, fwd http.Handler) (healthcheck.BalancerHandler, error) {
+	if service.Strategy == "" || service.Strategy == dynamic.RoundRobinStrategy {
+		return newRoundRobin(ctx, serviceName, service, fwd)
+	}
+
+	if service.Sticky != nil {
+		return nil, fmt.Errorf("sticky sessions are not supported with the %s strategy", service.Strategy)
+	}
+
+	log.FromContext(ctx).Debugf("Load-balancing strategy: %s", service.Strategy)
+
+	switch service.Strategy {
+	case dynamic.LeastConnectionsStrategy:
+		return strategy.NewLeastConnections(fwd), nil
+	case dynamic.PowerOfTwoChoicesStrategy:
+		return strategy.NewPowerOfTwoChoices(fwd), nil
+	case dynamic.PeakEWMAStrategy:
+		return strategy.NewPeakEWMA(fwd), nil
+	case dynamic.ConsistentHashStrategy:
+		return strategy.NewConsistentHash(fwd, service.ConsistentHash), nil
+	default:
+		return nil, fmt.Errorf("unknown load-balancing strategy %q", service.Strategy)
+	}
+}
+
+func newRoundRobin(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer, fwd http.Handler) (healthcheck.BalancerHandler, error) {
+	logger := log.FromContext(ctx)
+
 	var options []roundrobin.LBOption
 
 	var cookieName string
@@ -294,17 +624,7 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 		logger.Debugf("Sticky session cookie name: %v", cookieName)
 	}
 
-	lb, err := roundrobin.New(fwd, options...)
-	if err != nil {
-		return nil, err
-	}
-
-	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName])
-	if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
-		return nil, fmt.Errorf("error configuring load balancer for service %s: %v", serviceName, err)
-	}
-
-	return lbsu, nil
+	return roundrobin.New(fwd, options...)
 }
 
 func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHandler, servers []dynamic.Server) error {
@@ -316,9 +636,19 @@ func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHand
 			return fmt.Errorf("error parsing server URL %s: %v", srv.URL, err)
 		}
 
-		logger.WithField(log.ServerName, name).Debugf("Creating server %d %s", name, u)
+		weight := 1
+		if srv.Weight != nil {
+			weight = *srv.Weight
+		}
+
+		if weight == 0 {
+			logger.WithField(log.ServerName, name).Debugf("Skipping server %d %s with a weight of 0", name, u)
+			continue
+		}
+
+		logger.WithField(log.ServerName, name).Debugf("Creating server %d %s with weight %d", name, u, weight)
 
-		if err := lb.UpsertServer(u, roundrobin.Weight(1)); err != nil {
+		if err := healthcheck.UpsertWeightedServer(lb, u, weight); err != nil {
 			return fmt.Errorf("error adding server %s to load balancer: %v", srv.URL, err)
 		}
 
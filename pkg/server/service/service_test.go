@@ -6,11 +6,13 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -51,6 +53,63 @@ func TestGetLoadBalancer(t *testing.T) {
 			fwd:         &MockForwarder{},
 			expectError: false,
 		},
+		{
+			desc:        "Fails when provided a negative weight",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Servers: []dynamic.Server{
+					{
+						URL:    "http://foo",
+						Weight: func(v int) *int { return &v }(-1),
+					},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Succeeds with the least connections strategy",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: dynamic.LeastConnectionsStrategy,
+				Servers: []dynamic.Server{
+					{
+						URL: "http://foo",
+					},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Succeeds with the consistent hash strategy",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy:       dynamic.ConsistentHashStrategy,
+				ConsistentHash: &dynamic.ConsistentHash{Header: "X-User"},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when provided an unknown strategy",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: "Random",
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Fails when sticky.cookie is set with a strategy other than round robin",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: dynamic.PeakEWMAStrategy,
+				Sticky:   &dynamic.Sticky{Cookie: &dynamic.Cookie{}},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
 		{
 			desc:        "Succeeds when sticky.cookie is set",
 			serviceName: "test",
@@ -80,7 +139,7 @@ func TestGetLoadBalancer(t *testing.T) {
 }
 
 func TestGetLoadBalancerServiceHandler(t *testing.T) {
-	sm := NewManager(nil, http.DefaultTransport, nil, nil)
+	sm := NewManager(nil, NewRoundTripperManager(http.DefaultTransport), nil, nil)
 
 	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-From", "first")
@@ -104,6 +163,12 @@ func TestGetLoadBalancerServiceHandler(t *testing.T) {
 	}))
 	defer serverPassHostFalse.Close()
 
+	serverError := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-From", "error")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer serverError.Close()
+
 	type ExpectedResult struct {
 		StatusCode     int
 		XFrom          string
@@ -143,6 +208,60 @@ func TestGetLoadBalancerServiceHandler(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:        "Load balances between the two servers according to their weights",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Servers: []dynamic.Server{
+					{
+						URL:    server1.URL,
+						Weight: func(v int) *int { return &v }(2),
+					},
+					{
+						URL: server2.URL,
+					},
+				},
+			},
+			expected: []ExpectedResult{
+				{
+					StatusCode: http.StatusOK,
+					XFrom:      "first",
+				},
+				{
+					StatusCode: http.StatusOK,
+					XFrom:      "first",
+				},
+				{
+					StatusCode: http.StatusOK,
+					XFrom:      "second",
+				},
+			},
+		},
+		{
+			desc:        "Skips the servers with a weight of 0",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Servers: []dynamic.Server{
+					{
+						URL:    server1.URL,
+						Weight: func(v int) *int { return &v }(0),
+					},
+					{
+						URL: server2.URL,
+					},
+				},
+			},
+			expected: []ExpectedResult{
+				{
+					StatusCode: http.StatusOK,
+					XFrom:      "second",
+				},
+				{
+					StatusCode: http.StatusOK,
+					XFrom:      "second",
+				},
+			},
+		},
 		{
 			desc:        "StatusBadGateway when the server is not reachable",
 			serviceName: "test",
@@ -171,6 +290,39 @@ func TestGetLoadBalancerServiceHandler(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:        "Ejects the server responding with errors when outlier detection is enabled",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				OutlierDetection: &dynamic.OutlierDetection{
+					ConsecutiveErrors:  1,
+					BaseEjectionTime:   types.Duration(time.Minute),
+					MaxEjectionPercent: 50,
+				},
+				Servers: []dynamic.Server{
+					{
+						URL: serverError.URL,
+					},
+					{
+						URL: server2.URL,
+					},
+				},
+			},
+			expected: []ExpectedResult{
+				{
+					StatusCode: http.StatusInternalServerError,
+					XFrom:      "error",
+				},
+				{
+					StatusCode: http.StatusOK,
+					XFrom:      "second",
+				},
+				{
+					StatusCode: http.StatusOK,
+					XFrom:      "second",
+				},
+			},
+		},
 		{
 			desc:        "Always call the same server when sticky.cookie is true",
 			serviceName: "test",
@@ -332,7 +484,7 @@ func TestManager_Build(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			manager := NewManager(test.configs, http.DefaultTransport, nil, nil)
+			manager := NewManager(test.configs, NewRoundTripperManager(http.DefaultTransport), nil, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -345,6 +497,59 @@ func TestManager_Build(t *testing.T) {
 	}
 }
 
+func TestManager_BuildServersTransport(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		serversTransport string
+		expectError      bool
+	}{
+		{
+			desc:             "Servers transport name with provider in context",
+			serversTransport: "transport",
+		},
+		{
+			desc:             "Servers transport name with provider",
+			serversTransport: "transport@provider-1",
+		},
+		{
+			desc:             "Unknown servers transport",
+			serversTransport: "unknown",
+			expectError:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtManager := NewRoundTripperManager(http.DefaultTransport)
+			rtManager.Update(map[string]*dynamic.ServersTransport{
+				"transport@provider-1": {},
+			})
+
+			configs := map[string]*runtime.ServiceInfo{
+				"serviceName@provider-1": {
+					Service: &dynamic.Service{
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							ServersTransport: test.serversTransport,
+						},
+					},
+				},
+			}
+
+			manager := NewManager(configs, rtManager, nil, nil)
+
+			_, err := manager.BuildHTTP(context.Background(), "serviceName@provider-1", nil)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 	services := map[string]*runtime.ServiceInfo{
 		"test@file": {
@@ -355,10 +560,237 @@ func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 		},
 	}
 
-	manager := NewManager(services, http.DefaultTransport, nil, nil)
+	manager := NewManager(services, NewRoundTripperManager(http.DefaultTransport), nil, nil)
 
 	_, err := manager.BuildHTTP(context.Background(), "test@file", nil)
 	assert.Error(t, err, "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 }
 
+func TestManager_BuildFailover(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+		}))
+	}
+
+	mainServer := newServer("main")
+	defer mainServer.Close()
+
+	fallbackServer := newServer("fallback")
+	defer fallbackServer.Close()
+
+	services := map[string]*runtime.ServiceInfo{
+		"main@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers:     []dynamic.Server{{URL: mainServer.URL}},
+					HealthCheck: &dynamic.HealthCheck{Path: "/health"},
+				},
+			},
+		},
+		"fallback@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers: []dynamic.Server{{URL: fallbackServer.URL}},
+				},
+			},
+		},
+		"failover@file": {
+			Service: &dynamic.Service{
+				Failover: &dynamic.Failover{
+					Service:  "main@file",
+					Fallback: "fallback@file",
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, NewRoundTripperManager(http.DefaultTransport), nil, nil)
+
+	handler, err := manager.BuildHTTP(context.Background(), "failover@file", nil)
+	require.NoError(t, err)
+
+	serve := func() string {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.com/", nil))
+		return recorder.Header().Get("server")
+	}
+
+	assert.Equal(t, "main", serve())
+
+	// The health check removes the only server of the main service.
+	mainURL := testhelpers.MustParseURL(mainServer.URL)
+	require.NoError(t, manager.balancers["main@file"].RemoveServer(mainURL))
+	assert.Equal(t, "fallback", serve())
+
+	require.NoError(t, manager.balancers["main@file"].UpsertServer(mainURL))
+	assert.Equal(t, "main", serve())
+}
+
+func TestManager_BuildWeightedHealthPropagation(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+		}))
+	}
+
+	serverA := newServer("A")
+	defer serverA.Close()
+
+	serverB := newServer("B")
+	defer serverB.Close()
+
+	services := map[string]*runtime.ServiceInfo{
+		"serviceA@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers:     []dynamic.Server{{URL: serverA.URL}},
+					HealthCheck: &dynamic.HealthCheck{Path: "/health"},
+				},
+			},
+		},
+		"serviceB@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers:     []dynamic.Server{{URL: serverB.URL}},
+					HealthCheck: &dynamic.HealthCheck{Path: "/health"},
+				},
+			},
+		},
+		"weighted@file": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{{Name: "serviceA"}, {Name: "serviceB"}},
+				},
+			},
+		},
+		"top@file": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{{Name: "weighted"}},
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, NewRoundTripperManager(http.DefaultTransport), nil, nil)
+
+	handler, err := manager.BuildHTTP(context.Background(), "top@file", nil)
+	require.NoError(t, err)
+
+	serve := func(count int) map[string]int {
+		servers := make(map[string]int)
+		for i := 0; i < count; i++ {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.com/", nil))
+			servers[recorder.Header().Get("server")]++
+		}
+		return servers
+	}
+
+	assert.Equal(t, map[string]int{"A": 2, "B": 2}, serve(4))
+	assert.Equal(t, "UP", services["weighted@file"].GetHealthStatus())
+	assert.Equal(t, map[string]string{"serviceA@file": "UP", "serviceB@file": "UP"}, services["weighted@file"].GetAllStatus())
+
+	// The health check removes the only server of serviceA.
+	urlA := testhelpers.MustParseURL(serverA.URL)
+	require.NoError(t, manager.balancers["serviceA@file"].RemoveServer(urlA))
+
+	assert.Equal(t, map[string]int{"B": 4}, serve(4))
+	assert.Equal(t, "DOWN", services["serviceA@file"].GetHealthStatus())
+	assert.Equal(t, "UP", services["weighted@file"].GetHealthStatus())
+	assert.Equal(t, map[string]string{"serviceA@file": "DOWN", "serviceB@file": "UP"}, services["weighted@file"].GetAllStatus())
+
+	// All the servers are down, and the status is propagated to the top service.
+	urlB := testhelpers.MustParseURL(serverB.URL)
+	require.NoError(t, manager.balancers["serviceB@file"].RemoveServer(urlB))
+
+	assert.Equal(t, map[string]int{"": 1}, serve(1))
+	assert.Equal(t, "DOWN", services["weighted@file"].GetHealthStatus())
+	assert.Equal(t, "DOWN", services["top@file"].GetHealthStatus())
+	assert.Equal(t, map[string]string{"weighted@file": "DOWN"}, services["top@file"].GetAllStatus())
+
+	require.NoError(t, manager.balancers["serviceA@file"].UpsertServer(urlA))
+
+	assert.Equal(t, map[string]int{"A": 4}, serve(4))
+	assert.Equal(t, "UP", services["top@file"].GetHealthStatus())
+}
+
+func TestManager_BuildWeightedZeroWeightHealthPropagation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+
+	services := map[string]*runtime.ServiceInfo{
+		"serviceA@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers:     []dynamic.Server{{URL: server.URL}},
+					HealthCheck: &dynamic.HealthCheck{Path: "/health"},
+				},
+			},
+		},
+		"serviceB@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					HealthCheck: &dynamic.HealthCheck{Path: "/health"},
+				},
+			},
+		},
+		"weighted@file": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{
+						{Name: "serviceA"},
+						{Name: "serviceB", Weight: func(v int) *int { return &v }(0)},
+					},
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, NewRoundTripperManager(http.DefaultTransport), nil, nil)
+
+	handler, err := manager.BuildHTTP(context.Background(), "weighted@file", nil)
+	require.NoError(t, err)
+
+	// The health check removes the only server of serviceA, then the zero-weight serviceB goes up.
+	url := testhelpers.MustParseURL(server.URL)
+	require.NoError(t, manager.balancers["serviceA@file"].RemoveServer(url))
+	require.NoError(t, manager.balancers["serviceB@file"].UpsertServer(url))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.com/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "DOWN", services["weighted@file"].GetHealthStatus())
+}
+
+func TestManager_BuildFailoverWithoutHealthCheck(t *testing.T) {
+	services := map[string]*runtime.ServiceInfo{
+		"main@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{},
+			},
+		},
+		"fallback@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{},
+			},
+		},
+		"failover@file": {
+			Service: &dynamic.Service{
+				Failover: &dynamic.Failover{
+					Service:  "main@file",
+					Fallback: "fallback@file",
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, NewRoundTripperManager(http.DefaultTransport), nil, nil)
+
+	_, err := manager.BuildHTTP(context.Background(), "failover@file", nil)
+	assert.Error(t, err)
+}
+
 // FIXME Add healthcheck tests
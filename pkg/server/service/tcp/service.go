@@ -7,7 +7,9 @@ import (
 	"net"
 	"time"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	"github.com/containous/traefik/v2/pkg/tcp"
@@ -16,12 +18,15 @@ import (
 // Manager is the TCPHandlers factory
 type Manager struct {
 	configs map[string]*runtime.TCPServiceInfo
+	// healthChecks is the map of the health check configurations, keyed by service name.
+	healthChecks map[string]*healthcheck.TCPServiceConfig
 }
 
 // NewManager creates a new manager
 func NewManager(conf *runtime.Configuration) *Manager {
 	return &Manager{
-		configs: conf.TCPServices,
+		configs:      conf.TCPServices,
+		healthChecks: make(map[string]*healthcheck.TCPServiceConfig),
 	}
 }
 
@@ -53,21 +58,31 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		}
 		duration := time.Duration(*conf.LoadBalancer.TerminationDelay) * time.Millisecond
 
+		proxyProtocolVersion := getProxyProtocolVersion(conf.LoadBalancer.ProxyProtocol)
+
+		handlers := make(map[string]tcp.Handler)
 		for name, server := range conf.LoadBalancer.Servers {
 			if _, _, err := net.SplitHostPort(server.Address); err != nil {
 				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
 				continue
 			}
 
-			handler, err := tcp.NewProxy(server.Address, duration)
+			handler, err := tcp.NewProxy(server.Address, duration, proxyProtocolVersion)
 			if err != nil {
 				logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
 			}
 
 			loadBalancer.AddServer(handler)
+			handlers[server.Address] = handler
+			conf.UpdateServerStatus(server.Address, "UP")
 			logger.WithField(log.ServerName, name).Debugf("Creating TCP server %d at %s", name, server.Address)
 		}
+
+		if conf.LoadBalancer.HealthCheck != nil {
+			m.addHealthCheck(ctx, serviceQualifiedName, conf, loadBalancer, handlers)
+		}
+
 		return loadBalancer, nil
 	case conf.Weighted != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
@@ -86,3 +101,47 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		return nil, err
 	}
 }
+
+func (m *Manager) addHealthCheck(ctx context.Context, serviceName string, conf *runtime.TCPServiceInfo, lb healthcheck.TCPBalancer, handlers map[string]tcp.Handler) {
+	if hc, ok := m.healthChecks[serviceName]; ok {
+		hc.AddBalancer(lb, handlers)
+		return
+	}
+
+	hcOpts := buildHealthCheckOptions(ctx, serviceName, conf.LoadBalancer.HealthCheck)
+	hcOpts.ProxyProtocolVersion = getProxyProtocolVersion(conf.LoadBalancer.ProxyProtocol)
+	log.FromContext(ctx).Debugf("Setting up healthcheck for TCP service %s with %s", serviceName, hcOpts)
+
+	hc := healthcheck.NewTCPServiceConfig(hcOpts, serviceName, conf)
+	hc.AddBalancer(lb, handlers)
+	m.healthChecks[serviceName] = hc
+}
+
+// LaunchHealthCheck Launches the health checks.
+func (m *Manager) LaunchHealthCheck() {
+	healthcheck.GetTCPHealthCheck().SetServicesConfiguration(context.Background(), m.healthChecks)
+}
+
+// getProxyProtocolVersion returns the PROXY protocol version to send to the servers,
+// 0 if it is disabled, and 2 if it is enabled without an explicit version.
+func getProxyProtocolVersion(proxyProtocol *dynamic.ProxyProtocol) int {
+	if proxyProtocol == nil {
+		return 0
+	}
+	if proxyProtocol.Version == 0 {
+		return 2
+	}
+	return proxyProtocol.Version
+}
+
+func buildHealthCheckOptions(ctx context.Context, serviceName string, hc *dynamic.TCPHealthCheck) healthcheck.TCPOptions {
+	interval, timeout := healthcheck.ParseNetworkDurations(ctx, serviceName, hc.Interval, hc.Timeout)
+
+	return healthcheck.TCPOptions{
+		Port:     hc.Port,
+		Send:     hc.Send,
+		Expect:   hc.Expect,
+		Interval: interval,
+		Timeout:  timeout,
+	}
+}
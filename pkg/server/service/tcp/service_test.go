@@ -3,6 +3,7 @@ package tcp
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
@@ -199,3 +200,39 @@ func TestManager_BuildTCP(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_BuildTCP_HealthCheck(t *testing.T) {
+	conf := &runtime.TCPServiceInfo{
+		TCPService: &dynamic.TCPService{
+			LoadBalancer: &dynamic.TCPServersLoadBalancer{
+				Servers: []dynamic.TCPServer{
+					{Address: "127.0.0.1:8080"},
+					{Address: "127.0.0.1:8081"},
+				},
+				HealthCheck: &dynamic.TCPHealthCheck{
+					Interval: "10s",
+				},
+			},
+		},
+	}
+
+	manager := NewManager(&runtime.Configuration{
+		TCPServices: map[string]*runtime.TCPServiceInfo{"test@file": conf},
+	})
+
+	ctx := provider.AddInContext(context.Background(), "router@file")
+
+	// Two routers referencing the same service.
+	_, err := manager.BuildTCP(ctx, "test")
+	require.NoError(t, err)
+	_, err = manager.BuildTCP(ctx, "test")
+	require.NoError(t, err)
+
+	require.Len(t, manager.healthChecks, 1)
+	hc := manager.healthChecks["test@file"]
+	require.NotNil(t, hc)
+	assert.Equal(t, 10*time.Second, hc.Interval)
+	assert.Equal(t, 5*time.Second, hc.Timeout)
+
+	assert.Equal(t, map[string]string{"127.0.0.1:8080": "UP", "127.0.0.1:8081": "UP"}, conf.GetAllStatus())
+}
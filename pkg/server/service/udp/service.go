@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	"github.com/containous/traefik/v2/pkg/udp"
@@ -15,12 +17,15 @@ import (
 // Manager handles UDP services creation.
 type Manager struct {
 	configs map[string]*runtime.UDPServiceInfo
+	// healthChecks is the map of the health check configurations, keyed by service name.
+	healthChecks map[string]*healthcheck.UDPServiceConfig
 }
 
 // NewManager creates a new manager
 func NewManager(conf *runtime.Configuration) *Manager {
 	return &Manager{
-		configs: conf.UDPServices,
+		configs:      conf.UDPServices,
+		healthChecks: make(map[string]*healthcheck.UDPServiceConfig),
 	}
 }
 
@@ -46,6 +51,7 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	case conf.LoadBalancer != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
 
+		handlers := make(map[string]udp.Handler)
 		for name, server := range conf.LoadBalancer.Servers {
 			if _, _, err := net.SplitHostPort(server.Address); err != nil {
 				logger.Errorf("In udp service %q: %v", serviceQualifiedName, err)
@@ -59,8 +65,15 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 			}
 
 			loadBalancer.AddServer(handler)
+			handlers[server.Address] = handler
+			conf.UpdateServerStatus(server.Address, "UP")
 			logger.WithField(log.ServerName, name).Debugf("Creating UDP server %d at %s", name, server.Address)
 		}
+
+		if conf.LoadBalancer.HealthCheck != nil {
+			m.addHealthCheck(ctx, serviceQualifiedName, conf, loadBalancer, handlers)
+		}
+
 		return loadBalancer, nil
 	case conf.Weighted != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
@@ -79,3 +92,34 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 		return nil, err
 	}
 }
+
+func (m *Manager) addHealthCheck(ctx context.Context, serviceName string, conf *runtime.UDPServiceInfo, lb healthcheck.UDPBalancer, handlers map[string]udp.Handler) {
+	if hc, ok := m.healthChecks[serviceName]; ok {
+		hc.AddBalancer(lb, handlers)
+		return
+	}
+
+	hcOpts := buildHealthCheckOptions(ctx, serviceName, conf.LoadBalancer.HealthCheck)
+	log.FromContext(ctx).Debugf("Setting up healthcheck for UDP service %s with %s", serviceName, hcOpts)
+
+	hc := healthcheck.NewUDPServiceConfig(hcOpts, serviceName, conf)
+	hc.AddBalancer(lb, handlers)
+	m.healthChecks[serviceName] = hc
+}
+
+// LaunchHealthCheck Launches the health checks.
+func (m *Manager) LaunchHealthCheck() {
+	healthcheck.GetUDPHealthCheck().SetServicesConfiguration(context.Background(), m.healthChecks)
+}
+
+func buildHealthCheckOptions(ctx context.Context, serviceName string, hc *dynamic.UDPHealthCheck) healthcheck.UDPOptions {
+	interval, timeout := healthcheck.ParseNetworkDurations(ctx, serviceName, hc.Interval, hc.Timeout)
+
+	return healthcheck.UDPOptions{
+		Port:     hc.Port,
+		Send:     hc.Send,
+		Expect:   hc.Expect,
+		Interval: interval,
+		Timeout:  timeout,
+	}
+}
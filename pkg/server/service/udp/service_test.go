@@ -3,6 +3,7 @@ package udp
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
@@ -199,3 +200,41 @@ func TestManager_BuildUDP(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_BuildUDP_HealthCheck(t *testing.T) {
+	conf := &runtime.UDPServiceInfo{
+		UDPService: &dynamic.UDPService{
+			LoadBalancer: &dynamic.UDPServersLoadBalancer{
+				Servers: []dynamic.UDPServer{
+					{Address: "127.0.0.1:8080"},
+					{Address: "127.0.0.1:8081"},
+				},
+				HealthCheck: &dynamic.UDPHealthCheck{
+					Send:    "PING",
+					Timeout: "1s",
+				},
+			},
+		},
+	}
+
+	manager := NewManager(&runtime.Configuration{
+		UDPServices: map[string]*runtime.UDPServiceInfo{"test@file": conf},
+	})
+
+	ctx := provider.AddInContext(context.Background(), "router@file")
+
+	// Two routers referencing the same service.
+	_, err := manager.BuildUDP(ctx, "test")
+	require.NoError(t, err)
+	_, err = manager.BuildUDP(ctx, "test")
+	require.NoError(t, err)
+
+	require.Len(t, manager.healthChecks, 1)
+	hc := manager.healthChecks["test@file"]
+	require.NotNil(t, hc)
+	assert.Equal(t, "PING", hc.Send)
+	assert.Equal(t, 30*time.Second, hc.Interval)
+	assert.Equal(t, time.Second, hc.Timeout)
+
+	assert.Equal(t, map[string]string{"127.0.0.1:8080": "UP", "127.0.0.1:8081": "UP"}, conf.GetAllStatus())
+}
@@ -0,0 +1,69 @@
+package tcp
+
+import (
+	"errors"
+)
+
+// Constructor A constructor for a piece of TCP middleware.
+// Some TCP middleware use this constructor out of the box,
+// so in most cases you can just pass somepackage.New.
+type Constructor func(Handler) (Handler, error)
+
+// Chain is a chain for TCP handlers.
+// Chain is effectively immutable:
+// once created, it will always hold
+// the same set of constructors in the same order.
+type Chain struct {
+	constructors []Constructor
+}
+
+// NewChain creates a new TCP chain,
+// memorizing the given list of TCP middleware constructors.
+// New serves no other function,
+// constructors are only called upon a call to Then().
+func NewChain(constructors ...Constructor) Chain {
+	return Chain{constructors: constructors}
+}
+
+// Then chains the middleware and returns the final TCP handler.
+//     NewChain(m1, m2, m3).Then(h)
+// is equivalent to:
+//     m1(m2(m3(h)))
+// When the connection comes in, it will be passed to m1, then m2, then m3
+// and finally, the given handler
+// (assuming every middleware calls the following one).
+func (c Chain) Then(h Handler) (Handler, error) {
+	if h == nil {
+		return nil, errors.New("cannot add a nil handler to the chain")
+	}
+
+	for i := range c.constructors {
+		handler, err := c.constructors[len(c.constructors)-1-i](h)
+		if err != nil {
+			return nil, err
+		}
+		h = handler
+	}
+
+	return h, nil
+}
+
+// Append extends a chain, adding the specified constructors
+// as the last ones in the connection flow.
+//
+// Append returns a new chain, leaving the original one untouched.
+func (c Chain) Append(constructors ...Constructor) Chain {
+	newCons := make([]Constructor, 0, len(c.constructors)+len(constructors))
+	newCons = append(newCons, c.constructors...)
+	newCons = append(newCons, constructors...)
+
+	return Chain{newCons}
+}
+
+// Extend extends a chain by adding the specified chain
+// as the last one in the connection flow.
+//
+// Extend returns a new chain, leaving the original one untouched.
+func (c Chain) Extend(chain Chain) Chain {
+	return c.Append(chain.constructors...)
+}
@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"fmt"
 	"io"
 	"net"
 	"time"
@@ -12,16 +13,23 @@ import (
 type Proxy struct {
 	target           *net.TCPAddr
 	terminationDelay time.Duration
+	// proxyProtocolVersion is the version of the PROXY protocol header sent to the target, 0 if disabled.
+	proxyProtocolVersion int
 }
 
-// NewProxy creates a new Proxy
-func NewProxy(address string, terminationDelay time.Duration) (*Proxy, error) {
+// NewProxy creates a new Proxy.
+// The proxyProtocolVersion is the version of the PROXY protocol header sent to the target, 0 to disable it.
+func NewProxy(address string, terminationDelay time.Duration, proxyProtocolVersion int) (*Proxy, error) {
 	tcpAddr, err := net.ResolveTCPAddr("tcp", address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Proxy{target: tcpAddr, terminationDelay: terminationDelay}, nil
+	if proxyProtocolVersion < 0 || proxyProtocolVersion > 2 {
+		return nil, fmt.Errorf("unsupported PROXY protocol version: %d", proxyProtocolVersion)
+	}
+
+	return &Proxy{target: tcpAddr, terminationDelay: terminationDelay, proxyProtocolVersion: proxyProtocolVersion}, nil
 }
 
 // ServeTCP forwards the connection to a service
@@ -40,6 +48,13 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
+	if p.proxyProtocolVersion > 0 {
+		if err := WriteProxyProtocolHeader(connBackend, p.proxyProtocolVersion, conn.RemoteAddr(), conn.LocalAddr()); err != nil {
+			log.Errorf("Error while writing the PROXY protocol header to backend: %v", err)
+			return
+		}
+	}
+
 	errChan := make(chan error)
 	go p.connCopy(conn, connBackend, errChan)
 	go p.connCopy(connBackend, conn, errChan)
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"testing"
 	"time"
 
+	proxyprotocol "github.com/c0va23/go-proxyprotocol"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,7 +49,7 @@ func TestCloseWrite(t *testing.T) {
 	_, port, err := net.SplitHostPort(backendListener.Addr().String())
 	require.NoError(t, err)
 
-	proxy, err := NewProxy(":"+port, 10*time.Millisecond)
+	proxy, err := NewProxy(":"+port, 10*time.Millisecond, 0)
 	require.NoError(t, err)
 
 	proxyListener, err := net.Listen("tcp", ":0")
@@ -79,3 +82,69 @@ func TestCloseWrite(t *testing.T) {
 	require.Equal(t, int64(4), n)
 	require.Equal(t, "PONG", buffer.String())
 }
+
+func TestProxyProtocol(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		version int
+	}{
+		{
+			desc:    "PROXY protocol v1",
+			version: 1,
+		},
+		{
+			desc:    "PROXY protocol v2",
+			version: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer backendListener.Close()
+
+			// The backend answers with the client address it sees, as announced by the PROXY protocol header.
+			go func() {
+				listener := proxyprotocol.NewDefaultListener(backendListener)
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				_, _ = conn.Write([]byte(conn.RemoteAddr().String()))
+			}()
+
+			proxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, test.version)
+			require.NoError(t, err)
+
+			proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer proxyListener.Close()
+
+			go func() {
+				conn, err := proxyListener.Accept()
+				if err != nil {
+					return
+				}
+				proxy.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", proxyListener.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			answer, err := ioutil.ReadAll(conn)
+			require.NoError(t, err)
+
+			assert.Equal(t, conn.LocalAddr().String(), string(answer))
+		})
+	}
+}
+
+func TestNewProxyUnsupportedProxyProtocolVersion(t *testing.T) {
+	_, err := NewProxy("127.0.0.1:80", 10*time.Millisecond, 3)
+	assert.Error(t, err)
+}
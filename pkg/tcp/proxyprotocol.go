@@ -0,0 +1,99 @@
+package tcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// proxyProtocolV2Signature is the signature starting every PROXY protocol v2 header.
+var proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+const (
+	proxyProtocolV2Local = 0x20
+	proxyProtocolV2Proxy = 0x21
+
+	proxyProtocolV2Unspec   = 0x00
+	proxyProtocolV2TCPOver4 = 0x11
+	proxyProtocolV2TCPOver6 = 0x21
+)
+
+// WriteProxyProtocolHeader writes to w the PROXY protocol header of the given version (1 or 2),
+// announcing a connection from src to dst.
+// When the addresses are unknown (e.g. a health check), or not TCP addresses of the same family,
+// an UNKNOWN (v1) or LOCAL (v2) header is written, i.e. the receiver uses the real connection endpoints.
+func WriteProxyProtocolHeader(w io.Writer, version int, src, dst net.Addr) error {
+	header, err := proxyProtocolHeader(version, src, dst)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(header)
+	return err
+}
+
+func proxyProtocolHeader(version int, src, dst net.Addr) ([]byte, error) {
+	srcAddr, dstAddr, isIPv4, ok := proxyProtocolAddrs(src, dst)
+
+	switch version {
+	case 1:
+		if !ok {
+			return []byte("PROXY UNKNOWN\r\n"), nil
+		}
+
+		family := "TCP6"
+		if isIPv4 {
+			family = "TCP4"
+		}
+
+		return []byte(fmt.Sprintf("PROXY %s %s %s %d %d\r\n", family, srcAddr.IP, dstAddr.IP, srcAddr.Port, dstAddr.Port)), nil
+
+	case 2:
+		header := bytes.NewBuffer(append([]byte{}, proxyProtocolV2Signature...))
+
+		if !ok {
+			header.Write([]byte{proxyProtocolV2Local, proxyProtocolV2Unspec, 0, 0})
+			return header.Bytes(), nil
+		}
+
+		family := byte(proxyProtocolV2TCPOver6)
+		srcIP, dstIP := srcAddr.IP.To16(), dstAddr.IP.To16()
+		if isIPv4 {
+			family = proxyProtocolV2TCPOver4
+			srcIP, dstIP = srcAddr.IP.To4(), dstAddr.IP.To4()
+		}
+
+		header.Write([]byte{proxyProtocolV2Proxy, family})
+		_ = binary.Write(header, binary.BigEndian, uint16(2*len(srcIP)+4))
+		header.Write(srcIP)
+		header.Write(dstIP)
+		_ = binary.Write(header, binary.BigEndian, uint16(srcAddr.Port))
+		_ = binary.Write(header, binary.BigEndian, uint16(dstAddr.Port))
+
+		return header.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported PROXY protocol version: %d", version)
+	}
+}
+
+// proxyProtocolAddrs returns the given addresses as TCP addresses,
+// and whether they can be both represented as IPv4 addresses.
+// ok is false if they are not TCP addresses of the same family.
+func proxyProtocolAddrs(src, dst net.Addr) (srcAddr, dstAddr *net.TCPAddr, isIPv4, ok bool) {
+	srcAddr, srcOK := src.(*net.TCPAddr)
+	dstAddr, dstOK := dst.(*net.TCPAddr)
+	if !srcOK || !dstOK || srcAddr == nil || dstAddr == nil || srcAddr.IP == nil || dstAddr.IP == nil {
+		return nil, nil, false, false
+	}
+
+	srcIsIPv4 := srcAddr.IP.To4() != nil
+	dstIsIPv4 := dstAddr.IP.To4() != nil
+	if srcIsIPv4 != dstIsIPv4 {
+		return nil, nil, false, false
+	}
+
+	return srcAddr, dstAddr, srcIsIPv4, true
+}
@@ -0,0 +1,92 @@
+package tcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyProtocolHeader(t *testing.T) {
+	v2Signature := "\r\n\r\n\x00\r\nQUIT\n"
+
+	testCases := []struct {
+		desc          string
+		version       int
+		src           net.Addr
+		dst           net.Addr
+		expected      string
+		expectedError bool
+	}{
+		{
+			desc:     "v1 IPv4",
+			version:  1,
+			src:      &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+			dst:      &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443},
+			expected: "PROXY TCP4 10.0.0.1 10.0.0.2 1234 443\r\n",
+		},
+		{
+			desc:     "v1 IPv6",
+			version:  1,
+			src:      &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 1234},
+			dst:      &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 443},
+			expected: "PROXY TCP6 2001:db8::1 2001:db8::2 1234 443\r\n",
+		},
+		{
+			desc:     "v1 unknown addresses",
+			version:  1,
+			expected: "PROXY UNKNOWN\r\n",
+		},
+		{
+			desc:     "v1 mixed address families",
+			version:  1,
+			src:      &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+			dst:      &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 443},
+			expected: "PROXY UNKNOWN\r\n",
+		},
+		{
+			desc:     "v2 IPv4",
+			version:  2,
+			src:      &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+			dst:      &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443},
+			expected: v2Signature + "\x21\x11\x00\x0c" + "\x0a\x00\x00\x01" + "\x0a\x00\x00\x02" + "\x04\xd2" + "\x01\xbb",
+		},
+		{
+			desc:    "v2 IPv6",
+			version: 2,
+			src:     &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 1234},
+			dst:     &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 443},
+			expected: v2Signature + "\x21\x21\x00\x24" +
+				"\x20\x01\x0d\xb8\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01" +
+				"\x20\x01\x0d\xb8\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02" +
+				"\x04\xd2" + "\x01\xbb",
+		},
+		{
+			desc:     "v2 unknown addresses",
+			version:  2,
+			expected: v2Signature + "\x20\x00\x00\x00",
+		},
+		{
+			desc:          "unsupported version",
+			version:       3,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header, err := proxyProtocolHeader(test.version, test.src, test.dst)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(header))
+		})
+	}
+}
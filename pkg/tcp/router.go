@@ -7,15 +7,37 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/log"
 )
 
+// ConnData holds the data of a connection used to match the routing rules.
+type ConnData struct {
+	// ServerName is the SNI server name, empty for non-TLS connections.
+	ServerName string
+	// RemoteIP is the IP of the client.
+	RemoteIP string
+	// ALPNProtos are the protocols advertised by the client through ALPN.
+	ALPNProtos []string
+}
+
+// Matcher reports whether a connection matches a routing rule.
+type Matcher func(ConnData) bool
+
+type matcherRoute struct {
+	matcher  Matcher
+	priority int
+	tls      bool
+	handler  Handler
+}
+
 // Router is a TCP router
 type Router struct {
 	routingTable      map[string]Handler
+	matcherRoutes     []matcherRoute // sorted by decreasing priority
 	httpForwarder     Handler
 	httpsForwarder    Handler
 	httpHandler       http.Handler
@@ -29,13 +51,18 @@ type Router struct {
 func (r *Router) ServeTCP(conn WriteCloser) {
 	// FIXME -- Check if ProxyProtocol changes the first bytes of the request
 
-	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 {
-		r.catchAllNoTLS.ServeTCP(conn)
-		return
+	if len(r.routingTable) == 0 && !r.hasMatcherRoutes(true) {
+		// No TLS routes, the connection can be routed without peeking its first bytes:
+		// by the catch-all route, which takes all the connections,
+		// or by the matcher routes, when the TLS connections cannot be forwarded to an HTTPS handler either.
+		if r.catchAllNoTLS != nil || r.httpsForwarder == nil && r.hasMatcherRoutes(false) {
+			r.serveNoTLS(conn, ConnData{RemoteIP: remoteIP(conn)})
+			return
+		}
 	}
 
 	br := bufio.NewReader(conn)
-	serverName, tls, peeked, err := clientHelloServerName(br)
+	hello, err := clientHelloInfo(br)
 	if err != nil {
 		conn.Close()
 		return
@@ -52,40 +79,79 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 		log.WithoutContext().Errorf("Error while setting write deadline: %v", err)
 	}
 
-	if !tls {
-		switch {
-		case r.catchAllNoTLS != nil:
-			r.catchAllNoTLS.ServeTCP(r.GetConn(conn, peeked))
-		case r.httpForwarder != nil:
-			r.httpForwarder.ServeTCP(r.GetConn(conn, peeked))
-		default:
-			conn.Close()
-		}
+	data := ConnData{
+		ServerName: strings.ToLower(hello.serverName),
+		RemoteIP:   remoteIP(conn),
+		ALPNProtos: hello.protos,
+	}
+
+	if !hello.isTLS {
+		r.serveNoTLS(r.GetConn(conn, hello.peeked), data)
+		return
+	}
+
+	if target := r.match(data, true); target != nil {
+		target.ServeTCP(r.GetConn(conn, hello.peeked))
 		return
 	}
 
 	// FIXME Optimize and test the routing table before helloServerName
-	serverName = strings.ToLower(serverName)
-	if r.routingTable != nil && serverName != "" {
-		if target, ok := r.routingTable[serverName]; ok {
-			target.ServeTCP(r.GetConn(conn, peeked))
+	if r.routingTable != nil && data.ServerName != "" {
+		if target, ok := r.routingTable[data.ServerName]; ok {
+			target.ServeTCP(r.GetConn(conn, hello.peeked))
 			return
 		}
 	}
 
 	// FIXME Needs tests
 	if target, ok := r.routingTable["*"]; ok {
-		target.ServeTCP(r.GetConn(conn, peeked))
+		target.ServeTCP(r.GetConn(conn, hello.peeked))
 		return
 	}
 
 	if r.httpsForwarder != nil {
-		r.httpsForwarder.ServeTCP(r.GetConn(conn, peeked))
+		r.httpsForwarder.ServeTCP(r.GetConn(conn, hello.peeked))
 	} else {
 		conn.Close()
 	}
 }
 
+// serveNoTLS forwards a non-TLS connection to the right TCP/HTTP handler.
+func (r *Router) serveNoTLS(conn WriteCloser, data ConnData) {
+	if target := r.match(data, false); target != nil {
+		target.ServeTCP(conn)
+		return
+	}
+
+	switch {
+	case r.catchAllNoTLS != nil:
+		r.catchAllNoTLS.ServeTCP(conn)
+	case r.httpForwarder != nil:
+		r.httpForwarder.ServeTCP(conn)
+	default:
+		conn.Close()
+	}
+}
+
+// match returns the handler of the matcher route with the highest priority matching the connection, or nil.
+func (r *Router) match(data ConnData, isTLS bool) Handler {
+	for _, route := range r.matcherRoutes {
+		if route.tls == isTLS && route.matcher(data) {
+			return route.handler
+		}
+	}
+	return nil
+}
+
+func (r *Router) hasMatcherRoutes(isTLS bool) bool {
+	for _, route := range r.matcherRoutes {
+		if route.tls == isTLS {
+			return true
+		}
+	}
+	return false
+}
+
 // AddRoute defines a handler for a given sniHost (* is the only valid option)
 func (r *Router) AddRoute(sniHost string, target Handler) {
 	if r.routingTable == nil {
@@ -102,6 +168,33 @@ func (r *Router) AddRouteTLS(sniHost string, target Handler, config *tls.Config)
 	})
 }
 
+// AddRouteMatcher defines a handler for the TLS connections matching the given matcher.
+// The matcher routes are evaluated by decreasing priority, before the sniHost ones.
+func (r *Router) AddRouteMatcher(matcher Matcher, priority int, target Handler) {
+	r.addMatcherRoute(matcherRoute{matcher: matcher, priority: priority, tls: true, handler: target})
+}
+
+// AddRouteMatcherTLS defines a handler for the TLS connections matching the given matcher and sets the matching tlsConfig
+func (r *Router) AddRouteMatcherTLS(matcher Matcher, priority int, target Handler, config *tls.Config) {
+	r.AddRouteMatcher(matcher, priority, &TLSHandler{
+		Next:   target,
+		Config: config,
+	})
+}
+
+// AddRouteMatcherNoTLS defines a handler for the non-TLS connections matching the given matcher.
+// The matcher routes are evaluated by decreasing priority, before the catch-all one.
+func (r *Router) AddRouteMatcherNoTLS(matcher Matcher, priority int, target Handler) {
+	r.addMatcherRoute(matcherRoute{matcher: matcher, priority: priority, handler: target})
+}
+
+func (r *Router) addMatcherRoute(route matcherRoute) {
+	r.matcherRoutes = append(r.matcherRoutes, route)
+	sort.SliceStable(r.matcherRoutes, func(i, j int) bool {
+		return r.matcherRoutes[i].priority > r.matcherRoutes[j].priority
+	})
+}
+
 // AddRouteHTTPTLS defines a handler for a given sniHost and sets the matching tlsConfig
 func (r *Router) AddRouteHTTPTLS(sniHost string, config *tls.Config) {
 	if r.hostHTTPTLSConfig == nil {
@@ -190,17 +283,25 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
-// clientHelloServerName returns the SNI server name inside the TLS ClientHello,
+// clientHello holds the information sniffed from the beginning of a connection.
+type clientHello struct {
+	serverName string   // SNI server name
+	protos     []string // ALPN protocols
+	isTLS      bool
+	peeked     string
+}
+
+// clientHelloInfo returns the SNI server name and the ALPN protocols inside the TLS ClientHello,
 // without consuming any bytes from br.
-// On any error, the empty string is returned.
-func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
+// On any error, the empty string is returned as server name.
+func clientHelloInfo(br *bufio.Reader) (*clientHello, error) {
 	hdr, err := br.Peek(1)
 	if err != nil {
 		opErr, ok := err.(*net.OpError)
 		if err != io.EOF && (!ok || !opErr.Timeout()) {
 			log.WithoutContext().Debugf("Error while Peeking first byte: %s", err)
 		}
-		return "", false, "", err
+		return nil, err
 	}
 
 	// No valid TLS record has a type of 0x80, however SSLv2 handshakes
@@ -212,35 +313,51 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 	if hdr[0] != recordTypeHandshake {
 		if hdr[0] == recordTypeSSLv2 {
 			// we consider SSLv2 as TLS and it will be refuse by real TLS handshake.
-			return "", true, getPeeked(br), nil
+			return &clientHello{isTLS: true, peeked: getPeeked(br)}, nil
 		}
-		return "", false, getPeeked(br), nil // Not TLS.
+		return &clientHello{peeked: getPeeked(br)}, nil // Not TLS.
 	}
 
 	const recordHeaderLen = 5
 	hdr, err = br.Peek(recordHeaderLen)
 	if err != nil {
 		log.Errorf("Error while Peeking hello: %s", err)
-		return "", false, getPeeked(br), nil
+		return &clientHello{peeked: getPeeked(br)}, nil
 	}
 
 	recLen := int(hdr[3])<<8 | int(hdr[4]) // ignoring version in hdr[1:3]
 	helloBytes, err := br.Peek(recordHeaderLen + recLen)
 	if err != nil {
 		log.Errorf("Error while Hello: %s", err)
-		return "", true, getPeeked(br), nil
+		return &clientHello{isTLS: true, peeked: getPeeked(br)}, nil
 	}
 
-	sni := ""
+	hello := &clientHello{isTLS: true}
 	server := tls.Server(sniSniffConn{r: bytes.NewReader(helloBytes)}, &tls.Config{
-		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
-			sni = hello.ServerName
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			hello.serverName = info.ServerName
+			hello.protos = info.SupportedProtos
 			return nil, nil
 		},
 	})
 	_ = server.Handshake()
 
-	return sni, true, getPeeked(br), nil
+	hello.peeked = getPeeked(br)
+	return hello, nil
+}
+
+// remoteIP returns the IP of the remote address of the connection.
+func remoteIP(conn net.Conn) string {
+	addr := conn.RemoteAddr()
+	if addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
 }
 
 func getPeeked(br *bufio.Reader) string {
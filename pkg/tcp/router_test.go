@@ -0,0 +1,174 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_ServeTCPMatchers(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		tls        bool
+		alpnProtos []string
+		expected   string
+	}{
+		{
+			desc:     "non-TLS connection matching a matcher route",
+			expected: "local",
+		},
+		{
+			desc:       "TLS connection matching a matcher route",
+			tls:        true,
+			alpnProtos: []string{"h2", "http/1.1"},
+			expected:   "h2",
+		},
+		{
+			desc:       "TLS connection not matching any matcher route",
+			tls:        true,
+			alpnProtos: []string{"http/1.1"},
+			expected:   "passthrough",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			served := make(chan string, 1)
+			handler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					served <- name
+					_ = conn.Close()
+				})
+			}
+
+			router := &Router{}
+			router.AddRoute("*", handler("passthrough"))
+			router.AddCatchAllNoTLS(handler("catchall"))
+			router.AddRouteMatcher(func(data ConnData) bool {
+				return len(data.ALPNProtos) > 0 && data.ALPNProtos[0] == "h2"
+			}, 10, handler("h2"))
+			router.AddRouteMatcherNoTLS(func(data ConnData) bool {
+				return data.RemoteIP == "127.0.0.1"
+			}, 10, handler("local"))
+			router.AddRouteMatcherNoTLS(func(data ConnData) bool {
+				return false
+			}, 20, handler("never"))
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				router.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			if test.tls {
+				tlsConn := tls.Client(conn, &tls.Config{
+					ServerName:         "foo.bar",
+					NextProtos:         test.alpnProtos,
+					InsecureSkipVerify: true,
+				})
+				go func() { _ = tlsConn.Handshake() }()
+			} else {
+				_, err = conn.Write([]byte("hello"))
+				require.NoError(t, err)
+			}
+
+			select {
+			case name := <-served:
+				assert.Equal(t, test.expected, name)
+			case <-time.After(5 * time.Second):
+				t.Fatal("connection not served")
+			}
+		})
+	}
+}
+
+func TestRouter_ServeTCPMatchersNoTLSWithHTTPS(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		tls      bool
+		expected string
+	}{
+		{
+			desc:     "non-TLS connection matching a matcher route",
+			expected: "local",
+		},
+		{
+			desc:     "TLS connection",
+			tls:      true,
+			expected: "https",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			served := make(chan string, 1)
+			handler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					served <- name
+					_ = conn.Close()
+				})
+			}
+
+			router := &Router{}
+			router.AddRouteMatcherNoTLS(func(data ConnData) bool {
+				return data.RemoteIP == "127.0.0.1"
+			}, 10, handler("local"))
+			router.HTTPForwarder(handler("http"))
+			router.HTTPSForwarder(handler("https"))
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				router.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			if test.tls {
+				tlsConn := tls.Client(conn, &tls.Config{
+					ServerName:         "foo.bar",
+					InsecureSkipVerify: true,
+				})
+				go func() { _ = tlsConn.Handshake() }()
+			} else {
+				_, err = conn.Write([]byte("hello"))
+				require.NoError(t, err)
+			}
+
+			select {
+			case name := <-served:
+				assert.Equal(t, test.expected, name)
+			case <-time.After(5 * time.Second):
+				t.Fatal("connection not served")
+			}
+		})
+	}
+}
@@ -129,3 +129,44 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+type writerHandler struct {
+	name string
+}
+
+func (h *writerHandler) ServeTCP(conn WriteCloser) {
+	_, _ = conn.Write([]byte(h.name))
+}
+
+func TestLoadBalancing_RemoveServer(t *testing.T) {
+	h1 := &writerHandler{name: "h1"}
+	h2 := &writerHandler{name: "h2"}
+	h3 := &writerHandler{name: "h3"}
+
+	balancer := NewWRRLoadBalancer()
+	balancer.AddServer(h1)
+	balancer.AddServer(h2)
+	balancer.AddServer(h3)
+
+	conn := &fakeConn{call: make(map[string]int)}
+	balancer.ServeTCP(conn)
+	balancer.ServeTCP(conn)
+	assert.Equal(t, map[string]int{"h1": 1, "h2": 1}, conn.call)
+
+	require.NoError(t, balancer.RemoveServer(h2))
+	assert.Error(t, balancer.RemoveServer(h2))
+
+	conn = &fakeConn{call: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h1": 2, "h3": 2}, conn.call)
+
+	balancer.AddServer(h2)
+
+	conn = &fakeConn{call: make(map[string]int)}
+	for i := 0; i < 6; i++ {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 2, "h3": 2}, conn.call)
+}
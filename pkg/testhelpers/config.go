@@ -95,6 +95,28 @@ func WithMiddleware(name string, opts ...func(*dynamic.Middleware)) func(*dynami
 	}
 }
 
+// WithServersTransports is a helper to create a configuration.
+func WithServersTransports(opts ...func(*dynamic.ServersTransport) string) func(*dynamic.HTTPConfiguration) {
+	return func(c *dynamic.HTTPConfiguration) {
+		c.ServersTransports = make(map[string]*dynamic.ServersTransport)
+		for _, opt := range opts {
+			st := &dynamic.ServersTransport{}
+			name := opt(st)
+			c.ServersTransports[name] = st
+		}
+	}
+}
+
+// WithServersTransport is a helper to create a configuration.
+func WithServersTransport(name string, opts ...func(*dynamic.ServersTransport)) func(*dynamic.ServersTransport) string {
+	return func(st *dynamic.ServersTransport) string {
+		for _, opt := range opts {
+			opt(st)
+		}
+		return name
+	}
+}
+
 // WithBasicAuth is a helper to create a configuration.
 func WithBasicAuth(auth *dynamic.BasicAuth) func(*dynamic.Middleware) {
 	return func(r *dynamic.Middleware) {
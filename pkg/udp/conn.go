@@ -12,10 +12,6 @@ const receiveMTU = 8192
 
 const closeRetryInterval = 500 * time.Millisecond
 
-// connTimeout determines how long to wait on an idle session,
-// before releasing all resources related to that session.
-const connTimeout = time.Second * 3
-
 var errClosedListener = errors.New("udp: listener closed")
 
 // Listener augments a session-oriented Listener over a UDP PacketConn.
@@ -29,10 +25,19 @@ type Listener struct {
 	accepting bool
 
 	acceptCh chan *Conn // no need for a Once, already indirectly guarded by accepting.
+
+	// timeout defines how long to wait on an idle session,
+	// before releasing its related resources.
+	timeout time.Duration
 }
 
 // Listen creates a new listener.
-func Listen(network string, laddr *net.UDPAddr) (*Listener, error) {
+// timeout defines how long to wait on an idle session, before releasing all resources related to that session.
+func Listen(network string, laddr *net.UDPAddr, timeout time.Duration) (*Listener, error) {
+	if timeout <= 0 {
+		return nil, errors.New("timeout should be greater than zero")
+	}
+
 	conn, err := net.ListenUDP(network, laddr)
 	if err != nil {
 		return nil, err
@@ -43,6 +48,7 @@ func Listen(network string, laddr *net.UDPAddr) (*Listener, error) {
 		acceptCh:  make(chan *Conn),
 		conns:     make(map[string]*Conn),
 		accepting: true,
+		timeout:   timeout,
 	}
 
 	go l.readLoop()
@@ -175,7 +181,8 @@ func (l *Listener) newConn(rAddr net.Addr) *Conn {
 		readCh:    make(chan []byte),
 		sizeCh:    make(chan int),
 		doneCh:    make(chan struct{}),
-		timer:     time.NewTimer(connTimeout),
+		timer:     time.NewTimer(l.timeout),
+		timeout:   l.timeout,
 	}
 }
 
@@ -190,6 +197,7 @@ type Conn struct {
 	msgs      [][]byte    // to store data from listener, to be consumed by Reads
 
 	timer    *time.Timer // for timeouts
+	timeout  time.Duration
 	doneOnce sync.Once
 	doneCh   chan struct{}
 }
@@ -230,7 +238,7 @@ func (c *Conn) Read(p []byte) (int, error) {
 	select {
 	case c.readCh <- p:
 		n := <-c.sizeCh
-		c.timer.Reset(connTimeout)
+		c.timer.Reset(c.timeout)
 		return n, nil
 	case <-c.doneCh:
 		return 0, io.EOF
@@ -244,7 +252,7 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 		return 0, io.EOF
 	}
 
-	c.timer.Reset(connTimeout)
+	c.timer.Reset(c.timeout)
 	return l.pConn.WriteTo(p, c.rAddr)
 }
 
@@ -1,6 +1,7 @@
 package udp
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -29,15 +30,11 @@ func NewWRRLoadBalancer() *WRRLoadBalancer {
 
 // ServeUDP forwards the connection to the right service
 func (b *WRRLoadBalancer) ServeUDP(conn *Conn) {
-	if len(b.servers) == 0 {
-		log.WithoutContext().Error("no available server")
-		return
-	}
-
 	next, err := b.next()
 	if err != nil {
 		log.WithoutContext().Errorf("Error during load balancing: %v", err)
 		conn.Close()
+		return
 	}
 	next.ServeUDP(conn)
 }
@@ -54,9 +51,32 @@ func (b *WRRLoadBalancer) AddWeightedServer(serverHandler Handler, weight *int)
 	if weight != nil {
 		w = *weight
 	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
 	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
 }
 
+// RemoveServer removes the given server from the list.
+// The server is identified by its handler, which must therefore be comparable (e.g. a *Proxy).
+func (b *WRRLoadBalancer) RemoveServer(serverHandler Handler) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for i, srv := range b.servers {
+		if srv.Handler == serverHandler {
+			b.servers = append(b.servers[:i], b.servers[i+1:]...)
+			if b.index >= i {
+				b.index--
+			}
+			return nil
+		}
+	}
+
+	return errors.New("server not found")
+}
+
 func (b *WRRLoadBalancer) maxWeight() int {
 	max := -1
 	for _, s := range b.servers {
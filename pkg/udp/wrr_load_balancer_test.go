@@ -0,0 +1,47 @@
+package udp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorderHandler struct {
+	name  string
+	calls map[string]int
+}
+
+func (h *recorderHandler) ServeUDP(conn *Conn) {
+	h.calls[h.name]++
+}
+
+func TestWRRLoadBalancer_RemoveServer(t *testing.T) {
+	calls := make(map[string]int)
+	h1 := &recorderHandler{name: "h1", calls: calls}
+	h2 := &recorderHandler{name: "h2", calls: calls}
+
+	balancer := NewWRRLoadBalancer()
+	balancer.AddServer(h1)
+	balancer.AddServer(h2)
+
+	for i := 0; i < 4; i++ {
+		balancer.ServeUDP(&Conn{})
+	}
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 2}, calls)
+
+	require.NoError(t, balancer.RemoveServer(h1))
+	assert.Error(t, balancer.RemoveServer(h1))
+
+	for i := 0; i < 2; i++ {
+		balancer.ServeUDP(&Conn{})
+	}
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 4}, calls)
+
+	balancer.AddServer(h1)
+
+	for i := 0; i < 2; i++ {
+		balancer.ServeUDP(&Conn{})
+	}
+	assert.Equal(t, map[string]int{"h1": 3, "h2": 5}, calls)
+}